@@ -0,0 +1,33 @@
+// Package account
+package account
+
+import (
+	"context"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/bootstrap"
+	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/logger"
+)
+
+// PurgeDeletedAccounts hard deletes what is left of the accounts deleted longer ago than the configured delay
+func PurgeDeletedAccounts(ctx context.Context) {
+	cfg := appctx.NewConfig()
+
+	days := cfg.Auth.AccountPurgeAfterDay
+	if days <= 0 {
+		days = consts.AccountPurgeAfterDayDefault
+	}
+
+	db := bootstrap.RegistryPostgreSQLMasterSlave(cfg.WriteDB, cfg.ReadDB, cfg.App.Timezone)
+	userRepository := repositories.NewUserRepository(db)
+
+	purged, err := userRepository.Purge(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		logger.Fatal(logger.MessageFormat("purge deleted accounts error: %v", err))
+	}
+
+	logger.Info(logger.MessageFormat("purged %d accounts deleted more than %d days ago", purged, days))
+}
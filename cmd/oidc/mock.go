@@ -0,0 +1,49 @@
+// Package oidc
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"sharefood/pkg/logger"
+	"sharefood/pkg/oidc/oidctest"
+)
+
+// MockOptions settings of the local identity provider
+type MockOptions struct {
+	Addr         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Identity     oidctest.Identity
+}
+
+// StartMock runs a local openid connect identity provider that signs everyone in as the given identity
+func StartMock(ctx context.Context, opt MockOptions) {
+	mock, err := oidctest.NewMockProvider(opt.ClientID, opt.ClientSecret)
+	if err != nil {
+		logger.Fatal(logger.MessageFormat("mock identity provider error: %v", err))
+	}
+
+	mock.Issuer = opt.Issuer
+	if opt.Identity.Subject != "" {
+		mock.Identity = opt.Identity
+	}
+
+	srv := &http.Server{
+		Addr:              opt.Addr,
+		Handler:           mock,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	logger.Info(logger.MessageFormat("mock identity provider %s listening on %s", mock.Issuer, opt.Addr))
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Fatal(logger.MessageFormat("mock identity provider error: %v", err))
+	}
+}
@@ -0,0 +1,30 @@
+// Package openapi
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/router"
+	"sharefood/pkg/logger"
+)
+
+// Generate writes the OpenAPI document of the http routes to path, the routes are built without
+// connecting to the database, redis or any provider
+func Generate(path string) {
+	cfg := appctx.NewConfig()
+
+	doc := router.NewRouter(cfg).Spec()
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		logger.Fatal(logger.MessageFormat("encode openapi document error: %v", err))
+	}
+
+	if err := os.WriteFile(path, append(b, '\n'), 0644); err != nil {
+		logger.Fatal(logger.MessageFormat("write openapi document error: %v", err))
+	}
+
+	logger.Info(logger.MessageFormat("openapi document of %d paths written to %s", len(doc.Paths), path))
+}
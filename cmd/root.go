@@ -9,9 +9,12 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"sharefood/cmd/account"
 	"sharefood/cmd/genx"
 	"sharefood/cmd/http"
 	"sharefood/cmd/migration"
+	"sharefood/cmd/oidc"
+	"sharefood/cmd/openapi"
 	"sharefood/pkg/logger"
 )
 
@@ -22,7 +25,7 @@ func Start() {
 	logger.SetJSONFormatter()
 	ctx, cancel := context.WithCancel(context.Background())
 
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-quit
@@ -43,6 +46,35 @@ func Start() {
 	migrateCmd.Flags().BoolP("verbose", "", false, "enable verbose mode")
 	migrateCmd.Flags().BoolP("guide", "", false, "print help")
 
+	mockOIDC := oidc.MockOptions{}
+	mockOIDCCmd := &cobra.Command{
+		Use:   "oidc:mock",
+		Short: "Run a local OpenID Connect identity provider",
+		Run: func(c *cobra.Command, args []string) {
+			oidc.StartMock(ctx, mockOIDC)
+		},
+	}
+
+	mockOIDCCmd.Flags().StringVar(&mockOIDC.Addr, "addr", ":9090", "listen address")
+	mockOIDCCmd.Flags().StringVar(&mockOIDC.Issuer, "issuer", "http://localhost:9090", "issuer url, as configured for the provider")
+	mockOIDCCmd.Flags().StringVar(&mockOIDC.ClientID, "client-id", "sharefood", "accepted client id")
+	mockOIDCCmd.Flags().StringVar(&mockOIDC.ClientSecret, "client-secret", "secret", "accepted client secret")
+	mockOIDCCmd.Flags().StringVar(&mockOIDC.Identity.Subject, "subject", "mock-subject", "subject of the signed in identity")
+	mockOIDCCmd.Flags().StringVar(&mockOIDC.Identity.Email, "email", "jhon.doe@mail.com", "email of the signed in identity")
+	mockOIDCCmd.Flags().BoolVar(&mockOIDC.Identity.EmailVerified, "email-verified", true, "whether the email is verified")
+	mockOIDCCmd.Flags().StringVar(&mockOIDC.Identity.Name, "name", "jhon doe", "name of the signed in identity")
+
+	openAPIOut := ""
+	genOpenAPICmd := &cobra.Command{
+		Use:   "gen:openapi",
+		Short: "Write the OpenAPI document of the http routes",
+		Run: func(c *cobra.Command, args []string) {
+			openapi.Generate(openAPIOut)
+		},
+	}
+
+	genOpenAPICmd.Flags().StringVar(&openAPIOut, "out", "openapi.json", "file the document is written to")
+
 	cmd := []*cobra.Command{
 		{
 			Use:   "http",
@@ -65,7 +97,16 @@ func Start() {
 				genx.GenLogic()
 			},
 		},
+		{
+			Use:   "account:purge",
+			Short: "Hard delete the leftovers of deleted accounts",
+			Run: func(cmd *cobra.Command, args []string) {
+				account.PurgeDeletedAccounts(ctx)
+			},
+		},
 		migrateCmd,
+		mockOIDCCmd,
+		genOpenAPICmd,
 	}
 
 	rootCmd.AddCommand(cmd...)
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sessions_revoked_at TIMESTAMPTZ NULL;
+
+CREATE TABLE IF NOT EXISTS password_resets (
+    id_password_reset UUID NOT NULL,
+    id_user UUID NOT NULL REFERENCES users (id_user),
+    token_hash VARCHAR (64) NOT NULL,
+    expired_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id_password_reset),
+    UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS password_resets_id_user_idx ON password_resets (id_user);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS password_resets;
+ALTER TABLE users DROP COLUMN IF EXISTS sessions_revoked_at;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMPTZ NULL;
+
+CREATE TABLE IF NOT EXISTS phone_verifications (
+    id_phone_verification UUID NOT NULL,
+    id_user UUID NOT NULL REFERENCES users (id_user),
+    phone_number VARCHAR (20) NOT NULL,
+    code_hash VARCHAR (64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expired_at TIMESTAMPTZ NOT NULL,
+    verified_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id_phone_verification)
+);
+
+CREATE INDEX IF NOT EXISTS phone_verifications_id_user_idx ON phone_verifications (id_user, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS phone_verifications;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bio VARCHAR (500) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pickup_address VARCHAR (255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pickup_latitude VARCHAR (20) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pickup_longitude VARCHAR (20) NOT NULL DEFAULT '';
+UPDATE users SET image_url = '' WHERE image_url IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS pickup_longitude;
+ALTER TABLE users DROP COLUMN IF EXISTS pickup_latitude;
+ALTER TABLE users DROP COLUMN IF EXISTS pickup_address;
+ALTER TABLE users DROP COLUMN IF EXISTS bio;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_roles (
+    id_user UUID NOT NULL REFERENCES users (id_user),
+    role VARCHAR (32) NOT NULL,
+    granted_by UUID NULL REFERENCES users (id_user),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id_user, role)
+);
+
+-- everyone could share and request food before roles existed
+INSERT INTO user_roles (id_user, role)
+SELECT id_user, r.role FROM users CROSS JOIN (VALUES ('receiver'), ('giver')) AS r (role)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_roles (id_user, role)
+SELECT id_user, 'admin' FROM users WHERE is_admin
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE users SET is_admin = TRUE WHERE id_user IN (SELECT id_user FROM user_roles WHERE role = 'admin');
+DROP TABLE IF EXISTS user_roles;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS organizations (
+    id_organization UUID NOT NULL,
+    name VARCHAR (100) NOT NULL,
+    kind VARCHAR (32) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ NULL,
+    PRIMARY KEY (id_organization)
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    id_organization UUID NOT NULL REFERENCES organizations (id_organization),
+    id_user UUID NOT NULL REFERENCES users (id_user),
+    role VARCHAR (16) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id_organization, id_user)
+);
+
+CREATE INDEX IF NOT EXISTS organization_members_id_user_idx ON organization_members (id_user);
+
+ALTER TABLE foods ADD COLUMN IF NOT EXISTS id_organization UUID NULL REFERENCES organizations (id_organization);
+CREATE INDEX IF NOT EXISTS foods_id_organization_idx ON foods (id_organization);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS foods_id_organization_idx;
+ALTER TABLE foods DROP COLUMN IF EXISTS id_organization;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+-- +goose StatementEnd
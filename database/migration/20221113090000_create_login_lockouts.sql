@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    id_login_lockout UUID NOT NULL,
+    id_user UUID NULL REFERENCES users (id_user),
+    email VARCHAR (100) NOT NULL DEFAULT '',
+    ip_address VARCHAR (45) NOT NULL,
+    reason VARCHAR (20) NOT NULL,
+    attempts INT NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id_login_lockout)
+);
+
+CREATE INDEX IF NOT EXISTS login_lockouts_created_at_idx ON login_lockouts (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS login_lockouts;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS partner_api_keys (
+    id_api_key UUID NOT NULL,
+    name VARCHAR (100) NOT NULL,
+    id_organization UUID NOT NULL REFERENCES organizations (id_organization),
+    id_user UUID NOT NULL REFERENCES users (id_user),
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    -- secrets are kept encrypted with the app encrypt key, the hmac needs them in clear
+    secret TEXT NOT NULL,
+    previous_secret TEXT NULL,
+    previous_secret_expires_at TIMESTAMPTZ NULL,
+    created_by UUID NOT NULL REFERENCES users (id_user),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    rotated_at TIMESTAMPTZ NULL,
+    revoked_at TIMESTAMPTZ NULL,
+    last_used_at TIMESTAMPTZ NULL,
+    PRIMARY KEY (id_api_key)
+);
+
+CREATE INDEX IF NOT EXISTS partner_api_keys_id_organization_idx ON partner_api_keys (id_organization);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS partner_api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the secret is kept encrypted with the app encrypt key, it is set on enrollment and
+-- only in use once the enrollment is confirmed
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_secret TEXT NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled_at TIMESTAMPTZ NULL;
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id_user UUID NOT NULL REFERENCES users (id_user),
+    code_hash VARCHAR (64) NOT NULL,
+    used_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id_user, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS two_factor_roles (
+    role VARCHAR (32) NOT NULL,
+    enforced_by UUID NOT NULL REFERENCES users (id_user),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (role)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS two_factor_roles;
+DROP TABLE IF EXISTS user_recovery_codes;
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_enabled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_secret;
+-- +goose StatementEnd
@@ -34,16 +34,31 @@ func NewConfig() *Config {
 //
 //go:generate easytags $GOFILE yaml,json
 type Config struct {
-	App     *Common      `yaml:"app" json:"app"`
-	Logger  Logging      `yaml:"logger" json:"logger"`
-	WriteDB *Database    `yaml:"db_write" json:"db_write"`
-	ReadDB  *Database    `yaml:"db_read" json:"read_db"`
-	Redis   *RedisConf   `yaml:"redis" json:"redis"`
-	AWS     AWS          `yaml:"aws" json:"aws"`
-	Kafka   *KafkaConfig `yaml:"kafka" json:"kafka"`
-	APM     APM          `yaml:"apm" json:"apm"`
-	Pubsub  PubSub       `yaml:"pubsub" json:"pubsub"`
-	GCS     GCS          `yaml:"gcs" json:"gcs"`
+	App         *Common      `yaml:"app" json:"app"`
+	Logger      Logging      `yaml:"logger" json:"logger"`
+	WriteDB     *Database    `yaml:"db_write" json:"db_write"`
+	ReadDB      *Database    `yaml:"db_read" json:"read_db"`
+	Redis       *RedisConf   `yaml:"redis" json:"redis"`
+	AWS         AWS          `yaml:"aws" json:"aws"`
+	Kafka       *KafkaConfig `yaml:"kafka" json:"kafka"`
+	APM         APM          `yaml:"apm" json:"apm"`
+	Pubsub      PubSub       `yaml:"pubsub" json:"pubsub"`
+	GCS         GCS          `yaml:"gcs" json:"gcs"`
+	Auth        Auth         `yaml:"auth" json:"auth"`
+	Mailer      Mailer       `yaml:"mailer" json:"mailer"`
+	SMS         SMS          `yaml:"sms" json:"sms"`
+	Storage     Storage      `yaml:"storage" json:"storage"`
+	OIDC        OIDC         `yaml:"oidc" json:"oidc"`
+	JWT         JWT          `yaml:"jwt" json:"jwt"`
+	RateLimit   RateLimit    `yaml:"rate_limit" json:"rate_limit"`
+	Idempotency Idempotency  `yaml:"idempotency" json:"idempotency"`
+	AccessLog   AccessLog    `yaml:"access_log" json:"access_log"`
+	CORS        CORS         `yaml:"cors" json:"cors"`
+	Metrics     Metrics      `yaml:"metrics" json:"metrics"`
+	Compression Compression  `yaml:"compression" json:"compression"`
+	Readiness   Readiness    `yaml:"readiness" json:"readiness"`
+	OpenAPI     OpenAPI      `yaml:"openapi" json:"openapi"`
+	APIVersion  APIVersion   `yaml:"api_version" json:"api_version"`
 }
 
 // Common general config object contract
@@ -59,7 +74,12 @@ type Common struct {
 	WriteTimeoutSecond int    `yaml:"write_timeout_second" json:"write_timeout_second"`
 	DefaultLang        string `yaml:"default_lang" json:"default_lang"`
 	EncryptKey         string `yaml:"encrypt_key" json:"encrypt_key"`
-	JWTSecret          string `yaml:"jwt_secret" json:"jwt_secret"`
+	// TrustedProxies addresses or cidr ranges of the proxies X-Forwarded-For is trusted from
+	TrustedProxies []string `yaml:"trusted_proxies" json:"trusted_proxies"`
+	// MaintenanceAllowedIPs addresses or cidr ranges reaching the service while in maintenance
+	MaintenanceAllowedIPs []string `yaml:"maintenance_allowed_ips" json:"maintenance_allowed_ips"`
+	// MaintenanceRetryAfterSecond hint given to the clients while in maintenance
+	MaintenanceRetryAfterSecond int `yaml:"maintenance_retry_after_second" json:"maintenance_retry_after_second"`
 }
 
 // Database configuration structure
@@ -252,9 +272,16 @@ type TLS struct {
 
 // APM config
 type APM struct {
+	// Address host:port of the OTLP collector
 	Address string `yaml:"address" json:"address"`
 	Enable  bool   `yaml:"enable" json:"enable"`
 	Name    string `yaml:"name" json:"name"`
+	// Exporter otlp (default) or stdout for local development
+	Exporter string `yaml:"exporter" json:"exporter"`
+	// Insecure sends the spans to the collector without tls
+	Insecure bool `yaml:"insecure" json:"insecure"`
+	// SampleRate share of the traces started here which are sampled, defaults to 1
+	SampleRate float64 `yaml:"sample_rate" json:"sample_rate"`
 }
 
 type (
@@ -270,3 +297,211 @@ type (
 		Prefix      string `yaml:"prefix"`
 	}
 )
+
+// Auth config for account security flows
+type Auth struct {
+	// PasswordResetTTLMinute lifetime of a password reset token
+	PasswordResetTTLMinute int `yaml:"password_reset_ttl_minute" json:"password_reset_ttl_minute"`
+	// PasswordResetURL frontend page receiving the reset token as `token` query string
+	PasswordResetURL string `yaml:"password_reset_url" json:"password_reset_url"`
+	// EmailVerificationTTLHour lifetime of an email verification link
+	EmailVerificationTTLHour int `yaml:"email_verification_ttl_hour" json:"email_verification_ttl_hour"`
+	// EmailVerificationURL page receiving the signed token as `token` query string
+	EmailVerificationURL string `yaml:"email_verification_url" json:"email_verification_url"`
+	// PhoneOTPTTLMinute lifetime of a phone verification code
+	PhoneOTPTTLMinute int `yaml:"phone_otp_ttl_minute" json:"phone_otp_ttl_minute"`
+	// PhoneOTPMaxAttempt wrong guesses allowed before a new code must be requested
+	PhoneOTPMaxAttempt int `yaml:"phone_otp_max_attempt" json:"phone_otp_max_attempt"`
+	// PhoneOTPMaxAttemptDaily wrong guesses allowed to a user a day, whatever the codes sent
+	PhoneOTPMaxAttemptDaily int `yaml:"phone_otp_max_attempt_daily" json:"phone_otp_max_attempt_daily"`
+	// VerificationResendCooldownSecond wait before another code or link is sent to a user or a destination
+	VerificationResendCooldownSecond int `yaml:"verification_resend_cooldown_second" json:"verification_resend_cooldown_second"`
+	// VerificationSendMaxDaily codes or links sent a day to a user and to a destination
+	VerificationSendMaxDaily int `yaml:"verification_send_max_daily" json:"verification_send_max_daily"`
+	// LoginMaxAttemptAccount failed logins on one email before it is locked out
+	LoginMaxAttemptAccount int `yaml:"login_max_attempt_account" json:"login_max_attempt_account"`
+	// LoginMaxAttemptIP failed logins from one address before it is locked out
+	LoginMaxAttemptIP int `yaml:"login_max_attempt_ip" json:"login_max_attempt_ip"`
+	// LoginAttemptWindowMinute period the failed logins are counted over
+	LoginAttemptWindowMinute int `yaml:"login_attempt_window_minute" json:"login_attempt_window_minute"`
+	// LoginLockoutMinute lifetime of a lockout
+	LoginLockoutMinute int `yaml:"login_lockout_minute" json:"login_lockout_minute"`
+	// AccountPurgeAfterDay days a deleted account is kept before its leftovers are hard deleted
+	AccountPurgeAfterDay int `yaml:"account_purge_after_day" json:"account_purge_after_day"`
+	// PartnerSignatureMaxSkewSecond how far the timestamp of a partner request may be off the server clock
+	PartnerSignatureMaxSkewSecond int `yaml:"partner_signature_max_skew_second" json:"partner_signature_max_skew_second"`
+	// PartnerKeyRotationGraceHour how long the secret before a rotation keeps being accepted
+	PartnerKeyRotationGraceHour int `yaml:"partner_key_rotation_grace_hour" json:"partner_key_rotation_grace_hour"`
+}
+
+// Mailer config for outgoing email
+type Mailer struct {
+	// Driver possible values: smtp, file, log (defaults to log)
+	Driver    string `yaml:"driver" json:"driver"`
+	From      string `yaml:"from" json:"from"`
+	Host      string `yaml:"host" json:"host"`
+	Port      int    `yaml:"port" json:"port"`
+	User      string `yaml:"user" json:"user"`
+	Password  string `yaml:"password" json:"password"`
+	OutboxDir string `yaml:"outbox_dir" json:"outbox_dir"`
+}
+
+// SMS config for outgoing text message
+type SMS struct {
+	// Driver possible values: log (defaults to log)
+	Driver string `yaml:"driver" json:"driver"`
+}
+
+// Storage config for uploaded files
+type Storage struct {
+	// Driver possible values: file, s3, gcs, noop (defaults to file)
+	Driver string `yaml:"driver" json:"driver"`
+	// Bucket name of the s3/gcs bucket or the directory of file driver
+	Bucket string `yaml:"bucket" json:"bucket"`
+	// PublicURL base url the stored objects are served from
+	PublicURL string `yaml:"public_url" json:"public_url"`
+	// AvatarMaxSizeKB upper bound of an uploaded avatar
+	AvatarMaxSizeKB int `yaml:"avatar_max_size_kb" json:"avatar_max_size_kb"`
+}
+
+// OIDC config for signing in with openid connect identity providers
+type OIDC struct {
+	// TimeoutSecond bounds the calls to the identity providers
+	TimeoutSecond int `yaml:"timeout_second" json:"timeout_second"`
+	// Providers by the name used in the login url
+	Providers map[string]OIDCProvider `yaml:"providers" json:"providers"`
+}
+
+// OIDCProvider settings of an identity provider, the endpoints are discovered from the issuer
+type OIDCProvider struct {
+	Issuer       string   `yaml:"issuer" json:"issuer"`
+	ClientID     string   `yaml:"client_id" json:"client_id"`
+	ClientSecret string   `yaml:"client_secret" json:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url" json:"redirect_url"`
+	Scopes       []string `yaml:"scopes" json:"scopes"`
+}
+
+// JWT config for the asymmetric keys access tokens are signed with
+type JWT struct {
+	// SigningKeyID id of the key new tokens are signed with, the other keys only verify
+	SigningKeyID string `yaml:"signing_key_id" json:"signing_key_id"`
+	// Keys signing and verification keys, keep a rotated out key until its tokens expired
+	Keys []JWTKey `yaml:"keys" json:"keys"`
+}
+
+// JWTKey pem encoded key, inline or read from a file. The signing key needs the private key,
+// a verification key only the public one
+type JWTKey struct {
+	ID             string `yaml:"id" json:"id"`
+	PrivateKey     string `yaml:"private_key" json:"private_key"`
+	PrivateKeyFile string `yaml:"private_key_file" json:"private_key_file"`
+	PublicKey      string `yaml:"public_key" json:"public_key"`
+	PublicKeyFile  string `yaml:"public_key_file" json:"public_key_file"`
+}
+
+// RateLimit config for limiting the calls of the routes
+type RateLimit struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// Driver possible values: redis, memory (defaults to redis). Redis falls back to memory while it cannot be reached
+	Driver string `yaml:"driver" json:"driver"`
+	// Routes limits, a route is matched on its method and path template
+	Routes []RateLimitRoute `yaml:"routes" json:"routes"`
+}
+
+// RateLimitRoute limit of a route
+type RateLimitRoute struct {
+	Method string `yaml:"method" json:"method"`
+	Path   string `yaml:"path" json:"path"`
+	// Key the calls are counted by, possible values: ip, user, api_key (defaults to ip).
+	// Calls without a user or api key are counted by ip
+	Key          string `yaml:"key" json:"key"`
+	Limit        int    `yaml:"limit" json:"limit"`
+	WindowSecond int    `yaml:"window_second" json:"window_second"`
+}
+
+// Idempotency config for replaying the response of a request retried with the same idempotency key
+type Idempotency struct {
+	// TTLHour how long the response of an idempotency key is replayed
+	TTLHour int `yaml:"ttl_hour" json:"ttl_hour"`
+	// LockSecond how long a request is considered in flight at most
+	LockSecond int `yaml:"lock_second" json:"lock_second"`
+	// BodyMaxByte largest body of a request sent with an idempotency key
+	BodyMaxByte int64 `yaml:"body_max_byte" json:"body_max_byte"`
+}
+
+// AccessLog config for the http access log
+type AccessLog struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// SampleRate share of the requests logged between 0 and 1 (defaults to 1), server errors are always logged
+	SampleRate float64 `yaml:"sample_rate" json:"sample_rate"`
+	// Exclude paths never logged
+	Exclude []string `yaml:"exclude" json:"exclude"`
+}
+
+// CORS config for the cross-origin requests of the web frontends
+type CORS struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// AllowedOrigins origins allowed, "*" allows any and "https://*.example.com" any subdomain
+	AllowedOrigins []string `yaml:"allowed_origins" json:"allowed_origins"`
+	// AllowedMethods methods allowed, defaults to GET, HEAD, POST, PUT, PATCH and DELETE
+	AllowedMethods []string `yaml:"allowed_methods" json:"allowed_methods"`
+	// AllowedHeaders request headers allowed, "*" allows any
+	AllowedHeaders []string `yaml:"allowed_headers" json:"allowed_headers"`
+	// ExposedHeaders response headers the page can read
+	ExposedHeaders   []string `yaml:"exposed_headers" json:"exposed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials" json:"allow_credentials"`
+	// MaxAgeSecond how long the browser caches a preflight answer
+	MaxAgeSecond int `yaml:"max_age_second" json:"max_age_second"`
+}
+
+// Metrics config for the prometheus metrics served on /metrics
+type Metrics struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// AllowedIPs addresses or cidr ranges of the scrapers, defaults to loopback
+	AllowedIPs []string `yaml:"allowed_ips" json:"allowed_ips"`
+	// Token bearer token letting a scraper from any other address through, none when empty
+	Token string `yaml:"token" json:"token"`
+}
+
+// Compression config for the compression of the responses negotiated by Accept-Encoding
+type Compression struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// MinSizeByte size from which a response is compressed, defaults to 1024
+	MinSizeByte int `yaml:"min_size_byte" json:"min_size_byte"`
+}
+
+// Readiness config for the readiness probe
+type Readiness struct {
+	// TimeoutSecond each dependency is given to answer, defaults to 2
+	TimeoutSecond int `yaml:"timeout_second" json:"timeout_second"`
+	// Critical dependencies the service is not ready without, among db_write, db_read, redis and kafka
+	Critical []string `yaml:"critical" json:"critical"`
+}
+
+// OpenAPI config for the OpenAPI document served on /openapi.json
+type OpenAPI struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// Version of the api in the document, defaults to 1.0.0
+	Version string `yaml:"version" json:"version"`
+	// RedocScript url of the Redoc bundle of the docs page, a pinned version
+	RedocScript string `yaml:"redoc_script" json:"redoc_script"`
+	// RedocIntegrity subresource integrity of the Redoc bundle, as in sha384-<base64 digest>
+	RedocIntegrity string `yaml:"redoc_integrity" json:"redoc_integrity"`
+}
+
+// APIVersion config of the versions of the api, the unversioned paths alias the routes of v1
+type APIVersion struct {
+	// Alias deprecation of the unversioned paths, advertised once deprecated_at is set
+	Alias Deprecation `yaml:"alias" json:"alias"`
+	// Deprecations of the versions, keyed by version
+	Deprecations map[string]Deprecation `yaml:"deprecations" json:"deprecations"`
+}
+
+// Deprecation of routes, the dates are in RFC 3339
+type Deprecation struct {
+	DeprecatedAt string `yaml:"deprecated_at" json:"deprecated_at"`
+	// SunsetAt the routes are removed after, optional
+	SunsetAt string `yaml:"sunset_at" json:"sunset_at"`
+	// Link to the migration guide, optional
+	Link string `yaml:"link" json:"link"`
+}
@@ -100,7 +100,10 @@ func (d *Data) transform(target interface{}, src map[string][]string) error {
 // Grab request method
 // Take a destination source of struct
 func (d *Data) grabMethod(target interface{}) error {
-	if d.Request.Method != http.MethodPost && d.Request.Method != http.MethodPut && d.Request.Method != http.MethodPatch {
+	// the body of a DELETE is read too, the credentials confirming it must stay out of the url
+	switch d.Request.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
 		return d.transform(target, d.Request.URL.Query())
 	}
 
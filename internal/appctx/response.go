@@ -3,6 +3,7 @@ package appctx
 
 import (
 	"encoding/json"
+	"net/http"
 	"sync"
 
 	"sharefood/internal/consts"
@@ -14,15 +15,18 @@ var oneRsp sync.Once
 
 // Response presentation contract object
 type Response struct {
-	Code    int         `json:"-"`
-	Status  string      `json:"status,omitempty"`
-	Entity  string      `json:"entity,omitempty"`
-	State   string      `json:"state,omitempty"`
-	Message interface{} `json:"message,omitempty"`
-	Meta    interface{} `json:"meta,omitempty"`
-	Errors  interface{} `json:"errors,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	lang    string      `json:"-"`
+	Code    int            `json:"-"`
+	ETag    string         `json:"-"`
+	Secret  bool           `json:"-"`
+	Cookies []*http.Cookie `json:"-"`
+	Status  string         `json:"status,omitempty"`
+	Entity  string         `json:"entity,omitempty"`
+	State   string         `json:"state,omitempty"`
+	Message interface{}    `json:"message,omitempty"`
+	Meta    interface{}    `json:"meta,omitempty"`
+	Errors  interface{}    `json:"errors,omitempty"`
+	Data    interface{}    `json:"data,omitempty"`
+	lang    string         `json:"-"`
 	msgKey  string
 }
 
@@ -103,6 +107,25 @@ func (r *Response) WithMeta(v interface{}) *Response {
 	return r
 }
 
+// WithETag setter entity tag of the data, the router tags the successful reads by their data
+// when it is not set
+func (r *Response) WithETag(v string) *Response {
+	r.ETag = v
+	return r
+}
+
+// WithSecret setter the data carries credentials, the response is neither cached nor compressed
+func (r *Response) WithSecret() *Response {
+	r.Secret = true
+	return r
+}
+
+// WithCookie setter cookie the router sets with the response
+func (r *Response) WithCookie(c *http.Cookie) *Response {
+	r.Cookies = append(r.Cookies, c)
+	return r
+}
+
 // WithLang setter language response
 func (r *Response) WithLang(v string) *Response {
 	r.lang = v
@@ -0,0 +1,113 @@
+// Package bootstrap
+package bootstrap
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/pkg/cryptox"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/util"
+)
+
+// RegistryJWTKeys initialize the key set access tokens are signed and verified with. Without
+// configured keys an ephemeral rsa key is generated in development, tokens then do not survive a
+// restart; any other environment refuses to start, each instance would reject the tokens of the others
+func RegistryJWTKeys(cfg *appctx.Config) *jwtx.KeySet {
+	lf := logger.EventName("jwt")
+
+	if len(cfg.JWT.Keys) == 0 {
+		if util.EnvironmentTransform(cfg.App.Env) != util.EnvironmentTransform(consts.EnvDevelopment) {
+			logger.Fatal(fmt.Sprintf("no jwt signing key configured, jwt.keys is required in the %q environment", cfg.App.Env), lf)
+		}
+
+		logger.Warn("no jwt signing key configured, signing with an ephemeral key", lf)
+
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			logger.Fatal(err, lf)
+		}
+
+		signing, err := jwtx.NewSigningKey("ephemeral", private)
+		if err != nil {
+			logger.Fatal(err, lf)
+		}
+
+		keys, err := jwtx.NewKeySet(signing)
+		if err != nil {
+			logger.Fatal(err, lf)
+		}
+		return keys
+	}
+
+	var signing *jwtx.Key
+	verification := make([]jwtx.Key, 0, len(cfg.JWT.Keys))
+	for _, k := range cfg.JWT.Keys {
+		key, err := loadJWTKey(k, k.ID == cfg.JWT.SigningKeyID)
+		if err != nil {
+			logger.Fatal(err, lf)
+		}
+
+		if k.ID == cfg.JWT.SigningKeyID {
+			signing = &key
+			continue
+		}
+		verification = append(verification, key)
+	}
+
+	if signing == nil {
+		logger.Fatal(fmt.Sprintf("jwt signing key %q not configured", cfg.JWT.SigningKeyID), lf)
+	}
+
+	keys, err := jwtx.NewKeySet(*signing, verification...)
+	if err != nil {
+		logger.Fatal(err, lf)
+	}
+
+	return keys
+}
+
+func loadJWTKey(k appctx.JWTKey, signing bool) (jwtx.Key, error) {
+	if signing {
+		b, err := readKey(k.PrivateKey, k.PrivateKeyFile)
+		if err != nil {
+			return jwtx.Key{}, fmt.Errorf("jwt key %q: %w", k.ID, err)
+		}
+
+		err, private := cryptox.BytesToSigner(b)
+		if err != nil {
+			return jwtx.Key{}, fmt.Errorf("jwt key %q: %w", k.ID, err)
+		}
+
+		return jwtx.NewSigningKey(k.ID, private)
+	}
+
+	b, err := readKey(k.PublicKey, k.PublicKeyFile)
+	if err != nil {
+		return jwtx.Key{}, fmt.Errorf("jwt key %q: %w", k.ID, err)
+	}
+
+	err, public := cryptox.BytesToAnyPublicKey(b)
+	if err != nil {
+		return jwtx.Key{}, fmt.Errorf("jwt key %q: %w", k.ID, err)
+	}
+
+	return jwtx.NewVerificationKey(k.ID, public)
+}
+
+func readKey(inline, path string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+
+	if path == "" {
+		return nil, fmt.Errorf("no key configured")
+	}
+
+	return ioutil.ReadFile(path)
+}
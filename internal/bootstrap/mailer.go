@@ -0,0 +1,19 @@
+// Package bootstrap
+package bootstrap
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/pkg/mailer"
+)
+
+// RegistryMailer initialize mailer based on configured driver
+func RegistryMailer(cfg *appctx.Config) mailer.Mailer {
+	switch cfg.Mailer.Driver {
+	case "smtp":
+		return mailer.NewSMTP(cfg.Mailer.Host, cfg.Mailer.Port, cfg.Mailer.User, cfg.Mailer.Password, cfg.Mailer.From)
+	case "file":
+		return mailer.NewFileOutbox(cfg.Mailer.OutboxDir, cfg.Mailer.From)
+	default:
+		return mailer.NewLogOutbox(cfg.Mailer.From)
+	}
+}
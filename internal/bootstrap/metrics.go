@@ -0,0 +1,39 @@
+// Package bootstrap
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"sharefood/internal/appctx"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/postgres"
+)
+
+// RegistryMetrics registers the stats of the database pools and of the redis pool on the default
+// prometheus registry, they are read when /metrics is scraped
+func RegistryMetrics(cfg *appctx.Config, db postgres.Adapter, rds redis.Cmdable) {
+	if !cfg.Metrics.Enable {
+		return
+	}
+
+	lf := logger.EventName("metrics")
+
+	names := map[string]string{postgres.PoolWrite: cfg.WriteDB.Name, postgres.PoolRead: cfg.ReadDB.Name}
+	for role, pool := range db.Pools() {
+		name := fmt.Sprintf("%s_%s", names[role], role)
+		if err := prometheus.Register(collectors.NewDBStatsCollector(pool, name)); err != nil {
+			logger.Fatal(logger.MessageFormat("cannot register database %s metrics: %v", name, err), lf)
+		}
+	}
+
+	if client, ok := rds.(cache.PoolStater); ok {
+		if err := prometheus.Register(cache.NewPoolCollector(client)); err != nil {
+			logger.Fatal(logger.MessageFormat("cannot register redis metrics: %v", err), lf)
+		}
+	}
+}
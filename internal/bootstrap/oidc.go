@@ -0,0 +1,32 @@
+// Package bootstrap
+package bootstrap
+
+import (
+	"net/http"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/pkg/oidc"
+)
+
+// RegistryOIDCProviders initialize the configured openid connect identity providers by name
+func RegistryOIDCProviders(cfg *appctx.Config) map[string]*oidc.Provider {
+	// nil falls back to the default timeout of the provider
+	var client *http.Client
+	if cfg.OIDC.TimeoutSecond > 0 {
+		client = &http.Client{Timeout: time.Duration(cfg.OIDC.TimeoutSecond) * time.Second}
+	}
+
+	providers := make(map[string]*oidc.Provider, len(cfg.OIDC.Providers))
+	for name, p := range cfg.OIDC.Providers {
+		providers[name] = oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, client)
+	}
+
+	return providers
+}
@@ -0,0 +1,29 @@
+// Package bootstrap
+package bootstrap
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/ratelimit"
+)
+
+// RegistryRateLimiter initialize the limiter of the rate limited routes, nil when rate limiting is disabled.
+// The redis driver counts in memory while redis cannot be reached
+func RegistryRateLimiter(cfg *appctx.Config, cacher cache.Cacher) *ratelimit.Limiter {
+	if !cfg.RateLimit.Enable {
+		return nil
+	}
+
+	lf := logger.EventName("rate_limit")
+
+	switch cfg.RateLimit.Driver {
+	case consts.RateLimitDriverMemory:
+		logger.Info("rate limit counted in memory, each instance limits on its own", lf)
+		return ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+	default:
+		store := ratelimit.NewCacheStore(cacher, consts.RateLimitKeyPrefix)
+		return ratelimit.NewLimiter(ratelimit.NewFallbackStore(store, ratelimit.NewMemoryStore()))
+	}
+}
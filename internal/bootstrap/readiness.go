@@ -0,0 +1,61 @@
+// Package bootstrap
+package bootstrap
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/pkg/health"
+	"sharefood/pkg/kafka"
+	"sharefood/pkg/postgres"
+)
+
+// RegistryReadinessChecks the checks of the dependencies the readiness probe reports, kafka is only
+// checked when brokers are configured
+func RegistryReadinessChecks(cfg *appctx.Config, db postgres.Adapter, rds redis.Cmdable) []health.Check {
+	critical := cfg.Readiness.Critical
+	if len(critical) == 0 {
+		critical = consts.ReadinessCriticalDefault
+	}
+
+	isCritical := func(name string) bool {
+		for _, c := range critical {
+			if strings.EqualFold(c, name) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	pools := db.Pools()
+	checks := []health.Check{
+		{Name: consts.ReadinessDBWrite, Check: pools[postgres.PoolWrite].PingContext},
+	}
+
+	// without a read pool the reads go to the write one, already checked
+	if pool, ok := pools[postgres.PoolRead]; ok {
+		checks = append(checks, health.Check{Name: consts.ReadinessDBRead, Check: pool.PingContext})
+	}
+
+	checks = append(checks, health.Check{Name: consts.ReadinessRedis, Check: func(ctx context.Context) error {
+		return rds.Ping(ctx).Err()
+	}})
+
+	if cfg.Kafka != nil && strings.TrimSpace(cfg.Kafka.Brokers) != "" {
+		brokers := strings.Split(cfg.Kafka.Brokers, ",")
+		checks = append(checks, health.Check{Name: consts.ReadinessKafka, Check: func(ctx context.Context) error {
+			return kafka.Ping(ctx, brokers)
+		}})
+	}
+
+	for i := range checks {
+		checks[i].Critical = isCritical(checks[i].Name)
+	}
+
+	return checks
+}
@@ -0,0 +1,19 @@
+// Package bootstrap
+package bootstrap
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/sms"
+)
+
+// RegistrySMSSender initialize text message sender based on configured driver
+func RegistrySMSSender(cfg *appctx.Config) sms.Sender {
+	switch cfg.SMS.Driver {
+	case "", "log":
+		return sms.NewLogSender()
+	default:
+		logger.Warn(logger.MessageFormat("unknown sms driver %s, fallback to log sender", cfg.SMS.Driver), logger.EventName("sms"))
+		return sms.NewLogSender()
+	}
+}
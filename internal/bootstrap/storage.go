@@ -0,0 +1,32 @@
+// Package bootstrap
+package bootstrap
+
+import (
+	"context"
+	"os"
+
+	"sharefood/internal/appctx"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/storage"
+)
+
+// RegistryStorage initialize blob storage based on configured driver
+func RegistryStorage(cfg *appctx.Config) storage.Storage {
+	switch cfg.Storage.Driver {
+	case "s3":
+		return storage.NewAwsS3(RegistryAWSSession(cfg))
+	case "gcs":
+		st, err := storage.NewGCS(context.Background(), cfg.GCS.AccountPath)
+		if err != nil {
+			logger.Fatal(err, logger.EventName("storage"))
+		}
+		return st
+	case "noop":
+		return storage.NewNoop()
+	default:
+		if err := os.MkdirAll(cfg.Storage.Bucket, 0755); err != nil {
+			logger.Fatal(err, logger.EventName("storage"))
+		}
+		return storage.NewFileSystem()
+	}
+}
@@ -0,0 +1,33 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	"sharefood/internal/appctx"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/tracer"
+)
+
+// RegistryTracer exports the spans to the OTLP collector, or to stdout for local development. The trace
+// context is propagated even when the export is disabled
+func RegistryTracer(cfg *appctx.Config) {
+	if !cfg.APM.Enable {
+		return
+	}
+
+	lf := logger.NewFields(logger.EventName("TracerInitiated"))
+	logger.Debug(fmt.Sprint("apm exporter : ", cfg.APM.Exporter, ", address : ", cfg.APM.Address), lf...)
+
+	err := tracer.Setup(context.Background(), tracer.Config{
+		ServiceName: cfg.APM.Name,
+		Env:         cfg.App.Env,
+		Exporter:    cfg.APM.Exporter,
+		Endpoint:    cfg.APM.Address,
+		Insecure:    cfg.APM.Insecure,
+		SampleRatio: cfg.APM.SampleRate,
+	})
+	if err != nil {
+		logger.Fatal(logger.MessageFormat("tracer setup: %v", err), lf...)
+	}
+}
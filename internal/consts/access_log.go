@@ -0,0 +1,6 @@
+package consts
+
+const (
+	// AccessLogSampleRateDefault const, every request is logged
+	AccessLogSampleRateDefault = 1.0
+)
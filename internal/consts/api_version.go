@@ -0,0 +1,6 @@
+package consts
+
+const (
+	// APIVersion1 const, the version the unversioned paths alias during the migration
+	APIVersion1 = "v1"
+)
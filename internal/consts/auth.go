@@ -0,0 +1,135 @@
+package consts
+
+const (
+	// PasswordResetTTLMinuteDefault const
+	PasswordResetTTLMinuteDefault = 30
+
+	// PasswordResetTokenBytes const
+	PasswordResetTokenBytes = 32
+
+	// EmailVerificationTTLHourDefault const
+	EmailVerificationTTLHourDefault = 24
+
+	// EmailVerificationAudience const
+	EmailVerificationAudience = "email_verification"
+
+	// PhoneOTPTTLMinuteDefault const
+	PhoneOTPTTLMinuteDefault = 10
+
+	// PhoneOTPMaxAttemptDefault const
+	PhoneOTPMaxAttemptDefault = 5
+
+	// PhoneOTPLength const
+	PhoneOTPLength = 6
+
+	// PhoneOTPMaxAttemptDailyDefault const, wrong guesses of a user a day whatever the codes sent
+	PhoneOTPMaxAttemptDailyDefault = 15
+
+	// VerificationResendCooldownSecondDefault const
+	VerificationResendCooldownSecondDefault = 60
+
+	// VerificationSendMaxDailyDefault const, codes or links sent a day to a user and to a destination
+	VerificationSendMaxDailyDefault = 5
+
+	// VerificationCooldownKeyPrefix const
+	VerificationCooldownKeyPrefix = "verification:cooldown:"
+
+	// VerificationCountKeyPrefix const
+	VerificationCountKeyPrefix = "verification:count:"
+
+	// VerificationChannelEmail const
+	VerificationChannelEmail = "email"
+
+	// VerificationChannelPhone const
+	VerificationChannelPhone = "phone"
+
+	// AvatarMaxSizeKBDefault const
+	AvatarMaxSizeKBDefault = 2048
+
+	// AvatarFormField const
+	AvatarFormField = "avatar"
+
+	// LoginMaxAttemptAccountDefault const
+	LoginMaxAttemptAccountDefault = 5
+
+	// LoginMaxAttemptIPDefault const
+	LoginMaxAttemptIPDefault = 20
+
+	// LoginAttemptWindowMinuteDefault const
+	LoginAttemptWindowMinuteDefault = 15
+
+	// LoginLockoutMinuteDefault const
+	LoginLockoutMinuteDefault = 15
+
+	// LoginDelayBaseMS const, delay after the first failed attempt, doubled on every next one
+	LoginDelayBaseMS = 250
+
+	// LoginDelayMaxMS const
+	LoginDelayMaxMS = 4000
+
+	// LoginFailureKeyPrefix const
+	LoginFailureKeyPrefix = "login:failure:"
+
+	// LoginLockKeyPrefix const
+	LoginLockKeyPrefix = "login:lock:"
+
+	// LoginLockoutReasonAccount const
+	LoginLockoutReasonAccount = "account"
+
+	// LoginLockoutReasonIP const
+	LoginLockoutReasonIP = "ip"
+
+	// LoginLockoutReasonTwoFactor const, wrong two-factor codes of an account across its challenges
+	LoginLockoutReasonTwoFactor = "two_factor"
+
+	// OIDCSessionTTLMinute const, time a user has to finish signing in at the identity provider
+	OIDCSessionTTLMinute = 10
+
+	// OIDCSessionKeyPrefix const
+	OIDCSessionKeyPrefix = "oidc:session:"
+
+	// OIDCStateCookieName const, binds the state of a login to the browser that started it
+	OIDCStateCookieName = "oidc_state"
+
+	// PartnerSignatureMaxSkewSecondDefault const, how far the timestamp of a signed request may be off
+	PartnerSignatureMaxSkewSecondDefault = 300
+
+	// PartnerKeyRotationGraceHourDefault const, how long the secret before a rotation is still accepted
+	PartnerKeyRotationGraceHourDefault = 24
+
+	// PartnerKeySecretBytes const
+	PartnerKeySecretBytes = 32
+
+	// PartnerNonceKeyPrefix const
+	PartnerNonceKeyPrefix = "partner:nonce:"
+
+	// TwoFactorChallengeTTLMinute const, time between the password and the code step of a login
+	TwoFactorChallengeTTLMinute = 5
+
+	// TwoFactorChallengeAudience const
+	TwoFactorChallengeAudience = "two_factor_challenge"
+
+	// TwoFactorSkewStep const, periods before and after the current one a code is accepted in
+	TwoFactorSkewStep = 1
+
+	// TwoFactorMaxAttempt const, wrong codes allowed for a challenge before signing in again
+	TwoFactorMaxAttempt = 5
+
+	// TwoFactorRecoveryCodeCount const
+	TwoFactorRecoveryCodeCount = 10
+
+	// TwoFactorRecoveryCodeBytes const
+	TwoFactorRecoveryCodeBytes = 5
+
+	// TwoFactorCodeKeyPrefix const
+	TwoFactorCodeKeyPrefix = "2fa:code:"
+
+	// TwoFactorAttemptKeyPrefix const
+	TwoFactorAttemptKeyPrefix = "2fa:attempt:"
+
+	// TwoFactorCompleteKeyPrefix const, marks the challenges already exchanged for a token
+	TwoFactorCompleteKeyPrefix = "2fa:complete:"
+
+	// LoginDummyPasswordHash const, compared against when the email is unknown so both cases take as long
+	LoginDummyPasswordHash = "$2a$10$hZ43NvZK56EHb3x/sbcLF.goShBlp3KWasTk.G0qdo7NHMfX0VVMa"
+)
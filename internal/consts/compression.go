@@ -0,0 +1,13 @@
+package consts
+
+const (
+	// CompressionMinSizeByteDefault const, smaller responses are sent as is
+	CompressionMinSizeByteDefault = 1024
+
+	// EncodingBrotli const
+	EncodingBrotli = "br"
+	// EncodingGzip const
+	EncodingGzip = "gzip"
+	// EncodingIdentity const
+	EncodingIdentity = "identity"
+)
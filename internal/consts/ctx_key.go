@@ -14,4 +14,10 @@ const (
 	CtxLang
 	// CtxUserInfo const
 	CtxUserInfo
+	// CtxTokenClaims const
+	CtxTokenClaims
+	// CtxPartnerAPIKey const
+	CtxPartnerAPIKey
+	// CtxCanonicalPath const, versioned path of a request sent to an unversioned alias
+	CtxCanonicalPath
 )
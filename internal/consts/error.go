@@ -104,10 +104,80 @@ const (
 	CreateRequestErrorMessage = "create request error"
 	ActionRequestErrorMessage = "action request error"
 	UpdateFoodErrorMessage    = "update food error"
+	FoodModifiedMessage       = "food changed since it was read, reload it and try again"
 	StatusForbidden           = "forbidden"
 	NotEnoughQuantity         = "too many quantity requested"
 	ActionRequestNotValid     = "action cannot be processed"
 	ActionAlreadyDone         = "action already accepted or rejected"
+	ResetTokenNotValid        = "reset token not valid or expired"
+	ResetPasswordErrorMessage = "reset password error"
+	SessionRevoked            = "session revoked"
+	VerificationTokenNotValid = "verification token not valid or expired"
+	VerificationCodeNotValid  = "verification code not valid or expired"
+	VerificationCodeExhausted = "too many verification attempts, request a new code"
+	VerificationTooSoon       = "verification sent recently, try again in a minute"
+	VerificationSendExhausted = "too many verifications sent today, try again tomorrow"
+	VerificationTriesExceeded = "too many verification attempts today, try again tomorrow"
+	AccountNotVerified        = "account email and phone number not verified"
+	VerifyAccountErrorMessage = "verify account error"
+	AlreadyVerified           = "already verified"
+	UpdateProfileErrorMessage = "update profile error"
+	CurrentPasswordNotValid   = "current password not valid"
+	AvatarNotValid            = "avatar must be a png, jpeg or webp image"
+	AvatarTooLarge            = "avatar file too large"
+	UserNotFoundMessage       = "user not found"
+	AccountSuspended          = "account suspended"
+	UpdateUserErrorMessage    = "update user error"
+	ListUserErrorMessage      = "list user error"
+	RoleNotValid              = "role not valid"
+	UpdateRoleErrorMessage    = "update role error"
+	DeleteAccountErrorMessage = "delete account error"
+	LoginFailed               = "invalid email or password"
+	LoginLocked               = "too many login attempts, try again later"
+	OIDCProviderNotFound      = "identity provider not found"
+	OIDCStateNotValid         = "login state not valid or expired"
+	OIDCLoginFailed           = "identity provider login failed"
+	OIDCEmailNotVerified      = "identity provider email not verified"
+	OIDCAccountNotVerified    = "verify the email of the existing account before signing in with an identity provider"
+	ExportAccountErrorMessage = "export account error"
+	AccountSoleOwner          = "transfer the ownership of your organizations before deleting the account"
+
+	PartnerKeyNotFoundMessage = "partner api key not found"
+	PartnerKeyErrorMessage    = "partner api key error"
+	PartnerKeyNotValid        = "api key not valid or revoked"
+	PartnerScopeNotValid      = "scope not valid"
+	PartnerUserNotMember      = "user must be a member of the organization"
+	SignatureNotValid         = "signature not valid"
+	TimestampNotValid         = "request timestamp not valid or expired"
+	NonceRequiredMessage      = "nonce required"
+	NonceAlreadyUsed          = "nonce already used"
+
+	TwoFactorErrorMessage      = "two factor authentication error"
+	TwoFactorCodeNotValid      = "two factor code not valid"
+	TwoFactorChallengeNotValid = "two factor challenge not valid or expired"
+	TwoFactorAttemptsExhausted = "too many two factor attempts, sign in again"
+	TwoFactorAlreadyEnabled    = "two factor authentication already enabled"
+	TwoFactorNotEnrolled       = "two factor enrollment not started"
+	TwoFactorNotEnabled        = "two factor authentication not enabled"
+	TwoFactorRequired          = "enable two factor authentication to use this role"
+
+	RateLimitExceeded = "too many requests, retry later"
+
+	MaintenanceMode               = "service under maintenance, retry later"
+	MaintenanceErrorMessage       = "maintenance error"
+	MaintenanceRetryAfterNotValid = "retry after second must not be negative"
+
+	IdempotencyKeyNotValid  = "idempotency key not valid"
+	IdempotencyKeyInFlight  = "a request with this idempotency key is in progress"
+	IdempotencyKeyReused    = "idempotency key already used for a different request"
+	IdempotencyBodyTooLarge = "request body too large for an idempotency key"
+	IdempotencyNotReplayed  = "request already processed, its response carried credentials and is not replayed"
+
+	OrganizationNotFoundMessage = "organization not found"
+	OrganizationErrorMessage    = "organization error"
+	OrganizationMemberExists    = "user already a member of the organization"
+	OrganizationMemberNotFound  = "organization member not found"
+	OrganizationLastOwner       = "organization must keep at least one owner"
 
 	AssetNotFoundMessage                                = "asset not found"
 	ClientIDNotValidMessage                             = "clients id not valid"
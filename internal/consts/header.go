@@ -10,4 +10,76 @@ const (
 
 	// HeaderContentTypeJSON const
 	HeaderContentTypeJSON = `application/json`
+
+	// HeaderContentTypeHTML const
+	HeaderContentTypeHTML = `text/html; charset=utf-8`
+
+	// HeaderUserIDKey const, set by the authentication middlewares to the id of the signed in user
+	HeaderUserIDKey = `idUser`
+
+	// HeaderRequestIDKey const
+	HeaderRequestIDKey = `X-Request-ID`
+
+	// HeaderAPIKey const
+	HeaderAPIKey = `X-Api-Key`
+
+	// HeaderTimestamp const
+	HeaderTimestamp = `X-Timestamp`
+
+	// HeaderNonce const
+	HeaderNonce = `X-Nonce`
+
+	// HeaderSignature const
+	HeaderSignature = `X-Signature`
+
+	// HeaderCacheControlKey const
+	HeaderCacheControlKey = `Cache-Control`
+
+	// HeaderETagKey const
+	HeaderETagKey = `ETag`
+
+	// HeaderIfNoneMatchKey const
+	HeaderIfNoneMatchKey = `If-None-Match`
+
+	// HeaderIfMatchKey const
+	HeaderIfMatchKey = `If-Match`
+
+	// HeaderAcceptEncodingKey const
+	HeaderAcceptEncodingKey = `Accept-Encoding`
+
+	// HeaderContentEncodingKey const
+	HeaderContentEncodingKey = `Content-Encoding`
+
+	// HeaderContentLengthKey const
+	HeaderContentLengthKey = `Content-Length`
+
+	// HeaderVaryKey const
+	HeaderVaryKey = `Vary`
+
+	// HeaderLinkKey const
+	HeaderLinkKey = `Link`
+
+	// HeaderRetryAfterKey const
+	HeaderRetryAfterKey = `Retry-After`
+
+	// HeaderRateLimitLimitKey const
+	HeaderRateLimitLimitKey = `X-RateLimit-Limit`
+
+	// HeaderRateLimitRemainingKey const
+	HeaderRateLimitRemainingKey = `X-RateLimit-Remaining`
+
+	// HeaderRateLimitResetKey const, seconds until the current window ends
+	HeaderRateLimitResetKey = `X-RateLimit-Reset`
+
+	// HeaderIdempotencyKey const
+	HeaderIdempotencyKey = `Idempotency-Key`
+
+	// HeaderIdempotentReplayedKey const, set on a response replayed for a retry
+	HeaderIdempotentReplayedKey = `Idempotent-Replayed`
+
+	// JWKSCacheControl const, verifiers refetch the keys hourly so a new key must be published an hour before signing with it
+	JWKSCacheControl = `public, max-age=3600`
+
+	// CacheControlNoStore const, of the responses carrying credentials
+	CacheControlNoStore = `no-store`
 )
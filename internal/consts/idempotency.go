@@ -0,0 +1,21 @@
+package consts
+
+const (
+	// IdempotencyTTLHourDefault const
+	IdempotencyTTLHourDefault = 24
+
+	// IdempotencyLockSecondDefault const, how long a request is considered in flight at most
+	IdempotencyLockSecondDefault = 60
+
+	// IdempotencyBodyMaxByteDefault const, largest body hashed for an idempotency key, above the largest avatar
+	IdempotencyBodyMaxByteDefault = 4 << 20
+
+	// IdempotencyKeyMaxLength const
+	IdempotencyKeyMaxLength = 255
+
+	// IdempotencyKeyPrefix const
+	IdempotencyKeyPrefix = "idempotency:response:"
+
+	// IdempotencyLockKeyPrefix const
+	IdempotencyLockKeyPrefix = "idempotency:lock:"
+)
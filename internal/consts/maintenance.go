@@ -0,0 +1,12 @@
+package consts
+
+const (
+	// MaintenanceRetryAfterSecondDefault const
+	MaintenanceRetryAfterSecondDefault = 300
+
+	// MaintenanceRefreshSecond const, how long an instance keeps the state before reading it again
+	MaintenanceRefreshSecond = 5
+
+	// MaintenanceKey const
+	MaintenanceKey = "maintenance:state"
+)
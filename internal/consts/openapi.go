@@ -0,0 +1,21 @@
+package consts
+
+const (
+	// OpenAPIPath const, the OpenAPI document of the routes
+	OpenAPIPath = "/openapi.json"
+
+	// OpenAPIDocsPath const, the page rendering the OpenAPI document
+	OpenAPIDocsPath = "/docs"
+
+	// OpenAPIVersionDefault const
+	OpenAPIVersionDefault = "1.0.0"
+
+	// OpenAPIRedocScriptDefault const, the Redoc bundle of a pinned version rendering the docs page
+	OpenAPIRedocScriptDefault = "https://cdn.jsdelivr.net/npm/redoc@2.1.5/bundles/redoc.standalone.js"
+
+	// SecurityBearer const, the access token in the Authorization header
+	SecurityBearer = "bearer"
+
+	// SecurityPartnerSignature const, the signature of a partner API key
+	SecurityPartnerSignature = "partnerSignature"
+)
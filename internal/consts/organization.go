@@ -0,0 +1,11 @@
+package consts
+
+const (
+	// OrganizationMemberOwner manages the members and the listings of the organization
+	OrganizationMemberOwner = "owner"
+	// OrganizationMemberStaff manages the listings of the organization
+	OrganizationMemberStaff = "staff"
+)
+
+// OrganizationKinds accepted organization kinds
+var OrganizationKinds = []string{"restaurant", "hotel", "ngo", "other"}
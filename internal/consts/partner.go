@@ -0,0 +1,9 @@
+package consts
+
+const (
+	// PartnerScopeFoodWrite share foods on behalf of the organization
+	PartnerScopeFoodWrite = "food:write"
+)
+
+// PartnerScopes scopes a partner api key can be issued with
+var PartnerScopes = []string{PartnerScopeFoodWrite}
@@ -0,0 +1,19 @@
+package consts
+
+const (
+	// RateLimitKeyIP counts the calls by client ip
+	RateLimitKeyIP = "ip"
+	// RateLimitKeyUser counts the calls by signed in user
+	RateLimitKeyUser = "user"
+	// RateLimitKeyAPIKey counts the calls by partner api key
+	RateLimitKeyAPIKey = "api_key"
+
+	// RateLimitDriverMemory counts the calls in each instance
+	RateLimitDriverMemory = "memory"
+
+	// RateLimitWindowSecondDefault const
+	RateLimitWindowSecondDefault = 60
+
+	// RateLimitKeyPrefix const
+	RateLimitKeyPrefix = "ratelimit:"
+)
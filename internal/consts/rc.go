@@ -3,11 +3,14 @@ package consts
 const (
 	CodeSuccess               = 200
 	CodeCreated               = 201
+	CodeNotModified           = 304
 	CodeBadRequest            = 400
 	CodeAuthenticationFailure = 401
 	CodeForbidden             = 403
 	CodeNotFound              = 404
+	CodePreconditionFailed    = 412
 	CodeRequestTimeout        = 408
+	CodeRequestTooLarge       = 413
 	CodeUnprocessableEntity   = 422
 	CodeReachMaxLimit         = 429
 	CodeInternalServerError   = 500
@@ -0,0 +1,18 @@
+package consts
+
+const (
+	// ReadinessTimeoutSecondDefault const
+	ReadinessTimeoutSecondDefault = 2
+
+	// ReadinessDBWrite const
+	ReadinessDBWrite = "db_write"
+	// ReadinessDBRead const
+	ReadinessDBRead = "db_read"
+	// ReadinessRedis const
+	ReadinessRedis = "redis"
+	// ReadinessKafka const
+	ReadinessKafka = "kafka"
+)
+
+// ReadinessCriticalDefault dependencies the service is not ready without, unless configured
+var ReadinessCriticalDefault = []string{ReadinessDBWrite, ReadinessDBRead, ReadinessRedis}
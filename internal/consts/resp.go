@@ -11,4 +11,5 @@ const (
 	RespReachMaxLimit      = `REACH-MAX-LIMIT`
 	RespOK                 = `OK`
 	RespSignatureIncorrect = `VALIDATION-SIGNATURE`
+	RespMaintenance        = `MAINTENANCE`
 )
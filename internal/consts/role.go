@@ -0,0 +1,61 @@
+package consts
+
+const (
+	// RoleReceiver const
+	RoleReceiver = "receiver"
+	// RoleGiver const
+	RoleGiver = "giver"
+	// RoleOrganizationAdmin const
+	RoleOrganizationAdmin = "organization_admin"
+	// RoleModerator const
+	RoleModerator = "moderator"
+	// RoleAdmin const
+	RoleAdmin = "admin"
+)
+
+const (
+	// PermissionFoodShare list and manage own food listings and act on their requests
+	PermissionFoodShare = "food:share"
+	// PermissionFoodRequest request food from other listings
+	PermissionFoodRequest = "food:request"
+	// PermissionUserRead browse the user management
+	PermissionUserRead = "user:read"
+	// PermissionUserSuspend suspend and unsuspend accounts
+	PermissionUserSuspend = "user:suspend"
+	// PermissionRoleManage grant and revoke roles
+	PermissionRoleManage = "role:manage"
+	// PermissionPartnerManage issue, rotate and revoke partner api keys
+	PermissionPartnerManage = "partner:manage"
+	// PermissionMaintenanceManage toggle the maintenance mode, and reach the service while it is on
+	PermissionMaintenanceManage = "maintenance:manage"
+)
+
+// ManagementPermissions permissions giving power over other accounts or the service, an account carrying
+// some of them is only managed by one carrying them too
+var ManagementPermissions = []string{
+	PermissionUserRead,
+	PermissionUserSuspend,
+	PermissionRoleManage,
+	PermissionPartnerManage,
+	PermissionMaintenanceManage,
+}
+
+// DefaultRoles granted on registration
+var DefaultRoles = []string{RoleReceiver, RoleGiver}
+
+// RolePermissions permissions carried by each role
+var RolePermissions = map[string][]string{
+	RoleReceiver:          {PermissionFoodRequest},
+	RoleGiver:             {PermissionFoodShare},
+	RoleOrganizationAdmin: {PermissionFoodShare},
+	RoleModerator:         {PermissionUserRead, PermissionUserSuspend},
+	RoleAdmin: {
+		PermissionFoodShare,
+		PermissionFoodRequest,
+		PermissionUserRead,
+		PermissionUserSuspend,
+		PermissionRoleManage,
+		PermissionPartnerManage,
+		PermissionMaintenanceManage,
+	},
+}
@@ -0,0 +1,18 @@
+package consts
+
+const (
+	// UserStatusActive const
+	UserStatusActive = "active"
+
+	// UserStatusSuspended const
+	UserStatusSuspended = "suspended"
+
+	// DeletedAccountName const
+	DeletedAccountName = "Deleted user"
+
+	// DeletedAccountEmailFormat const, keeps the anonymized email unique per account
+	DeletedAccountEmailFormat = "deleted-%s@deleted.invalid"
+
+	// AccountPurgeAfterDayDefault const
+	AccountPurgeAfterDayDefault = 30
+)
@@ -0,0 +1,13 @@
+package entity
+
+import "encoding/json"
+
+// IdempotentResponse first response to a request sent with an idempotency key, replayed on its retries
+type IdempotentResponse struct {
+	// RequestHash sha256 of the method, path and body of the request, a retry must send the same
+	RequestHash string          `json:"request_hash"`
+	Code        int             `json:"code"`
+	Body        json.RawMessage `json:"body"`
+	// Secret the response carried credentials, only its status is stored and it is not replayed
+	Secret bool `json:"secret,omitempty"`
+}
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserIdentity struct {
+	Provider  string    `json:"provider" db:"provider"`
+	Subject   string    `json:"subject" db:"subject"`
+	IDUser    uuid.UUID `json:"id_user" db:"id_user"`
+	Email     string    `json:"email" db:"email"`
+	CreatedAt time.Time `json:"created_at,omitempty" db:"created_at"`
+}
+
+// OIDCSession what the callback needs to finish a login started with a state
+type OIDCSession struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+type OIDCCallback struct {
+	Code  string `json:"code" url:"code"`
+	State string `json:"state" url:"state"`
+}
+
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
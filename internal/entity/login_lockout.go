@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoginLockout struct {
+	ID          uuid.UUID  `json:"id_login_lockout" db:"id_login_lockout"`
+	IDUser      *uuid.UUID `json:"id_user,omitempty" db:"id_user"`
+	Email       string     `json:"email" db:"email"`
+	IPAddress   string     `json:"ip_address" db:"ip_address"`
+	Reason      string     `json:"reason" db:"reason"`
+	Attempts    int64      `json:"attempts" db:"attempts"`
+	LockedUntil time.Time  `json:"locked_until" db:"locked_until"`
+	CreatedAt   time.Time  `json:"created_at,omitempty" db:"created_at"`
+}
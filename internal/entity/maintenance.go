@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Maintenance state of the maintenance mode, the configured one until an admin toggles it
+type Maintenance struct {
+	Enabled bool `json:"enabled"`
+	// RetryAfterSecond hint given to the clients of when to retry
+	RetryAfterSecond int        `json:"retry_after_second"`
+	UpdatedBy        *uuid.UUID `json:"updated_by,omitempty"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+}
+
+// MaintenanceInput enables the maintenance mode
+type MaintenanceInput struct {
+	// RetryAfterSecond defaults to the configured one
+	RetryAfterSecond int `json:"retry_after_second"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Organization struct {
+	ID        uuid.UUID `json:"id_organization" db:"id_organization"`
+	Name      string    `json:"name" db:"name"`
+	Kind      string    `json:"kind" db:"kind"`
+	CreatedAt time.Time `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at"`
+	// MemberRole role of the requesting user, only set when listing the organizations of a user
+	MemberRole string `json:"member_role,omitempty" db:"role"`
+}
+
+type OrganizationMember struct {
+	IDOrganization uuid.UUID `json:"id_organization" db:"id_organization"`
+	IDUser         uuid.UUID `json:"id_user" db:"id_user"`
+	Name           string    `json:"name" db:"name"`
+	Email          string    `json:"email" db:"email"`
+	Role           string    `json:"role" db:"role"`
+	CreatedAt      time.Time `json:"created_at,omitempty" db:"created_at"`
+}
+
+type OrganizationInput struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+type OrganizationMemberInput struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type OrganizationDetail struct {
+	Organization
+	Members []OrganizationMember `json:"members"`
+}
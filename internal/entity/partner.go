@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PartnerAPIKey lets a partner system sign requests on behalf of an organization,
+// the foods it shares are owned by IDUser, a member of the organization
+type PartnerAPIKey struct {
+	ID             uuid.UUID `json:"id_api_key" db:"id_api_key"`
+	Name           string    `json:"name" db:"name"`
+	IDOrganization uuid.UUID `json:"id_organization" db:"id_organization"`
+	IDUser         uuid.UUID `json:"id_user" db:"id_user"`
+	Scopes         []string  `json:"scopes" db:"scopes"`
+	Secret         string    `json:"-" db:"secret"`
+	// PreviousSecret the secret before the last rotation, accepted until PreviousSecretExpiresAt
+	PreviousSecret          string     `json:"-" db:"previous_secret"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty" db:"previous_secret_expires_at"`
+	CreatedBy               uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt               time.Time  `json:"created_at" db:"created_at"`
+	RotatedAt               *time.Time `json:"rotated_at,omitempty" db:"rotated_at"`
+	RevokedAt               *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	LastUsedAt              *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+}
+
+// HasScope key has been issued with the scope
+func (k PartnerAPIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsRevoked key has been revoked by an admin
+func (k PartnerAPIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// IssuedAt the time the current secret of the key was issued
+func (k PartnerAPIKey) IssuedAt() time.Time {
+	if k.RotatedAt != nil {
+		return *k.RotatedAt
+	}
+
+	return k.CreatedAt
+}
+
+// Secrets the secrets a signature is accepted with at the time
+func (k PartnerAPIKey) Secrets(at time.Time) []string {
+	secrets := []string{k.Secret}
+	if k.PreviousSecret != "" && k.PreviousSecretExpiresAt != nil && at.Before(*k.PreviousSecretExpiresAt) {
+		secrets = append(secrets, k.PreviousSecret)
+	}
+
+	return secrets
+}
+
+type PartnerAPIKeyInput struct {
+	Name           string    `json:"name"`
+	IDOrganization uuid.UUID `json:"id_organization"`
+	IDUser         uuid.UUID `json:"id_user"`
+	Scopes         []string  `json:"scopes"`
+}
+
+// PartnerAPIKeySecret shown once, when the key is issued or rotated
+type PartnerAPIKeySecret struct {
+	PartnerAPIKey
+	Secret string `json:"secret"`
+}
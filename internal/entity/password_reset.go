@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasswordReset struct {
+	ID        uuid.UUID  `json:"id_password_reset" db:"id_password_reset"`
+	IDUser    uuid.UUID  `json:"id_user" db:"id_user"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiredAt time.Time  `json:"expired_at" db:"expired_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at,omitempty" db:"created_at"`
+}
+
+type PasswordForgot struct {
+	Email string `json:"email"`
+}
+
+type PasswordResetInput struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
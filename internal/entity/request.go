@@ -22,13 +22,14 @@ type RequestAction struct {
 }
 
 type RequestWithFood struct {
-	ID         uuid.UUID `json:"id_request" db:"requests.id_request"`
-	IDUser     uuid.UUID `json:"id_user" db:"requests.id_user"`
-	IDFood     uuid.UUID `json:"id_food" db:"requests.id_food"`
-	Status     int       `json:"status" db:"requests.status"`
-	Quantity   int       `json:"quantity" db:"requests.quantity"`
-	IDUserFood uuid.UUID `json:"id_user_food" db:"foods.id_user"`
-	Stock      int       `json:"stock" db:"foods.quantity"`
+	ID         uuid.UUID  `json:"id_request" db:"requests.id_request"`
+	IDUser     uuid.UUID  `json:"id_user" db:"requests.id_user"`
+	IDFood     uuid.UUID  `json:"id_food" db:"requests.id_food"`
+	Status     int        `json:"status" db:"requests.status"`
+	Quantity   int        `json:"quantity" db:"requests.quantity"`
+	IDUserFood uuid.UUID  `json:"id_user_food" db:"foods.id_user"`
+	IDOrgFood  *uuid.UUID `json:"id_organization_food,omitempty" db:"foods.id_organization"`
+	Stock      int        `json:"stock" db:"foods.quantity"`
 }
 
 // type RequestInput struct {
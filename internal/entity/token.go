@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"sharefood/internal/consts"
 	"time"
 
 	"github.com/dgrijalva/jwt-go/v4"
@@ -8,10 +9,47 @@ import (
 )
 
 type TokenClaims struct {
-	ID uuid.UUID `json:"id_user"`
+	ID    uuid.UUID `json:"id_user"`
+	Roles []string  `json:"roles"`
+	// WithheldRoles roles of the account left out of Roles until it enables two-factor authentication
+	WithheldRoles []string `json:"-"`
 	jwt.StandardClaims
 }
 
+// HasPermission one of the roles in the claims carries the permission
+func (c TokenClaims) HasPermission(permission string) bool {
+	return rolesHavePermission(c.Roles, permission)
+}
+
+// WithholdsPermission one of the withheld roles would carry the permission
+func (c TokenClaims) WithholdsPermission(permission string) bool {
+	return rolesHavePermission(c.WithheldRoles, permission)
+}
+
+// Outranks the claims carry every management permission the roles carry, so the account holding
+// them can be managed by the claims
+func (c TokenClaims) Outranks(roles []string) bool {
+	for _, p := range consts.ManagementPermissions {
+		if rolesHavePermission(roles, p) && !c.HasPermission(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func rolesHavePermission(roles []string, permission string) bool {
+	for _, role := range roles {
+		for _, p := range consts.RolePermissions[role] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 type TokenResponse struct {
 	Type      string    `json:"type"`
 	Token     string    `json:"token"`
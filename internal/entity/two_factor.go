@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"github.com/google/uuid"
+)
+
+// TwoFactorEnrollment secret to add to an authenticator app, usually by scanning the provisioning uri as a qr code
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorConfirm struct {
+	Code string `json:"code"`
+}
+
+type TwoFactorDisable struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// TwoFactorRecoveryCodes shown once, each signs in a single time when the authenticator is lost
+type TwoFactorRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallenge returned by the login of an account with two-factor authentication,
+// the challenge token is exchanged along with a code for the access token
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiredAt         time.Time `json:"expired_at"`
+}
+
+type TwoFactorChallengeClaims struct {
+	ID uuid.UUID `json:"id_user"`
+	jwt.StandardClaims
+}
+
+// TwoFactorLogin second login step, either the totp code or a recovery code
+type TwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+// TwoFactorRole role whose permissions are only granted to accounts with two-factor authentication
+type TwoFactorRole struct {
+	Role       string    `json:"role" db:"role"`
+	EnforcedBy uuid.UUID `json:"enforced_by" db:"enforced_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
@@ -1,17 +1,95 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type User struct {
-	ID          uuid.UUID `json:"id_user" db:"id_user"`
-	Name        string    `json:"name" db:"name"`
-	Email       string    `json:"email" db:"email"`
-	PhoneNumber string    `json:"phone_number" db:"phone_number"`
-	Password    string    `json:"password" db:"password"`
-	ImageUrl    string    `json:"image_url" db:"image_url"`
+	ID                uuid.UUID  `json:"id_user" db:"id_user"`
+	Name              string     `json:"name" db:"name"`
+	Email             string     `json:"email" db:"email"`
+	PhoneNumber       string     `json:"phone_number" db:"phone_number"`
+	Password          string     `json:"-" db:"password"`
+	ImageUrl          string     `json:"image_url" db:"image_url"`
+	Bio               string     `json:"bio" db:"bio"`
+	PickupAddress     string     `json:"pickup_address" db:"pickup_address"`
+	PickupLatitude    string     `json:"pickup_latitude" db:"pickup_latitude"`
+	PickupLongitude   string     `json:"pickup_longitude" db:"pickup_longitude"`
+	Roles             []string   `json:"-" db:"roles"`
+	SuspendedAt       *time.Time `json:"-" db:"suspended_at"`
+	SessionsRevokedAt *time.Time `json:"-" db:"sessions_revoked_at"`
+	EmailVerifiedAt   *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	PhoneVerifiedAt   *time.Time `json:"phone_verified_at,omitempty" db:"phone_verified_at"`
+	// TwoFactorEnabledAt set once a totp enrollment has been confirmed
+	TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at,omitempty" db:"two_factor_enabled_at"`
+}
+
+// IsVerified account has verified both email and phone number
+func (u User) IsVerified() bool {
+	return u.EmailVerifiedAt != nil && u.PhoneVerifiedAt != nil
+}
+
+// HasRole account has been granted the role
+func (u User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasTwoFactor account signs in with a totp code on top of the password
+func (u User) HasTwoFactor() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+// IsSuspended account has been suspended by an admin
+func (u User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+type UserRegister struct {
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+	Password    string `json:"password"`
 }
 
 type UserLogin struct {
 	Email    string `json:"email" db:"email"`
 	Password string `json:"password" db:"password"`
 }
+
+type UserFilter struct {
+	Search string `url:"search"`
+	// Status possible values: active, suspended (empty for all)
+	Status string `url:"status"`
+	Limit  uint64 `url:"limit"`
+	Page   uint64 `url:"page"`
+}
+
+type ProfileUpdate struct {
+	Name            string `json:"name"`
+	PhoneNumber     string `json:"phone_number"`
+	Bio             string `json:"bio"`
+	PickupAddress   string `json:"pickup_address"`
+	PickupLatitude  string `json:"pickup_latitude"`
+	PickupLongitude string `json:"pickup_longitude"`
+}
+
+type PasswordChange struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type AccountDelete struct {
+	Password string `json:"password"`
+}
+
+type RoleInput struct {
+	Role string `json:"role"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"github.com/google/uuid"
+)
+
+type EmailVerificationClaims struct {
+	ID    uuid.UUID `json:"id_user"`
+	Email string    `json:"email"`
+	jwt.StandardClaims
+}
+
+type EmailVerificationInput struct {
+	Token string `json:"token" url:"token"`
+}
+
+type PhoneVerification struct {
+	ID          uuid.UUID  `json:"id_phone_verification" db:"id_phone_verification"`
+	IDUser      uuid.UUID  `json:"id_user" db:"id_user"`
+	PhoneNumber string     `json:"phone_number" db:"phone_number"`
+	CodeHash    string     `json:"-" db:"code_hash"`
+	Attempts    int        `json:"attempts" db:"attempts"`
+	ExpiredAt   time.Time  `json:"expired_at" db:"expired_at"`
+	VerifiedAt  *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+	CreatedAt   time.Time  `json:"created_at,omitempty" db:"created_at"`
+}
+
+type PhoneVerificationInput struct {
+	Code string `json:"code"`
+}
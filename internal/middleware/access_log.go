@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"math/rand"
+	"net/http"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/routerkit"
+	"sharefood/pkg/util"
+	"time"
+)
+
+// NewAccessLog logs the method, route, status, latency, size, user, user agent and request id of the
+// requests. The excluded paths are never logged, the others are sampled except server errors which are
+// always logged
+func NewAccessLog(cfg appctx.AccessLog) func(http.Handler) http.Handler {
+	excluded := make(map[string]bool, len(cfg.Exclude))
+	for _, path := range cfg.Exclude {
+		excluded[path] = true
+	}
+
+	sampleRate := cfg.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = consts.AccessLogSampleRateDefault
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.Enable || excluded[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			rw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			if status < http.StatusInternalServerError && sampleRate < 1 && rand.Float64() >= sampleRate {
+				return
+			}
+
+			logger.AccessLog("access",
+				logger.EventName("access"),
+				logger.String("method", r.Method),
+				logger.String("route", routerkit.Route(r)),
+				logger.String("path", r.URL.Path),
+				logger.Any("status", status),
+				logger.Any("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				logger.Any("bytes", rw.bytes),
+				logger.String("user_id", r.Header.Get(consts.HeaderUserIDKey)),
+				logger.String("user_agent", r.UserAgent()),
+				logger.String("ip", util.ClientIP(r)),
+				logger.String(logger.RequestIDKey, w.Header().Get(consts.HeaderRequestIDKey)),
+			)
+		})
+	}
+}
+
+// statusWriter records the status and size of the response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
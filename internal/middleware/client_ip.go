@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"sharefood/pkg/util"
+)
+
+// NewClientIP resolves once the address of the client behind the trusted proxies, util.ClientIP then
+// returns it for the request
+func NewClientIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := util.WithClientIP(r.Context(), util.ForwardedClientIP(r, trusted))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
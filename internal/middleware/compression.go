@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/pkg/etag"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// encodings the responses are compressed with, by preference when the client accepts several equally
+var encodings = []string{consts.EncodingBrotli, consts.EncodingGzip}
+
+// NewCompression compresses the responses with br or gzip as negotiated by Accept-Encoding. The responses
+// smaller than the minimum size, without a body, already encoded or of a content type that does not
+// compress well are sent as they are. So are the no-store responses, they carry credentials the size of
+// the compressed body would leak next to what the request reflects (BREACH)
+func NewCompression(cfg appctx.Compression) func(http.Handler) http.Handler {
+	minSize := cfg.MinSizeByte
+	if minSize <= 0 {
+		minSize = consts.CompressionMinSizeByteDefault
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the response differs by the encodings accepted, whichever is sent
+			w.Header().Add(consts.HeaderVaryKey, consts.HeaderAcceptEncodingKey)
+
+			encoding := negotiateEncoding(r.Header.Get(consts.HeaderAcceptEncodingKey))
+			if encoding == consts.EncodingIdentity || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize}
+			defer cw.Close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks the encoding of the highest quality the Accept-Encoding header allows,
+// identity when it allows none of them
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return consts.EncodingIdentity
+	}
+
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		if name == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		qualities[name] = q
+	}
+
+	best, bestQ := consts.EncodingIdentity, 0.0
+	for _, encoding := range encodings {
+		q, ok := qualities[encoding]
+		if !ok {
+			q = qualities["*"]
+		}
+
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+
+	return best
+}
+
+// compressible reports whether the content type is text that compresses well
+func compressible(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.Contains(mediaType, "json") ||
+		strings.Contains(mediaType, "javascript") ||
+		strings.Contains(mediaType, "xml")
+}
+
+// noStore reports whether the response is not to be stored, as the ones carrying credentials
+func noStore(h http.Header) bool {
+	for _, directive := range strings.Split(h.Get(consts.HeaderCacheControlKey), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), consts.CacheControlNoStore) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compressWriter buffers the start of the response until it knows whether to compress it, once the
+// minimum size is reached or the handler is done
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status  int
+	buf     []byte
+	decided bool
+	encoder io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.status != 0 || w.decided {
+		return
+	}
+
+	// informational, the response follows
+	if code < http.StatusOK {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+
+	// nothing to compress
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		w.decide()
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.minSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// Flush sends what is buffered so far, compressed or not
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close sends the rest of the response
+func (w *compressWriter) Close() error {
+	if !w.decided {
+		// the handler wrote nothing, net/http answers as it does for any handler
+		if w.status == 0 {
+			return nil
+		}
+
+		if err := w.decide(); err != nil {
+			return err
+		}
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Close()
+	}
+
+	return nil
+}
+
+// decide whether the response is compressed, then writes the header and what is buffered
+func (w *compressWriter) decide() error {
+	w.decided = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	h := w.Header()
+	if h.Get(consts.HeaderContentTypeKey) == "" && len(w.buf) > 0 {
+		// sniffed before compressing, net/http would sniff the compressed bytes
+		h.Set(consts.HeaderContentTypeKey, http.DetectContentType(w.buf))
+	}
+
+	if len(w.buf) >= w.minSize && h.Get(consts.HeaderContentEncodingKey) == "" && compressible(h.Get(consts.HeaderContentTypeKey)) && !noStore(h) {
+		h.Set(consts.HeaderContentEncodingKey, w.encoding)
+		h.Del(consts.HeaderContentLengthKey)
+		if tag := h.Get(consts.HeaderETagKey); tag != "" {
+			// the compressed bytes are not the identity ones, a strong tag must tell them apart
+			h.Set(consts.HeaderETagKey, etag.Encoded(tag, w.encoding))
+		}
+
+		switch w.encoding {
+		case consts.EncodingBrotli:
+			w.encoder = brotli.NewWriter(w.ResponseWriter)
+		default:
+			w.encoder = gzip.NewWriter(w.ResponseWriter)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+
+	return err
+}
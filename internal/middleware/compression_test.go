@@ -0,0 +1,235 @@
+// Package middleware
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+)
+
+// decode body of the response sent with the encoding
+func decode(t *testing.T, encoding string, body io.Reader) string {
+	var r io.Reader
+	switch encoding {
+	case consts.EncodingGzip:
+		gr, err := gzip.NewReader(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gr
+	case consts.EncodingBrotli:
+		r = brotli.NewReader(body)
+	default:
+		r = body
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	testCase := map[string]struct {
+		header   string
+		expected string
+	}{
+		"none":                    {"", consts.EncodingIdentity},
+		"gzip":                    {"gzip", consts.EncodingGzip},
+		"br preferred when equal": {"gzip, br", consts.EncodingBrotli},
+		"higher quality":          {"br;q=0.5, gzip", consts.EncodingGzip},
+		"any":                     {"*", consts.EncodingBrotli},
+		"any but br":              {"*;q=0.1, br;q=0", consts.EncodingGzip},
+		"refused":                 {"br;q=0, gzip;q=0", consts.EncodingIdentity},
+		"identity only":           {"identity", consts.EncodingIdentity},
+		"case and spaces":         {" GZIP ; q=0.8 ", consts.EncodingGzip},
+	}
+
+	for name, tc := range testCase {
+		assert.Equal(t, tc.expected, negotiateEncoding(tc.header), name)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	large := `{"data":"` + strings.Repeat("sharefood ", 20) + `"}`
+	small := `{"data":"ok"}`
+
+	testCase := map[string]struct {
+		method       string
+		accept       string
+		code         int
+		contentType  string
+		encoded      string
+		cacheControl string
+		body         string
+		encoding     string
+	}{
+		"gzip":               {http.MethodGet, "gzip", http.StatusOK, consts.HeaderContentTypeJSON, "", "", large, consts.EncodingGzip},
+		"br":                 {http.MethodGet, "br, gzip", http.StatusOK, consts.HeaderContentTypeJSON, "", "", large, consts.EncodingBrotli},
+		"error response":     {http.MethodPost, "gzip", http.StatusBadRequest, consts.HeaderContentTypeJSON, "", "", large, consts.EncodingGzip},
+		"sniffed text":       {http.MethodGet, "gzip", http.StatusOK, "", "", "", strings.Repeat("plain text ", 20), consts.EncodingGzip},
+		"under min size":     {http.MethodGet, "gzip", http.StatusOK, consts.HeaderContentTypeJSON, "", "", small, ""},
+		"not accepted":       {http.MethodGet, "", http.StatusOK, consts.HeaderContentTypeJSON, "", "", large, ""},
+		"not compressible":   {http.MethodGet, "gzip", http.StatusOK, "image/png", "", "", large, ""},
+		"already encoded":    {http.MethodGet, "gzip", http.StatusOK, consts.HeaderContentTypeJSON, consts.EncodingBrotli, "", large, consts.EncodingBrotli},
+		"no store":           {http.MethodPost, "gzip", http.StatusOK, consts.HeaderContentTypeJSON, "", consts.CacheControlNoStore, large, ""},
+		"no store directive": {http.MethodPost, "gzip", http.StatusOK, consts.HeaderContentTypeJSON, "", "private, No-Store", large, ""},
+		"no content":         {http.MethodDelete, "gzip", http.StatusNoContent, "", "", "", "", ""},
+		"not modified":       {http.MethodGet, "gzip", http.StatusNotModified, consts.HeaderContentTypeJSON, "", "", "", ""},
+		"head":               {http.MethodHead, "gzip", http.StatusOK, consts.HeaderContentTypeJSON, "", "", "", ""},
+	}
+
+	for name, tc := range testCase {
+		t.Run(name, func(t *testing.T) {
+			h := NewCompression(appctx.Compression{MinSizeByte: 64})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.contentType != "" {
+					w.Header().Set(consts.HeaderContentTypeKey, tc.contentType)
+				}
+				if tc.encoded != "" {
+					w.Header().Set(consts.HeaderContentEncodingKey, tc.encoded)
+				}
+				if tc.cacheControl != "" {
+					w.Header().Set(consts.HeaderCacheControlKey, tc.cacheControl)
+				}
+				w.Header().Set(consts.HeaderContentLengthKey, "1")
+				w.WriteHeader(tc.code)
+				w.Write([]byte(tc.body))
+			}))
+
+			req := httptest.NewRequest(tc.method, "/foods", nil)
+			req.Header.Set(consts.HeaderAcceptEncodingKey, tc.accept)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.code, rec.Code)
+			assert.Equal(t, consts.HeaderAcceptEncodingKey, rec.Header().Get(consts.HeaderVaryKey))
+			assert.Equal(t, tc.encoding, rec.Header().Get(consts.HeaderContentEncodingKey))
+			if tc.encoding != "" && tc.encoded == "" {
+				// the length of the body is not known until it is compressed
+				assert.Empty(t, rec.Header().Get(consts.HeaderContentLengthKey))
+				assert.Equal(t, tc.body, decode(t, tc.encoding, rec.Body))
+			} else {
+				assert.Equal(t, tc.body, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCompression_ETag(t *testing.T) {
+	large := `{"data":"` + strings.Repeat("sharefood ", 20) + `"}`
+
+	testCase := map[string]struct {
+		accept   string
+		tag      string
+		expected string
+	}{
+		"identity keeps the tag": {"", `"abc"`, `"abc"`},
+		"gzip strong tag":        {"gzip", `"abc"`, `"abc-gzip"`},
+		"br strong tag":          {"br", `"abc"`, `"abc-br"`},
+		"weak tag is kept":       {"gzip", `W/"abc"`, `W/"abc"`},
+	}
+
+	for name, tc := range testCase {
+		t.Run(name, func(t *testing.T) {
+			h := NewCompression(appctx.Compression{MinSizeByte: 64})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(consts.HeaderContentTypeKey, consts.HeaderContentTypeJSON)
+				w.Header().Set(consts.HeaderETagKey, tc.tag)
+				w.Write([]byte(large))
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/foods/1", nil)
+			req.Header.Set(consts.HeaderAcceptEncodingKey, tc.accept)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expected, rec.Header().Get(consts.HeaderETagKey))
+		})
+	}
+}
+
+func TestCompression_Flush(t *testing.T) {
+	large := strings.Repeat("sharefood ", 20)
+
+	testCase := map[string]struct {
+		first    string
+		encoding string
+	}{
+		"under min size is sent as is": {"chunk ", ""},
+		"over min size is compressed":  {large, consts.EncodingGzip},
+	}
+
+	for name, tc := range testCase {
+		t.Run(name, func(t *testing.T) {
+			var flushed string
+			h := NewCompression(appctx.Compression{MinSizeByte: 64})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(consts.HeaderContentTypeKey, "text/event-stream")
+				w.Write([]byte(tc.first))
+				w.(http.Flusher).Flush()
+
+				// decided once flushed, the rest follows the same way
+				flushed = w.Header().Get(consts.HeaderContentEncodingKey)
+				w.Write([]byte(large))
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			req.Header.Set(consts.HeaderAcceptEncodingKey, "gzip")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.True(t, rec.Flushed)
+			assert.Equal(t, tc.encoding, flushed)
+			assert.Equal(t, tc.encoding, rec.Header().Get(consts.HeaderContentEncodingKey))
+			assert.Equal(t, tc.first+large, decode(t, tc.encoding, rec.Body))
+		})
+	}
+}
+
+func TestCompression_Close(t *testing.T) {
+	testCase := map[string]struct {
+		handler func(w http.ResponseWriter)
+		code    int
+		body    string
+	}{
+		"nothing written": {
+			handler: func(w http.ResponseWriter) {},
+			code:    http.StatusOK,
+		},
+		"status only": {
+			handler: func(w http.ResponseWriter) { w.WriteHeader(http.StatusCreated) },
+			code:    http.StatusCreated,
+		},
+		"buffered under min size": {
+			handler: func(w http.ResponseWriter) { w.Write([]byte(`{"data":"ok"}`)) },
+			code:    http.StatusOK,
+			body:    `{"data":"ok"}`,
+		},
+	}
+
+	for name, tc := range testCase {
+		t.Run(name, func(t *testing.T) {
+			h := NewCompression(appctx.Compression{MinSizeByte: 64})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tc.handler(w)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/foods", nil)
+			req.Header.Set(consts.HeaderAcceptEncodingKey, "gzip")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.code, rec.Code)
+			assert.Empty(t, rec.Header().Get(consts.HeaderContentEncodingKey))
+			assert.Equal(t, tc.body, rec.Body.String())
+		})
+	}
+}
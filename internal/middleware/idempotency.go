@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/pkg/hash"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"strings"
+	"time"
+)
+
+// NewIdempotency replays the first response to a POST, PUT or PATCH sent with an Idempotency-Key to the
+// retries of the request. A key is scoped to the partner api key or user sending it, so it must be registered
+// after the middleware resolving them. A retry while the request is in flight gets a conflict and a key
+// reused for another request is rejected. Server errors, conflicts and throttled requests are not stored so
+// the request can be retried once they are over. The responses carrying credentials are not kept, only
+// that the request was processed, so their retries are refused rather than issued a second credential
+func NewIdempotency(idempotencyRepository repositories.Idempotency) Middleware {
+	return func(next Handler) Handler {
+		return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+			idempotencyKey := r.Header.Get(consts.HeaderIdempotencyKey)
+			if idempotencyKey == "" || !isIdempotencyMethod(r.Method) {
+				return next(w, r, conf)
+			}
+
+			errorEvent := consts.ErrorEvent("idempotency_middleware")
+			response := response.NewResponse("idempotency_middleware", r)
+			ctx := tracer.SpanStart(r.Context(), "idempotency_middleware")
+			defer tracer.SpanFinish(ctx)
+
+			if len(idempotencyKey) > consts.IdempotencyKeyMaxLength {
+				err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.IdempotencyKeyNotValid))
+				tracer.SpanError(ctx, err)
+				return *response.Failed(ctx, nil, err)
+			}
+
+			bodyMaxByte := conf.Idempotency.BodyMaxByte
+			if bodyMaxByte < 1 {
+				bodyMaxByte = consts.IdempotencyBodyMaxByteDefault
+			}
+
+			// the body is held in memory to be hashed, its size is capped before reading it
+			var body []byte
+			save, readBody, err := drainBody(http.MaxBytesReader(w, r.Body, bodyMaxByte))
+			if err == nil {
+				body, err = ioutil.ReadAll(readBody)
+			}
+			if err != nil {
+				logger.Warn(logger.MessageFormat("[idempotency] cannot read request body, error %v", err))
+				err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+				if isBodyTooLarge(err) {
+					err = errorEvent.WithCode(consts.CodeRequestTooLarge).WrapError(consts.Error(consts.IdempotencyBodyTooLarge))
+				}
+				tracer.SpanError(ctx, err)
+				return *response.Failed(ctx, nil, err)
+			}
+			r.Body = save
+
+			key := idempotencySubject(r) + ":" + idempotencyKey
+			requestHash := hash.SHA256(strings.Join([]string{r.Method, canonicalPath(r), string(body)}, "\n"))
+
+			ttlHour := conf.Idempotency.TTLHour
+			if ttlHour < 1 {
+				ttlHour = consts.IdempotencyTTLHourDefault
+			}
+
+			lockSecond := conf.Idempotency.LockSecond
+			if lockSecond < 1 {
+				lockSecond = consts.IdempotencyLockSecondDefault
+			}
+
+			stored, found, err := idempotencyRepository.Get(ctx, key)
+			if err != nil {
+				err := errorEvent.WrapError(err)
+				tracer.SpanError(ctx, err)
+				return *response.Failed(ctx, nil, err)
+			}
+			if found {
+				return replayIdempotent(w, r, stored, requestHash)
+			}
+
+			token, locked, err := idempotencyRepository.Lock(ctx, key, time.Duration(lockSecond)*time.Second)
+			if err != nil {
+				err := errorEvent.WrapError(err)
+				tracer.SpanError(ctx, err)
+				return *response.Failed(ctx, nil, err)
+			}
+			if !locked {
+				err := errorEvent.WithCode(consts.CodeDuplicateEntry).WrapError(consts.Error(consts.IdempotencyKeyInFlight))
+				tracer.SpanError(ctx, err)
+				return *response.Failed(ctx, nil, err)
+			}
+			defer func() {
+				if err := idempotencyRepository.Unlock(ctx, key, token); err != nil {
+					logger.Error(logger.MessageFormat("[idempotency] %v", err))
+				}
+			}()
+
+			// the first request may have finished between the lookup and the lock
+			stored, found, err = idempotencyRepository.Get(ctx, key)
+			if err != nil {
+				err := errorEvent.WrapError(err)
+				tracer.SpanError(ctx, err)
+				return *response.Failed(ctx, nil, err)
+			}
+			if found {
+				return replayIdempotent(w, r, stored, requestHash)
+			}
+
+			resp := next(w, r, conf)
+			if !isIdempotentFinal(resp.Code) {
+				return resp
+			}
+
+			stored = entity.IdempotentResponse{RequestHash: requestHash, Code: resp.Code, Secret: resp.Secret}
+			if !resp.Secret {
+				stored.Body, err = json.Marshal(resp)
+			}
+			if err == nil {
+				err = idempotencyRepository.Save(ctx, key, stored, time.Duration(ttlHour)*time.Hour)
+			}
+			if err != nil {
+				// the request went through, only its retries are not replayed
+				logger.Error(logger.MessageFormat("[idempotency] cannot store response, error %v", err))
+			}
+
+			return resp
+		}
+	}
+}
+
+// replayIdempotent the stored response, when the retry is the same request
+func replayIdempotent(w http.ResponseWriter, r *http.Request, stored entity.IdempotentResponse, requestHash string) appctx.Response {
+	errorEvent := consts.ErrorEvent("idempotency_middleware")
+	response := response.NewResponse("idempotency_middleware", r)
+	ctx := r.Context()
+
+	if stored.RequestHash != requestHash {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.IdempotencyKeyReused))
+		tracer.SpanError(ctx, err)
+		return *response.Failed(ctx, nil, err)
+	}
+
+	if stored.Secret {
+		err := errorEvent.WithCode(consts.CodeDuplicateEntry).WrapError(consts.Error(consts.IdempotencyNotReplayed))
+		tracer.SpanError(ctx, err)
+		return *response.Failed(ctx, nil, err)
+	}
+
+	resp := appctx.Response{}
+	if err := json.Unmarshal(stored.Body, &resp); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return *response.Failed(ctx, nil, err)
+	}
+	resp.Code = stored.Code
+
+	w.Header().Set(consts.HeaderIdempotentReplayedKey, "true")
+
+	return resp
+}
+
+// idempotencySubject whom the idempotency key belongs to
+func idempotencySubject(r *http.Request) string {
+	if key, ok := r.Context().Value(consts.CtxPartnerAPIKey).(entity.PartnerAPIKey); ok {
+		return "api_key:" + key.ID.String()
+	}
+
+	if user, ok := r.Context().Value(consts.CtxUserInfo).(entity.User); ok {
+		return "user:" + user.ID.String()
+	}
+
+	return "ip:" + util.ClientIP(r)
+}
+
+// canonicalPath path of the request under its api version, the unversioned alias of a route
+// and its versioned path are the same request
+func canonicalPath(r *http.Request) string {
+	if path, ok := r.Context().Value(consts.CtxCanonicalPath).(string); ok {
+		return path
+	}
+
+	return r.URL.Path
+}
+
+// isBodyTooLarge whether err is http.MaxBytesReader refusing to read past its limit, the error
+// only has a type since go 1.19
+func isBodyTooLarge(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if err.Error() == "http: request body too large" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isIdempotentFinal whether the response of the code is the outcome of the request, the conflicts and
+// the throttled requests are worth retrying with the same key
+func isIdempotentFinal(code int) bool {
+	return code >= http.StatusOK && code < consts.CodeInternalServerError &&
+		code != consts.CodeDuplicateEntry && code != consts.CodeReachMaxLimit
+}
+
+func isIdempotencyMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
@@ -0,0 +1,161 @@
+// Package middleware
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/cache"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	setup := func(t *testing.T) (*miniredis.Miniredis, repositories.Idempotency, *int) {
+		srv := miniredis.RunT(t)
+		return srv, repositories.NewIdempotencyRepository(cache.NewCache(redis.NewClient(&redis.Options{Addr: srv.Addr()}))), new(int)
+	}
+
+	created := func(calls *int) Handler {
+		return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+			*calls++
+			return *appctx.NewResponse().WithCode(consts.CodeCreated).WithData(*calls)
+		}
+	}
+
+	send := func(h Handler, conf *appctx.Config, path string, body string, canonical string) (appctx.Response, http.Header) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(consts.HeaderIdempotencyKey, "key-1")
+		if canonical != "" {
+			req = req.WithContext(context.WithValue(req.Context(), consts.CtxCanonicalPath, canonical))
+		}
+
+		w := httptest.NewRecorder()
+		return h(w, req, conf), w.Header()
+	}
+
+	t.Run("test retry is replayed", func(t *testing.T) {
+		_, repository, calls := setup(t)
+		h := NewIdempotency(repository)(created(calls))
+
+		first, _ := send(h, &appctx.Config{}, "/v1/foods", `{"name":"nasi"}`, "")
+		retry, header := send(h, &appctx.Config{}, "/v1/foods", `{"name":"nasi"}`, "")
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, first.Code, retry.Code)
+		assert.Equal(t, "true", header.Get(consts.HeaderIdempotentReplayedKey))
+	})
+
+	t.Run("test retry on the unversioned alias is replayed", func(t *testing.T) {
+		_, repository, calls := setup(t)
+		h := NewIdempotency(repository)(created(calls))
+
+		send(h, &appctx.Config{}, "/v1/foods", `{"name":"nasi"}`, "")
+		retry, _ := send(h, &appctx.Config{}, "/foods", `{"name":"nasi"}`, "/v1/foods")
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, consts.CodeCreated, retry.Code)
+	})
+
+	t.Run("test key reused for another body", func(t *testing.T) {
+		_, repository, calls := setup(t)
+		h := NewIdempotency(repository)(created(calls))
+
+		send(h, &appctx.Config{}, "/v1/foods", `{"name":"nasi"}`, "")
+		retry, _ := send(h, &appctx.Config{}, "/v1/foods", `{"name":"soto"}`, "")
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, consts.CodeUnprocessableEntity, retry.Code)
+	})
+
+	t.Run("test body over the limit", func(t *testing.T) {
+		_, repository, calls := setup(t)
+		h := NewIdempotency(repository)(created(calls))
+		conf := &appctx.Config{Idempotency: appctx.Idempotency{BodyMaxByte: 8}}
+
+		resp, _ := send(h, conf, "/v1/foods", `{"name":"nasi"}`, "")
+
+		assert.Equal(t, consts.CodeRequestTooLarge, resp.Code)
+		assert.Equal(t, 0, *calls)
+	})
+
+	t.Run("test throttled and conflicting requests are not stored", func(t *testing.T) {
+		for _, code := range []int{consts.CodeReachMaxLimit, consts.CodeDuplicateEntry, consts.CodeServerBusy} {
+			_, repository, calls := setup(t)
+			h := NewIdempotency(repository)(func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+				*calls++
+				if *calls == 1 {
+					return *appctx.NewResponse().WithCode(code)
+				}
+				return *appctx.NewResponse().WithCode(consts.CodeCreated)
+			})
+
+			first, _ := send(h, &appctx.Config{}, "/v1/foods", `{"name":"nasi"}`, "")
+			retry, header := send(h, &appctx.Config{}, "/v1/foods", `{"name":"nasi"}`, "")
+
+			assert.Equal(t, code, first.Code)
+			assert.Equal(t, consts.CodeCreated, retry.Code, code)
+			assert.Equal(t, 2, *calls)
+			assert.Empty(t, header.Get(consts.HeaderIdempotentReplayedKey))
+		}
+	})
+
+	t.Run("test issued credential is not replayed", func(t *testing.T) {
+		srv, repository, calls := setup(t)
+		h := NewIdempotency(repository)(func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+			*calls++
+			return *appctx.NewResponse().WithCode(consts.CodeCreated).WithData(map[string]string{"secret": "sk_live_issued"}).WithSecret()
+		})
+
+		first, _ := send(h, &appctx.Config{}, "/v1/partner/keys", `{"name":"acme"}`, "")
+		retry, header := send(h, &appctx.Config{}, "/v1/partner/keys", `{"name":"acme"}`, "")
+
+		assert.Equal(t, consts.CodeCreated, first.Code)
+		assert.True(t, first.Secret)
+		// the retry neither issues a second credential nor hands out the first one
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, consts.CodeDuplicateEntry, retry.Code)
+		assert.Nil(t, retry.Data)
+		assert.Empty(t, header.Get(consts.HeaderIdempotentReplayedKey))
+
+		for _, key := range srv.Keys() {
+			value, _ := srv.Get(key)
+			assert.NotContains(t, value, "sk_live_issued")
+		}
+	})
+
+	t.Run("test request outliving its lock leaves the lock of the retry", func(t *testing.T) {
+		srv, repository, _ := setup(t)
+		conf := &appctx.Config{Idempotency: appctx.Idempotency{LockSecond: 1}}
+		lockKey := consts.IdempotencyLockKeyPrefix + "ip:192.0.2.1:key-1"
+
+		var retryToken string
+		slow := func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+			// the lock expires while the request runs and a retry takes it
+			srv.FastForward(2 * time.Second)
+			assert.False(t, srv.Exists(lockKey))
+
+			token, ok, err := repository.Lock(r.Context(), "ip:192.0.2.1:key-1", time.Minute)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			retryToken = token
+
+			return *appctx.NewResponse().WithCode(consts.CodeServerBusy)
+		}
+
+		send(NewIdempotency(repository)(slow), conf, "/v1/foods", `{"name":"nasi"}`, "")
+
+		held, err := srv.Get(lockKey)
+		assert.NoError(t, err)
+		assert.Equal(t, retryToken, held)
+	})
+}
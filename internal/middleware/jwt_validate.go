@@ -1,57 +1,114 @@
 package middleware
 
 import (
-	"fmt"
+	"context"
 	"net/http"
 	"sharefood/internal/appctx"
 	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
 	"sharefood/internal/response"
+	"sharefood/pkg/jwtx"
 	"sharefood/pkg/logger"
 	"sharefood/pkg/tracer"
 	"strings"
-
-	"github.com/dgrijalva/jwt-go/v4"
 )
 
-func ValidateBearerToken(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
-	errorEvent := consts.ErrorEvent("validate_bearer_token_middleware")
-	response := response.NewResponse("validate_bearer_token_middleware", r)
-	ctx := tracer.SpanStart(r.Context(), "validate_bearer_token_middleware")
-	defer tracer.SpanFinish(ctx)
-
-	authToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
-	if len(authToken) == 0 {
-		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.StatusUnauthorized))
-		logger.Error(logger.MessageFormat("[user-login] parsing body request error:"))
-		return NewError(*response.Failed(ctx, nil, err))
-	}
+// NewValidateBearerToken validates the bearer token and rejects the token of a missing or suspended
+// account or issued before the account sessions were revoked. The token must be signed by one of the keys of the set.
+// The roles requiring two-factor authentication are withheld from an account without it
+func NewValidateBearerToken(userRepository repositories.User, roleRepository repositories.Role, keys *jwtx.KeySet) MiddlewareFunc {
+	return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
+		errorEvent := consts.ErrorEvent("validate_bearer_token_middleware")
+		response := response.NewResponse("validate_bearer_token_middleware", r)
+		ctx := tracer.SpanStart(r.Context(), "validate_bearer_token_middleware")
+		defer tracer.SpanFinish(ctx)
 
-	secret := []byte(conf.App.JWTSecret)
-	token, errToken := jwt.Parse(authToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("parsing error")
-		}
-		return secret, nil
-	})
-	if errToken != nil {
-		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(errToken)
-		tracer.SpanError(ctx, err)
-		return NewError(*response.Failed(ctx, nil, err))
-	}
+		authToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if len(authToken) == 0 {
+			err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.StatusUnauthorized))
+			logger.Error(logger.MessageFormat("[user-login] parsing body request error:"))
+			return NewError(*response.Failed(ctx, nil, err))
+		}
+
+		claims := entity.TokenClaims{}
+		token, errToken := keys.Parse(authToken, &claims)
+		if errToken != nil {
+			err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(errToken)
+			tracer.SpanError(ctx, err)
+			return NewError(*response.Failed(ctx, nil, err))
+		}
+
+		if !token.Valid {
+			err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.TokenNotValid))
+			tracer.SpanError(ctx, err)
+			return NewError(*response.Failed(ctx, nil, err))
+		}
+
+		user, errUser := userRepository.GetByID(ctx, claims.ID)
+		if errUser != nil {
+			err := errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(consts.Error(consts.TokenNotValid))
+			tracer.SpanError(ctx, err)
+			return NewError(*response.Failed(ctx, nil, err))
+		}
+
+		// the issue and revocation times are both kept to the microsecond, a token issued at the
+		// revocation is revoked with the others
+		if user.SessionsRevokedAt != nil && (claims.IssuedAt == nil || !claims.IssuedAt.After(*user.SessionsRevokedAt)) {
+			err := errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(consts.Error(consts.SessionRevoked))
+			tracer.SpanError(ctx, err)
+			return NewError(*response.Failed(ctx, nil, err))
+		}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.TokenNotValid))
-		tracer.SpanError(ctx, err)
-		return NewError(*response.Failed(ctx, nil, err))
+		if user.IsSuspended() {
+			err := errorEvent.WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.AccountSuspended))
+			tracer.SpanError(ctx, err)
+			return NewError(*response.Failed(ctx, nil, err))
+		}
+
+		// tokens signed before roles were added to the claims carry none
+		if claims.Roles == nil {
+			claims.Roles = user.Roles
+		}
+
+		if !user.HasTwoFactor() && len(claims.Roles) > 0 {
+			required, errRoles := roleRepository.ListTwoFactor(ctx)
+			if errRoles != nil {
+				err := errorEvent.WrapError(errRoles)
+				tracer.SpanError(ctx, err)
+				return NewError(*response.Failed(ctx, nil, err))
+			}
+
+			claims.Roles, claims.WithheldRoles = withholdRoles(claims.Roles, required)
+		}
+
+		r.Header.Set("idUser", claims.ID.String())
+		reqCtx := context.WithValue(r.Context(), consts.CtxUserInfo, user)
+		reqCtx = context.WithValue(reqCtx, consts.CtxTokenClaims, claims)
+		*r = *r.WithContext(reqCtx)
+
+		return nil
 	}
+}
 
-	idUser := claims["id_user"].(string)
-	r.Header.Set("idUser", idUser)
+// withholdRoles splits the roles into the ones granted and the ones requiring two-factor authentication
+func withholdRoles(roles []string, required []entity.TwoFactorRole) (granted []string, withheld []string) {
+	granted = []string{}
+	for _, role := range roles {
+		held := false
+		for _, r := range required {
+			if r.Role == role {
+				held = true
+				break
+			}
+		}
 
-	// role := claims.(jwt.MapClaims)["role"].(string)
-	// fmt.Println(id_user)
-	// r.Header.Set("role", role)
+		if held {
+			withheld = append(withheld, role)
+			continue
+		}
+		granted = append(granted, role)
+	}
 
-	return nil
+	return granted, withheld
 }
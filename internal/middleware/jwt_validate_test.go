@@ -0,0 +1,66 @@
+// Package middleware
+package middleware
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/jwtx"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBearerToken_SessionsRevoked(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	signing, _ := jwtx.NewSigningKey("test", private)
+	keys, _ := jwtx.NewKeySet(signing)
+
+	// in the middle of a second, a token issued earlier in the same second is revoked too
+	revokedAt := time.Date(2026, 10, 19, 10, 0, 0, 500000000, time.UTC)
+	user := entity.User{ID: uuid.New(), SessionsRevokedAt: &revokedAt}
+	mf := NewValidateBearerToken(&fakeUserRepository{users: map[uuid.UUID]entity.User{user.ID: user}}, nil, keys)
+
+	testCase := map[string]struct {
+		issuedAt time.Time
+		valid    bool
+	}{
+		"issued in an earlier second":       {revokedAt.Add(-time.Second), false},
+		"issued earlier in the same second": {revokedAt.Add(-100 * time.Millisecond), false},
+		"issued at the revocation":          {revokedAt, false},
+		"issued after the revocation":       {revokedAt.Add(time.Millisecond), true},
+	}
+
+	for name, tc := range testCase {
+		token, err := keys.Sign(entity.TokenClaims{
+			ID: user.ID,
+			StandardClaims: jwt.StandardClaims{
+				IssuedAt:  jwt.At(tc.issuedAt),
+				ExpiresAt: jwt.At(time.Now().Add(time.Hour)),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		err = mf(httptest.NewRecorder(), req, &appctx.Config{})
+
+		if tc.valid {
+			assert.NoError(t, err, name)
+			continue
+		}
+		if e, ok := err.(Error); assert.True(t, ok, name) {
+			assert.Equal(t, consts.CodeAuthenticationFailure, e.Response.Code, name)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewMaintenance answers service unavailable while the maintenance mode is on, the calls from the allowed
+// ips and with a token of the staff, moderators and admins, still get through so the admin routes keep
+// working. The state is read again every few seconds so a toggle reaches every instance without a
+// restart. It is registered ahead of the bearer token validation so the clients get the maintenance
+// answer rather than the one of a database down for it; the token is only checked against the keys
+// here, the validation after still applies
+func NewMaintenance(maintenanceRepository repositories.Maintenance, allowed []*net.IPNet, keys *jwtx.KeySet) MiddlewareFunc {
+	var (
+		mu       sync.Mutex
+		state    entity.Maintenance
+		loadedAt time.Time
+		loading  bool
+	)
+
+	// current state, one request reads it again once it is stale while the others go on with the
+	// state read before rather than wait on redis
+	current := func(ctx context.Context) entity.Maintenance {
+		mu.Lock()
+		if loading || time.Since(loadedAt) < consts.MaintenanceRefreshSecond*time.Second {
+			defer mu.Unlock()
+			return state
+		}
+		loading = true
+		mu.Unlock()
+
+		loaded, err := maintenanceRepository.Get(ctx)
+		if err != nil {
+			logger.Error(logger.MessageFormat("[maintenance] %v", err))
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		state, loadedAt, loading = loaded, time.Now(), false
+
+		return state
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
+		errorEvent := consts.ErrorEvent("maintenance_middleware")
+		ctx := tracer.SpanStart(r.Context(), "maintenance_middleware")
+		defer tracer.SpanFinish(ctx)
+
+		state := current(ctx)
+		if !state.Enabled || util.ContainsIP(allowed, util.ClientIP(r)) {
+			return nil
+		}
+
+		if staff(r, keys) {
+			return nil
+		}
+
+		retryAfter := state.RetryAfterSecond
+		if retryAfter < 1 {
+			retryAfter = consts.MaintenanceRetryAfterSecondDefault
+		}
+		w.Header().Set(consts.HeaderRetryAfterKey, strconv.Itoa(retryAfter))
+
+		err := errorEvent.WithCode(consts.CodeServerBusy).WrapError(consts.Error(consts.MaintenanceMode))
+		tracer.SpanError(ctx, err)
+		return NewError(*appctx.NewResponse().
+			WithCode(consts.CodeServerBusy).
+			WithMsgKey(consts.RespMaintenance).
+			WithError(consts.MaintenanceMode).
+			WithStatus(consts.StatusFailed).
+			WithEntity("maintenance").
+			WithState("maintenance"), WithError(err))
+	}
+}
+
+// staff whether the request carries a token signed by the keys and carrying one of the permissions to
+// manage the accounts or the service
+func staff(r *http.Request, keys *jwtx.KeySet) bool {
+	authToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if authToken == "" {
+		return false
+	}
+
+	claims := entity.TokenClaims{}
+	token, err := keys.Parse(authToken, &claims)
+	if err != nil || !token.Valid {
+		return false
+	}
+
+	for _, permission := range consts.ManagementPermissions {
+		if claims.HasPermission(permission) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,198 @@
+// Package middleware
+package middleware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/jwtx"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMaintenanceRepository serves state, a read signals started and waits for release when they are set
+type fakeMaintenanceRepository struct {
+	repositories.Maintenance
+	state   entity.Maintenance
+	reads   int
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *fakeMaintenanceRepository) Get(context.Context) (entity.Maintenance, error) {
+	if r.started != nil {
+		close(r.started)
+		<-r.release
+		return r.state, nil
+	}
+
+	r.reads++
+	return r.state, nil
+}
+
+// fakeRoleRepository requires two-factor authentication of no role
+type fakeRoleRepository struct {
+	repositories.Role
+}
+
+func (r *fakeRoleRepository) ListTwoFactor(context.Context) ([]entity.TwoFactorRole, error) {
+	return nil, nil
+}
+
+func TestMaintenance(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	signing, _ := jwtx.NewSigningKey("test", private)
+	keys, _ := jwtx.NewKeySet(signing)
+
+	_, otherPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	otherSigning, _ := jwtx.NewSigningKey("test", otherPrivate)
+	otherKeys, _ := jwtx.NewKeySet(otherSigning)
+
+	bearer := func(keys *jwtx.KeySet, roles ...string) string {
+		token, err := keys.Sign(entity.TokenClaims{
+			ID:             uuid.New(),
+			Roles:          roles,
+			StandardClaims: jwt.StandardClaims{ExpiresAt: jwt.At(time.Now().Add(time.Hour))},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return "Bearer " + token
+	}
+
+	_, allowed, _ := net.ParseCIDR("10.0.0.0/8")
+
+	serve := func(mf MiddlewareFunc, remoteAddr string, authorization string) (error, http.Header) {
+		req := httptest.NewRequest(http.MethodGet, "/foods", nil)
+		req.RemoteAddr = remoteAddr
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		w := httptest.NewRecorder()
+		return mf(w, req, &appctx.Config{}), w.Header()
+	}
+
+	t.Run("test off", func(t *testing.T) {
+		mf := NewMaintenance(&fakeMaintenanceRepository{}, []*net.IPNet{allowed}, keys)
+
+		err, _ := serve(mf, "192.0.2.1:1234", "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("test on", func(t *testing.T) {
+		repository := &fakeMaintenanceRepository{state: entity.Maintenance{Enabled: true, RetryAfterSecond: 120}}
+		mf := NewMaintenance(repository, []*net.IPNet{allowed}, keys)
+
+		testCase := map[string]struct {
+			remoteAddr    string
+			authorization string
+			through       bool
+		}{
+			"anonymous":                {"192.0.2.1:1234", "", false},
+			"allowed ip":               {"10.1.2.3:1234", "", true},
+			"token managing it":        {"192.0.2.1:1234", bearer(keys, consts.RoleAdmin), true},
+			"token of a moderator":     {"192.0.2.1:1234", bearer(keys, consts.RoleModerator), true},
+			"token of a giver":         {"192.0.2.1:1234", bearer(keys, consts.RoleGiver), false},
+			"token of other keys":      {"192.0.2.1:1234", bearer(otherKeys, consts.RoleAdmin), false},
+			"token not valid, not 401": {"192.0.2.1:1234", "Bearer not-a-token", false},
+		}
+
+		for name, tc := range testCase {
+			err, header := serve(mf, tc.remoteAddr, tc.authorization)
+			if tc.through {
+				assert.NoError(t, err, name)
+				continue
+			}
+
+			if e, ok := err.(Error); assert.True(t, ok, name) {
+				assert.Equal(t, consts.CodeServerBusy, e.Response.Code, name)
+			}
+			assert.Equal(t, "120", header.Get(consts.HeaderRetryAfterKey), name)
+		}
+
+		// read once, then served from memory until it is stale
+		assert.Equal(t, 1, repository.reads)
+	})
+
+	t.Run("test admin route", func(t *testing.T) {
+		repository := &fakeMaintenanceRepository{state: entity.Maintenance{Enabled: true}}
+		users := &fakeUserRepository{users: map[uuid.UUID]entity.User{}}
+
+		// as the users list is routed, the maintenance ahead of the token validation
+		h := NewChain(
+			Before(NewMaintenance(repository, nil, keys)),
+			Before(NewValidateBearerToken(users, &fakeRoleRepository{}, keys)),
+			Before(RequirePermission(consts.PermissionUserRead)),
+		).Then(func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+			return *appctx.NewResponse().WithCode(consts.CodeSuccess)
+		})
+
+		testCase := map[string]struct {
+			role string
+			code int
+		}{
+			"moderator": {consts.RoleModerator, consts.CodeSuccess},
+			"admin":     {consts.RoleAdmin, consts.CodeSuccess},
+			"giver":     {consts.RoleGiver, consts.CodeServerBusy},
+		}
+
+		for name, tc := range testCase {
+			user := entity.User{ID: uuid.New(), Roles: []string{tc.role}}
+			users.users[user.ID] = user
+
+			token, err := keys.Sign(entity.TokenClaims{
+				ID:             user.ID,
+				Roles:          user.Roles,
+				StandardClaims: jwt.StandardClaims{ExpiresAt: jwt.At(time.Now().Add(time.Hour))},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			resp := h(httptest.NewRecorder(), req, &appctx.Config{})
+			assert.Equal(t, tc.code, resp.Code, name)
+		}
+	})
+
+	t.Run("test reading the state again does not hold the other requests", func(t *testing.T) {
+		repository := &fakeMaintenanceRepository{
+			state:   entity.Maintenance{Enabled: true},
+			started: make(chan struct{}),
+			release: make(chan struct{}),
+		}
+		mf := NewMaintenance(repository, nil, keys)
+
+		// the first request reads the state and waits on redis
+		done := make(chan error)
+		go func() {
+			err, _ := serve(mf, "192.0.2.1:1234", "")
+			done <- err
+		}()
+		<-repository.started
+
+		// answered right away with the state read before, none yet
+		err, _ := serve(mf, "192.0.2.1:1234", "")
+		assert.NoError(t, err)
+
+		close(repository.release)
+		assert.Error(t, <-done)
+	})
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"sharefood/pkg/routerkit"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "http",
+		Name:      "requests_total",
+		Help:      "Number of http requests, by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the http requests, by method, route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// NewMetrics counts the requests and observes their latency by route template, so the paths
+// carrying ids do not each get their own series
+func NewMetrics() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			labels := prometheus.Labels{
+				"method": r.Method,
+				"route":  routerkit.Route(r),
+				"status": strconv.Itoa(status),
+			}
+			httpRequests.With(labels).Inc()
+			httpRequestDuration.With(labels).Observe(time.Since(start).Seconds())
+		})
+	}
+}
@@ -5,16 +5,77 @@ import (
 	"net/http"
 
 	"sharefood/internal/appctx"
+	"sharefood/pkg/logger"
+
+	"github.com/pkg/errors"
 )
 
 // MiddlewareFunc is contract for middleware and must implement this type for http if need middleware http request
 type MiddlewareFunc func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error
 
-// FilterFunc is a iterator resolver in each middleware registered
-func FilterFunc(w http.ResponseWriter, r *http.Request, conf *appctx.Config, mfs []MiddlewareFunc) error {
-	for _, mf := range mfs {
-		return mf(w, r, conf)
+// Handler serves a request once every middleware in front of it let it through
+type Handler func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response
+
+// Middleware wraps the next handler of a chain, it short-circuits by answering without calling
+// next and can see or change the response next returns
+type Middleware func(next Handler) Handler
+
+// Chain is an ordered list of middlewares, the first one is the outermost
+type Chain []Middleware
+
+// NewChain initialize a chain running mws in order
+func NewChain(mws ...Middleware) Chain {
+	return append(Chain{}, mws...)
+}
+
+// Append returns a new chain running mws after the middlewares of c, c is left untouched
+func (c Chain) Append(mws ...Middleware) Chain {
+	chain := make(Chain, 0, len(c)+len(mws))
+	chain = append(chain, c...)
+
+	return append(chain, mws...)
+}
+
+// Then wraps h with every middleware of the chain
+func (c Chain) Then(h Handler) Handler {
+	for i := len(c) - 1; i >= 0; i-- {
+		h = c[i](h)
 	}
 
-	return nil
+	return h
+}
+
+// Before runs mf ahead of the rest of the chain, its error becomes the response and
+// nothing after it runs
+func Before(mf MiddlewareFunc) Middleware {
+	return func(next Handler) Handler {
+		return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+			if err := mf(w, r, conf); err != nil {
+				logger.Error(errors.Wrap(err, "error on middleware"))
+
+				if e, ok := err.(Error); ok {
+					return e.Response
+				}
+
+				return *appctx.NewResponse().
+					WithCode(http.StatusInternalServerError).
+					WithMessage(http.StatusText(http.StatusInternalServerError))
+			}
+
+			return next(w, r, conf)
+		}
+	}
+}
+
+// After runs fn on the response of the rest of the chain, including the ones of the middlewares
+// after it that short-circuit
+func After(fn func(r *http.Request, resp *appctx.Response)) Middleware {
+	return func(next Handler) Handler {
+		return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+			resp := next(w, r, conf)
+			fn(r, &resp)
+
+			return resp
+		}
+	}
 }
@@ -0,0 +1,138 @@
+// Package middleware
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// trace middleware recording when it is entered and left
+func trace(calls *[]string, name string) Middleware {
+	return func(next Handler) Handler {
+		return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+			*calls = append(*calls, name+" in")
+			resp := next(w, r, conf)
+			*calls = append(*calls, name+" out")
+
+			return resp
+		}
+	}
+}
+
+func handler(calls *[]string) Handler {
+	return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+		*calls = append(*calls, "handler")
+		return *appctx.NewResponse().WithCode(consts.CodeSuccess)
+	}
+}
+
+func serve(h Handler) appctx.Response {
+	return h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), &appctx.Config{})
+}
+
+func TestChain_Then(t *testing.T) {
+	calls := []string{}
+
+	resp := serve(NewChain(trace(&calls, "a"), trace(&calls, "b")).Then(handler(&calls)))
+
+	assert.Equal(t, consts.CodeSuccess, resp.Code)
+	assert.Equal(t, []string{"a in", "b in", "handler", "b out", "a out"}, calls)
+}
+
+func TestChain_Append(t *testing.T) {
+	calls := []string{}
+	outer := NewChain(trace(&calls, "outer"))
+
+	// nested groups append to the chain of their parent, siblings do not see each other
+	first := outer.Append(trace(&calls, "first"))
+	second := outer.Append(trace(&calls, "second"))
+
+	serve(first.Then(handler(&calls)))
+	assert.Equal(t, []string{"outer in", "first in", "handler", "first out", "outer out"}, calls)
+
+	calls = calls[:0]
+	serve(second.Then(handler(&calls)))
+	assert.Equal(t, []string{"outer in", "second in", "handler", "second out", "outer out"}, calls)
+
+	assert.Len(t, outer, 1)
+}
+
+func TestBefore(t *testing.T) {
+	pass := func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
+		return nil
+	}
+	reject := func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
+		return NewError(*appctx.NewResponse().WithCode(consts.CodeForbidden))
+	}
+	fail := func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
+		return errors.New("boom")
+	}
+
+	t.Run("test let through", func(t *testing.T) {
+		calls := []string{}
+		resp := serve(NewChain(Before(pass), trace(&calls, "next")).Then(handler(&calls)))
+
+		assert.Equal(t, consts.CodeSuccess, resp.Code)
+		assert.Equal(t, []string{"next in", "handler", "next out"}, calls)
+	})
+
+	t.Run("test error response short-circuits", func(t *testing.T) {
+		calls := []string{}
+		resp := serve(NewChain(trace(&calls, "outer"), Before(reject), trace(&calls, "next")).Then(handler(&calls)))
+
+		assert.Equal(t, consts.CodeForbidden, resp.Code)
+		assert.Equal(t, []string{"outer in", "outer out"}, calls)
+	})
+
+	t.Run("test other error answers 500", func(t *testing.T) {
+		calls := []string{}
+		resp := serve(NewChain(Before(fail)).Then(handler(&calls)))
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.Empty(t, calls)
+	})
+}
+
+func TestAfter(t *testing.T) {
+	reject := func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
+		return NewError(*appctx.NewResponse().WithCode(consts.CodeForbidden))
+	}
+	seen := func(codes *[]int) Middleware {
+		return After(func(r *http.Request, resp *appctx.Response) {
+			*codes = append(*codes, resp.Code)
+			resp.WithETag(`"seen"`)
+		})
+	}
+
+	t.Run("test sees the response of the handler", func(t *testing.T) {
+		calls, codes := []string{}, []int{}
+		resp := serve(NewChain(seen(&codes)).Then(handler(&calls)))
+
+		assert.Equal(t, []int{consts.CodeSuccess}, codes)
+		assert.Equal(t, `"seen"`, resp.ETag)
+	})
+
+	t.Run("test sees the response of a middleware after it", func(t *testing.T) {
+		calls, codes := []string{}, []int{}
+		resp := serve(NewChain(seen(&codes), Before(reject)).Then(handler(&calls)))
+
+		assert.Equal(t, []int{consts.CodeForbidden}, codes)
+		assert.Equal(t, `"seen"`, resp.ETag)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("test does not see a middleware before it", func(t *testing.T) {
+		calls, codes := []string{}, []int{}
+		resp := serve(NewChain(Before(reject), seen(&codes)).Then(handler(&calls)))
+
+		assert.Empty(t, codes)
+		assert.Empty(t, resp.ETag)
+	})
+}
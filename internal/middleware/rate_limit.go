@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/msg"
+	"sharefood/pkg/ratelimit"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewRateLimit limits the calls of the route to the limit of the rule per window, the calls of each
+// user, api key or ip are counted apart. It must be registered after the middleware resolving the user
+// or api key the rule counts by
+func NewRateLimit(limiter *ratelimit.Limiter, rule appctx.RateLimitRoute) MiddlewareFunc {
+	window := time.Duration(rule.WindowSecond) * time.Second
+	if rule.WindowSecond < 1 {
+		window = consts.RateLimitWindowSecondDefault * time.Second
+	}
+
+	route := strings.ToUpper(rule.Method) + ":" + rule.Path
+
+	return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
+		errorEvent := consts.ErrorEvent("rate_limit_middleware")
+		ctx := tracer.SpanStart(r.Context(), "rate_limit_middleware")
+		defer tracer.SpanFinish(ctx)
+
+		res, errLimit := limiter.Allow(ctx, route+":"+rateLimitSubject(r, rule.Key), rule.Limit, window)
+		if errLimit != nil {
+			// the route stays available when the calls cannot be counted
+			logger.Error(logger.MessageFormat("[rate-limit] %v", errLimit))
+			return nil
+		}
+
+		w.Header().Set(consts.HeaderRateLimitLimitKey, strconv.Itoa(res.Limit))
+		w.Header().Set(consts.HeaderRateLimitRemainingKey, strconv.Itoa(res.Remaining))
+		w.Header().Set(consts.HeaderRateLimitResetKey, ceilSecond(res.Reset))
+
+		if !res.Allowed {
+			w.Header().Set(consts.HeaderRetryAfterKey, ceilSecond(res.RetryAfter))
+
+			err := errorEvent.WithCode(consts.CodeReachMaxLimit).WrapError(consts.Error(consts.RateLimitExceeded))
+			tracer.SpanError(ctx, err)
+			return NewError(*appctx.NewResponse().
+				WithCode(consts.CodeReachMaxLimit).
+				WithMsgKey(msg.CodeKey(consts.CodeReachMaxLimit)).
+				WithError(consts.RateLimitExceeded).
+				WithStatus(consts.StatusFailed).
+				WithEntity("rateLimit").
+				WithState("rateLimitExceeded"), WithError(err))
+		}
+
+		return nil
+	}
+}
+
+// rateLimitSubject whom the call is counted for, a call without the user or api key the rule
+// counts by is counted by ip
+func rateLimitSubject(r *http.Request, key string) string {
+	switch key {
+	case consts.RateLimitKeyUser:
+		if user, ok := r.Context().Value(consts.CtxUserInfo).(entity.User); ok {
+			return consts.RateLimitKeyUser + ":" + user.ID.String()
+		}
+	case consts.RateLimitKeyAPIKey:
+		if key, ok := r.Context().Value(consts.CtxPartnerAPIKey).(entity.PartnerAPIKey); ok {
+			return consts.RateLimitKeyAPIKey + ":" + key.ID.String()
+		}
+	}
+
+	return consts.RateLimitKeyIP + ":" + util.ClientIP(r)
+}
+
+func ceilSecond(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/response"
+	"sharefood/pkg/tracer"
+)
+
+// RequirePermission rejects a token whose roles do not carry every given permission,
+// it must be registered after the bearer token validation
+func RequirePermission(permissions ...string) MiddlewareFunc {
+	return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
+		errorEvent := consts.ErrorEvent("require_permission_middleware")
+		response := response.NewResponse("require_permission_middleware", r)
+		ctx := tracer.SpanStart(r.Context(), "require_permission_middleware")
+		defer tracer.SpanFinish(ctx)
+
+		claims, ok := r.Context().Value(consts.CtxTokenClaims).(entity.TokenClaims)
+		if !ok {
+			err := errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(consts.Error(consts.StatusUnauthorized))
+			tracer.SpanError(ctx, err)
+			return NewError(*response.Failed(ctx, nil, err))
+		}
+
+		for _, permission := range permissions {
+			if !claims.HasPermission(permission) {
+				message := consts.StatusForbidden
+				if claims.WithholdsPermission(permission) {
+					message = consts.TwoFactorRequired
+				}
+
+				err := errorEvent.WithCode(consts.CodeForbidden).WrapError(consts.Error(message))
+				tracer.SpanError(ctx, err)
+				return NewError(*response.Failed(ctx, nil, err))
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/response"
+	"sharefood/pkg/tracer"
+)
+
+// RequireVerified rejects an account without a verified email and phone number,
+// it must be registered after the bearer token validation
+func RequireVerified(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
+	errorEvent := consts.ErrorEvent("require_verified_middleware")
+	response := response.NewResponse("require_verified_middleware", r)
+	ctx := tracer.SpanStart(r.Context(), "require_verified_middleware")
+	defer tracer.SpanFinish(ctx)
+
+	user, ok := r.Context().Value(consts.CtxUserInfo).(entity.User)
+	if !ok {
+		err := errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(consts.Error(consts.StatusUnauthorized))
+		tracer.SpanError(ctx, err)
+		return NewError(*response.Failed(ctx, nil, err))
+	}
+
+	if !user.IsVerified() {
+		err := errorEvent.WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.AccountNotVerified))
+		tracer.SpanError(ctx, err)
+		return NewError(*response.Failed(ctx, nil, err))
+	}
+
+	return nil
+}
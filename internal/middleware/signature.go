@@ -3,58 +3,167 @@ package middleware
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
+	"context"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"sharefood/internal/appctx"
 	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
 	"sharefood/pkg/hash"
 	"sharefood/pkg/logger"
-)
-
-// ValidateSignature header signature
-func ValidateSignature(r *http.Request, conf *appctx.Config) int {
-
-	var err error
-	key := conf.App.ApiKey
-
-	signature := r.Header.Get("Signature")
-
-	save := r.Body
-
-	save, r.Body, err = drainBody(r.Body)
-
-	nameField := "request"
-
-	if err != nil {
-		return consts.CodeBadRequest
-	}
-
-	b, err := ioutil.ReadAll(r.Body)
-
-	if err != nil {
-		logger.Warn(fmt.Sprintf("[middleware] cannot read request body , error %s", err.Error()), logger.Any(nameField, string(b)))
-		return consts.CodeBadRequest
-	}
+	"sharefood/pkg/tracer"
 
-	buff := &bytes.Buffer{}
-	err = json.Compact(buff, b)
-	if err != nil {
-		logger.Error(fmt.Sprintf("[middleware] cannot compat bytes , error %s", err.Error()), logger.Any(nameField, string(b)))
-		return consts.CodeBadRequest
-	}
+	"github.com/google/uuid"
+)
 
-	if !hash.HmacComparator(buff.String(), signature, hash.SHA256(key)) {
-		logger.Warn(fmt.Sprintf("[middleware] invalid signature %s", signature), logger.Any(nameField, string(b)))
-		return consts.CodeBadRequest
+// NewValidateSignature authenticates a partner request signed with the secret of its api key.
+// The request carries the key id, a unix timestamp, a nonce and the hex hmac sha256 of
+//
+//	METHOD \n REQUEST URI \n TIMESTAMP \n NONCE \n HEX SHA256 OF THE BODY
+//
+// A timestamp off by more than the allowed skew or a nonce seen before is rejected so a captured
+// request cannot be replayed. The key must carry every given scope, the request then acts as the
+// organization member the key was issued for, rejected as a bearer token would be when the member is
+// missing or suspended or its sessions were revoked since the key secret was issued
+func NewValidateSignature(partnerAPIKeyRepository repositories.PartnerAPIKey, userRepository repositories.User, scopes ...string) MiddlewareFunc {
+	return func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) error {
+		errorEvent := consts.ErrorEvent("validate_signature_middleware")
+		response := response.NewResponse("validate_signature_middleware", r)
+		ctx := tracer.SpanStart(r.Context(), "validate_signature_middleware")
+		defer tracer.SpanFinish(ctx)
+
+		unauthorized := func(message string) error {
+			err := errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(consts.Error(message))
+			tracer.SpanError(ctx, err)
+			return NewError(*response.Failed(ctx, nil, err))
+		}
+
+		idKey, err := uuid.Parse(r.Header.Get(consts.HeaderAPIKey))
+		if err != nil {
+			return unauthorized(consts.PartnerKeyNotValid)
+		}
+
+		signature := r.Header.Get(consts.HeaderSignature)
+		if signature == "" {
+			return unauthorized(consts.SignatureRequiredMessage)
+		}
+
+		nonce := r.Header.Get(consts.HeaderNonce)
+		if nonce == "" {
+			return unauthorized(consts.NonceRequiredMessage)
+		}
+
+		rawTimestamp := r.Header.Get(consts.HeaderTimestamp)
+		if rawTimestamp == "" {
+			return unauthorized(consts.TimestampRequiredMessage)
+		}
+
+		maxSkew := time.Duration(conf.Auth.PartnerSignatureMaxSkewSecond) * time.Second
+		if maxSkew <= 0 {
+			maxSkew = consts.PartnerSignatureMaxSkewSecondDefault * time.Second
+		}
+
+		now := time.Now()
+		timestamp, err := strconv.ParseInt(rawTimestamp, 10, 64)
+		skew := now.Sub(time.Unix(timestamp, 0))
+		if err != nil || skew > maxSkew || skew < -maxSkew {
+			return unauthorized(consts.TimestampNotValid)
+		}
+
+		key, err := partnerAPIKeyRepository.GetByID(ctx, idKey)
+		if err != nil {
+			logger.Error(logger.MessageFormat("[validate-signature] %v", err))
+			return NewError(*response.Failed(ctx, nil, errorEvent.WrapError(err)))
+		}
+
+		if key.ID == uuid.Nil || key.IsRevoked() {
+			return unauthorized(consts.PartnerKeyNotValid)
+		}
+
+		var body []byte
+		save := r.Body
+		save, r.Body, err = drainBody(r.Body)
+		if err == nil {
+			body, err = ioutil.ReadAll(r.Body)
+		}
+		if err != nil {
+			logger.Warn(logger.MessageFormat("[validate-signature] cannot read request body, error %v", err))
+			err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+			return NewError(*response.Failed(ctx, nil, err))
+		}
+		r.Body = save
+
+		message := strings.Join([]string{
+			r.Method,
+			r.URL.RequestURI(),
+			rawTimestamp,
+			nonce,
+			hash.SHA256(string(body)),
+		}, "\n")
+
+		valid := false
+		for _, secret := range key.Secrets(now) {
+			if hash.HmacComparator(message, strings.ToLower(signature), secret) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			logger.Warn(logger.MessageFormat("[validate-signature] invalid signature of key %s", key.ID))
+			return unauthorized(consts.SignatureNotValid)
+		}
+
+		// the nonce only needs to be remembered for as long as the timestamp is accepted
+		fresh, err := partnerAPIKeyRepository.UseNonce(ctx, key.ID, nonce, 2*maxSkew)
+		if err != nil {
+			logger.Error(logger.MessageFormat("[validate-signature] %v", err))
+			return NewError(*response.Failed(ctx, nil, errorEvent.WrapError(err)))
+		}
+		if !fresh {
+			return unauthorized(consts.NonceAlreadyUsed)
+		}
+
+		for _, scope := range scopes {
+			if !key.HasScope(scope) {
+				err := errorEvent.WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.StatusForbidden))
+				tracer.SpanError(ctx, err)
+				return NewError(*response.Failed(ctx, nil, err))
+			}
+		}
+
+		user, errUser := userRepository.GetByID(ctx, key.IDUser)
+		if errUser != nil {
+			logger.Warn(logger.MessageFormat("[validate-signature] owner of key %s: %v", key.ID, errUser))
+			return unauthorized(consts.PartnerKeyNotValid)
+		}
+
+		if user.SessionsRevokedAt != nil && !key.IssuedAt().After(*user.SessionsRevokedAt) {
+			return unauthorized(consts.SessionRevoked)
+		}
+
+		if user.IsSuspended() {
+			err := errorEvent.WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.AccountSuspended))
+			tracer.SpanError(ctx, err)
+			return NewError(*response.Failed(ctx, nil, err))
+		}
+
+		if err := partnerAPIKeyRepository.Touch(ctx, key.ID, now); err != nil {
+			logger.Warn(logger.MessageFormat("[validate-signature] %v", err))
+		}
+
+		r.Header.Set("idUser", key.IDUser.String())
+		reqCtx := context.WithValue(r.Context(), consts.CtxPartnerAPIKey, key)
+		reqCtx = context.WithValue(reqCtx, consts.CtxUserInfo, user)
+		*r = *r.WithContext(reqCtx)
+
+		return nil
 	}
-
-	r.Body = save
-
-	return consts.CodeSuccess
 }
 
 func drainBody(b io.ReadCloser) (r1, r2 io.ReadCloser, err error) {
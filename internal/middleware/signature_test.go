@@ -0,0 +1,135 @@
+// Package middleware
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/hash"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePartnerAPIKeyRepository serves key, every nonce is fresh
+type fakePartnerAPIKeyRepository struct {
+	repositories.PartnerAPIKey
+	key entity.PartnerAPIKey
+}
+
+func (r *fakePartnerAPIKeyRepository) GetByID(context.Context, uuid.UUID) (entity.PartnerAPIKey, error) {
+	return r.key, nil
+}
+
+func (r *fakePartnerAPIKeyRepository) UseNonce(context.Context, uuid.UUID, string, time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (r *fakePartnerAPIKeyRepository) Touch(context.Context, uuid.UUID, time.Time) error {
+	return nil
+}
+
+// fakeUserRepository serves the users not deleted
+type fakeUserRepository struct {
+	repositories.User
+	users map[uuid.UUID]entity.User
+}
+
+func (r *fakeUserRepository) GetByID(_ context.Context, id uuid.UUID) (entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return entity.User{}, errors.New("scanning user sql: no rows in result set")
+	}
+
+	return user, nil
+}
+
+func TestValidateSignature(t *testing.T) {
+	issuedAt := time.Now().Add(-time.Hour)
+	key := entity.PartnerAPIKey{
+		ID:        uuid.New(),
+		IDUser:    uuid.New(),
+		Scopes:    []string{consts.PartnerScopeFoodWrite},
+		Secret:    "rahasia",
+		CreatedAt: issuedAt,
+	}
+
+	serve := func(owner *entity.User) (*http.Request, error) {
+		users := &fakeUserRepository{users: map[uuid.UUID]entity.User{}}
+		if owner != nil {
+			users.users[owner.ID] = *owner
+		}
+		mf := NewValidateSignature(&fakePartnerAPIKeyRepository{key: key}, users, consts.PartnerScopeFoodWrite)
+
+		body := `{"name":"nasi goreng"}`
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		message := strings.Join([]string{http.MethodPost, "/v1/partner/foods", timestamp, "n1", hash.SHA256(body)}, "\n")
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/partner/foods", strings.NewReader(body))
+		req.Header.Set(consts.HeaderAPIKey, key.ID.String())
+		req.Header.Set(consts.HeaderTimestamp, timestamp)
+		req.Header.Set(consts.HeaderNonce, "n1")
+		req.Header.Set(consts.HeaderSignature, hash.Hmac256(message, key.Secret))
+
+		return req, mf(httptest.NewRecorder(), req, &appctx.Config{})
+	}
+
+	code := func(err error) int {
+		if e, ok := err.(Error); ok {
+			return e.Response.Code
+		}
+		return 0
+	}
+
+	t.Run("test owner active", func(t *testing.T) {
+		owner := entity.User{ID: key.IDUser}
+
+		req, err := serve(&owner)
+
+		assert.NoError(t, err)
+		assert.Equal(t, key.IDUser.String(), req.Header.Get("idUser"))
+		assert.Equal(t, owner, req.Context().Value(consts.CtxUserInfo))
+	})
+
+	t.Run("test sessions revoked before the key was issued", func(t *testing.T) {
+		revokedAt := issuedAt.Add(-time.Minute)
+
+		_, err := serve(&entity.User{ID: key.IDUser, SessionsRevokedAt: &revokedAt})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("test owner deleted", func(t *testing.T) {
+		_, err := serve(nil)
+
+		assert.Equal(t, consts.CodeAuthenticationFailure, code(err))
+	})
+
+	t.Run("test owner suspended", func(t *testing.T) {
+		suspendedAt := time.Now()
+
+		_, err := serve(&entity.User{ID: key.IDUser, SuspendedAt: &suspendedAt})
+
+		assert.Equal(t, consts.CodeForbidden, code(err))
+	})
+
+	t.Run("test sessions revoked since the key was issued", func(t *testing.T) {
+		for _, revokedAt := range []time.Time{issuedAt, issuedAt.Add(time.Minute)} {
+			revokedAt := revokedAt
+
+			_, err := serve(&entity.User{ID: key.IDUser, SessionsRevokedAt: &revokedAt})
+
+			assert.Equal(t, consts.CodeAuthenticationFailure, code(err))
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+	"sharefood/internal/consts"
+)
+
+// NewStripUserID drops the user id header the client sent, only the authentication middlewares
+// tell who the user is. It runs for every request, whatever else is enabled
+func NewStripUserID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Del(consts.HeaderUserIDKey)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
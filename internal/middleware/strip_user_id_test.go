@@ -0,0 +1,26 @@
+// Package middleware
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sharefood/internal/consts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripUserID(t *testing.T) {
+	var seen []string
+	h := NewStripUserID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.Header.Values(consts.HeaderUserIDKey)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Add(consts.HeaderUserIDKey, "7d1f5b1e-4f8a-4c1b-9b1a-0d5e2c3f4a5b")
+	req.Header.Add(consts.HeaderUserIDKey, "3a2b1c0d-0000-4000-8000-000000000000")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Empty(t, seen)
+}
@@ -0,0 +1,82 @@
+// Package presentations
+package presentations
+
+import (
+	"time"
+
+	"sharefood/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// UserProfile is the account as its owner sees it
+	UserProfile struct {
+		ID                 uuid.UUID  `json:"id_user"`
+		Name               string     `json:"name"`
+		Email              string     `json:"email"`
+		PhoneNumber        string     `json:"phone_number"`
+		ImageUrl           string     `json:"image_url"`
+		Bio                string     `json:"bio"`
+		PickupAddress      string     `json:"pickup_address"`
+		PickupLatitude     string     `json:"pickup_latitude"`
+		PickupLongitude    string     `json:"pickup_longitude"`
+		EmailVerifiedAt    *time.Time `json:"email_verified_at"`
+		PhoneVerifiedAt    *time.Time `json:"phone_verified_at"`
+		TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at"`
+	}
+
+	// AdminUser is the account as the user management sees it
+	AdminUser struct {
+		UserProfile
+		Roles       []string   `json:"roles"`
+		SuspendedAt *time.Time `json:"suspended_at"`
+	}
+
+	// UserExport is everything the service stores about an account, handed to its owner
+	UserExport struct {
+		Profile       UserProfile           `json:"profile"`
+		Roles         []string              `json:"roles"`
+		Organizations []entity.Organization `json:"organizations"`
+		Foods         []entity.Food         `json:"foods"`
+		Requests      []entity.Request      `json:"requests"`
+		ExportedAt    time.Time             `json:"exported_at"`
+	}
+)
+
+// NewUserProfile copies the fields a user may see of their own account
+func NewUserProfile(u entity.User) UserProfile {
+	return UserProfile{
+		ID:                 u.ID,
+		Name:               u.Name,
+		Email:              u.Email,
+		PhoneNumber:        u.PhoneNumber,
+		ImageUrl:           u.ImageUrl,
+		Bio:                u.Bio,
+		PickupAddress:      u.PickupAddress,
+		PickupLatitude:     u.PickupLatitude,
+		PickupLongitude:    u.PickupLongitude,
+		EmailVerifiedAt:    u.EmailVerifiedAt,
+		PhoneVerifiedAt:    u.PhoneVerifiedAt,
+		TwoFactorEnabledAt: u.TwoFactorEnabledAt,
+	}
+}
+
+// NewAdminUser copies the fields an admin may see of an account
+func NewAdminUser(u entity.User) AdminUser {
+	return AdminUser{
+		UserProfile: NewUserProfile(u),
+		Roles:       u.Roles,
+		SuspendedAt: u.SuspendedAt,
+	}
+}
+
+// NewAdminUsers copies a list of users for the user management
+func NewAdminUsers(users []entity.User) []AdminUser {
+	result := make([]AdminUser, 0, len(users))
+	for _, u := range users {
+		result = append(result, NewAdminUser(u))
+	}
+
+	return result
+}
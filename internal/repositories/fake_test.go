@@ -0,0 +1,207 @@
+// Package repositories
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"sharefood/pkg/postgres"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// fakeConn affects the number of rows given to the statements executed, the queries and
+// transactions go to the fake database
+type fakeConn struct {
+	postgres.Adapter
+	db       *sqlx.DB
+	affected int64
+	query    string
+	args     []interface{}
+}
+
+func (c *fakeConn) Exec(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.query, c.args = query, args
+	return driver.RowsAffected(c.affected), nil
+}
+
+func (c *fakeConn) QueryRow(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	return c.db.QueryRowxContext(ctx, query, args...)
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	return c.db.BeginTxx(ctx, opts)
+}
+
+// fakeExec is a statement executed on the fake database
+type fakeExec struct {
+	query string
+	args  []driver.Value
+}
+
+// fakeDB answers every query with its row, the columns selected are read from the row by name so
+// a column left out of the query is left out of the result. The statements executed are recorded
+type fakeDB struct {
+	mu        sync.Mutex
+	row       map[string]driver.Value
+	execs     []fakeExec
+	committed bool
+}
+
+func (d *fakeDB) executed() []fakeExec {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]fakeExec(nil), d.execs...)
+}
+
+var (
+	fakeDBs    sync.Map
+	fakeDBOnce sync.Once
+)
+
+// newFakeConn gives a connection to a fake database answering the row, nil row for no rows
+func newFakeConn(t *testing.T, row map[string]driver.Value) (*fakeConn, *fakeDB) {
+	fakeDBOnce.Do(func() { sql.Register("fake", fakeDriver{}) })
+
+	fdb := &fakeDB{row: row}
+	fakeDBs.Store(t.Name(), fdb)
+
+	db, err := sql.Open("fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		fakeDBs.Delete(t.Name())
+	})
+
+	return &fakeConn{db: sqlx.NewDb(db, "postgres")}, fdb
+}
+
+// selectColumns names the columns selected by the query, the alias when given
+func selectColumns(query string) []string {
+	start := strings.Index(strings.ToUpper(query), "SELECT") + len("SELECT")
+	upper := strings.ToUpper(query)
+
+	var columns []string
+	depth, from := 0, start
+	for i := start; i < len(query); i++ {
+		switch query[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				columns = append(columns, query[from:i])
+				from = i + 1
+			}
+		default:
+			if depth == 0 && strings.HasPrefix(upper[i:], "FROM") && strings.ContainsAny(query[i-1:i], " \t\n") {
+				columns = append(columns, query[from:i])
+				from = -1
+			}
+		}
+		if from < 0 {
+			break
+		}
+	}
+
+	for i, column := range columns {
+		fields := strings.Fields(column)
+		columns[i] = fields[len(fields)-1]
+	}
+
+	return columns
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fdb, ok := fakeDBs.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("fake database %s not found", name)
+	}
+
+	return &fakeDriverConn{db: fdb.(*fakeDB)}, nil
+}
+
+type fakeDriverConn struct {
+	db *fakeDB
+}
+
+func (c *fakeDriverConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeDriverConn) Close() error { return nil }
+
+func (c *fakeDriverConn) Begin() (driver.Tx, error) { return fakeTx{db: c.db}, nil }
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx fakeTx) Commit() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	tx.db.committed = true
+	return nil
+}
+
+func (tx fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error { return nil }
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.execs = append(s.db.execs, fakeExec{query: s.query, args: args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(_ []driver.Value) (driver.Rows, error) {
+	columns := selectColumns(s.query)
+	rows := &fakeRows{columns: columns}
+	if s.db.row != nil {
+		values := make([]driver.Value, len(columns))
+		for i, column := range columns {
+			values[i] = s.db.row[column]
+		}
+		rows.values = [][]driver.Value{values}
+	}
+
+	return rows, nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -19,7 +19,9 @@ type Food interface {
 	DeleteByID(context.Context, uuid.UUID) error
 	Create(context.Context, *entity.Food) error
 	Update(context.Context, *entity.Food) error
+	UpdateIfUnmodified(context.Context, *entity.Food, time.Time) error
 	ListMy(context.Context, uuid.UUID) ([]entity.Food, error)
+	ListByUser(context.Context, uuid.UUID) ([]entity.Food, error)
 }
 
 type foodImplementation struct {
@@ -36,6 +38,7 @@ func (r foodImplementation) List(ctx context.Context) (foods []entity.Food, err
 		SELECT 
 			id_food, 
 			id_user, 
+			id_organization,
 			name, 
 			description, 
 			category, 
@@ -58,6 +61,7 @@ func (r foodImplementation) List(ctx context.Context) (foods []entity.Food, err
 		err := rows.Scan(
 			&food.ID,
 			&food.IDUser,
+			&food.IDOrganization,
 			&food.Name,
 			&food.Description,
 			&food.Category,
@@ -85,6 +89,7 @@ func (r foodImplementation) GetDetailByID(ctx context.Context, id uuid.UUID) (fo
 		SELECT 
 			id_food, 
 			id_user, 
+			id_organization,
 			name, 
 			description, 
 			category, 
@@ -92,7 +97,9 @@ func (r foodImplementation) GetDetailByID(ctx context.Context, id uuid.UUID) (fo
 			image_url,
 			expired_at,
 			latitude,
-			longitude
+			longitude,
+			created_at,
+			updated_at
 		FROM foods
 		WHERE id_food = $1 AND deleted_at IS NULL;
 	`
@@ -101,6 +108,7 @@ func (r foodImplementation) GetDetailByID(ctx context.Context, id uuid.UUID) (fo
 	err = row.Scan(
 		&food.ID,
 		&food.IDUser,
+		&food.IDOrganization,
 		&food.Name,
 		&food.Description,
 		&food.Category,
@@ -110,6 +118,8 @@ func (r foodImplementation) GetDetailByID(ctx context.Context, id uuid.UUID) (fo
 		&food.ExpiredAt,
 		&food.Latitude,
 		&food.Longitude,
+		&food.CreatedAt,
+		&food.UpdatedAt,
 	)
 	if err != nil {
 		err = fmt.Errorf("scanning food %w", err)
@@ -125,6 +135,7 @@ func (r foodImplementation) Create(ctx context.Context, food *entity.Food) (err
 	INSERT INTO foods(
 		id_food,
 		id_user,
+		id_organization,
 		name,
 		description,
 		category,
@@ -135,7 +146,7 @@ func (r foodImplementation) Create(ctx context.Context, food *entity.Food) (err
 		latitude,
 		longitude
 	)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 
 	_, err = r.conn.Exec(
@@ -143,6 +154,7 @@ func (r foodImplementation) Create(ctx context.Context, food *entity.Food) (err
 		query,
 		food.ID,
 		food.IDUser,
+		food.IDOrganization,
 		food.Name,
 		food.Description,
 		food.Category,
@@ -168,6 +180,41 @@ func (r foodImplementation) Update(ctx context.Context, food *entity.Food) (err
 	ctx = tracer.SpanStart(ctx, "update_my_foods")
 	defer tracer.SpanFinish(ctx)
 
+	_, err = r.update(ctx, food, "")
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateIfUnmodified updates my food only when it is still at the version last updated at
+// updatedAt, so a concurrent update is not overwritten
+func (r foodImplementation) UpdateIfUnmodified(ctx context.Context, food *entity.Food, updatedAt time.Time) (err error) {
+	errorEvent := consts.ErrorEvent("update_my_foods")
+	ctx = tracer.SpanStart(ctx, "update_my_foods")
+	defer tracer.SpanFinish(ctx)
+
+	affected, err := r.update(ctx, food, "AND updated_at = $11", updatedAt)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	if affected == 0 {
+		err := errorEvent.WithCode(consts.CodePreconditionFailed).WrapError(consts.Error(consts.FoodModifiedMessage))
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// update the fields of food, the rows matched further by condition on args
+func (r foodImplementation) update(ctx context.Context, food *entity.Food, condition string, args ...interface{}) (int64, error) {
 	query := `
 		UPDATE foods SET 
 			name = $1, 
@@ -179,22 +226,21 @@ func (r foodImplementation) Update(ctx context.Context, food *entity.Food) (err
 			latitude = $7,
 			longitude= $8,
 			updated_at = $9
-		WHERE id_food=$10;
+		WHERE id_food=$10 ` + condition + `;
 
 		`
 	updatedTime := time.Now().Local()
 
-	_, err = r.conn.Exec(ctx, query, food.Name, food.Description, food.Category, food.Quantity, food.ImageUrl, food.ExpiredAt, food.Latitude, food.Longitude, updatedTime, food.ID)
+	args = append([]interface{}{food.Name, food.Description, food.Category, food.Quantity, food.ImageUrl, food.ExpiredAt, food.Latitude, food.Longitude, updatedTime, food.ID}, args...)
+	result, err := r.conn.Exec(ctx, query, args...)
 	if err != nil {
-		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
-		tracer.SpanError(ctx, err)
-		return err
+		return 0, err
 	}
 
-	return nil
+	return result.RowsAffected()
 }
 
-// List my food
+// List my food, along with the food of the organizations the user is a member of
 func (r foodImplementation) ListMy(ctx context.Context, id_user uuid.UUID) (foods []entity.Food, err error) {
 	errorEvent := consts.ErrorEvent("list_my_foods")
 	ctx = tracer.SpanStart(ctx, "list_my_foods")
@@ -204,6 +250,7 @@ func (r foodImplementation) ListMy(ctx context.Context, id_user uuid.UUID) (food
 		SELECT 
 			id_food, 
 			id_user, 
+			id_organization,
 			name, 
 			description, 
 			category, 
@@ -213,7 +260,10 @@ func (r foodImplementation) ListMy(ctx context.Context, id_user uuid.UUID) (food
 			latitude,
 			longitude
 		FROM foods
-		WHERE id_user=$1 AND deleted_at IS NULL;
+		WHERE deleted_at IS NULL AND (
+			(id_user=$1 AND id_organization IS NULL)
+			OR id_organization IN (SELECT id_organization FROM organization_members WHERE id_user=$1)
+		);
 
 		`
 	rows, errQueryRows := r.conn.QueryRows(ctx, query, id_user)
@@ -230,6 +280,7 @@ func (r foodImplementation) ListMy(ctx context.Context, id_user uuid.UUID) (food
 		errStructScan := rows.Scan(
 			&food.ID,
 			&food.IDUser,
+			&food.IDOrganization,
 			&food.Name,
 			&food.Description,
 			&food.Category,
@@ -253,6 +304,69 @@ func (r foodImplementation) ListMy(ctx context.Context, id_user uuid.UUID) (food
 	return foods, nil
 }
 
+// List every food the user has shared, taken down ones included
+func (r foodImplementation) ListByUser(ctx context.Context, idUser uuid.UUID) (foods []entity.Food, err error) {
+	errorEvent := consts.ErrorEvent("list_user_foods")
+	ctx = tracer.SpanStart(ctx, "list_user_foods")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		SELECT
+			id_food,
+			id_user,
+			id_organization,
+			name,
+			description,
+			category,
+			quantity,
+			image_url,
+			expired_at,
+			latitude,
+			longitude,
+			created_at,
+			updated_at
+		FROM foods
+		WHERE id_user = $1
+		ORDER BY created_at DESC;
+	`
+
+	rows, err := r.conn.QueryRows(ctx, query, idUser)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var food entity.Food
+		err := rows.Scan(
+			&food.ID,
+			&food.IDUser,
+			&food.IDOrganization,
+			&food.Name,
+			&food.Description,
+			&food.Category,
+			&food.Quantity,
+			&food.ImageUrl,
+			&food.ExpiredAt,
+			&food.Latitude,
+			&food.Longitude,
+			&food.CreatedAt,
+			&food.UpdatedAt,
+		)
+		if err != nil {
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return nil, err
+		}
+
+		foods = append(foods, food)
+	}
+
+	return foods, nil
+}
+
 // Delete food by idFood
 func (r foodImplementation) DeleteByID(ctx context.Context, idFood uuid.UUID) (err error) {
 	errorEvent := consts.ErrorEvent("delete_my_food")
@@ -0,0 +1,41 @@
+// Package repositories
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFood_UpdateIfUnmodified(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	food := &entity.Food{ID: uuid.New(), Name: "nasi goreng"}
+
+	t.Run("test unmodified", func(t *testing.T) {
+		conn := &fakeConn{affected: 1}
+
+		err := NewFoodRepository(conn).UpdateIfUnmodified(context.Background(), food, updatedAt)
+
+		assert.NoError(t, err)
+		assert.Contains(t, conn.query, "updated_at = $11")
+		assert.Equal(t, updatedAt, conn.args[10])
+	})
+
+	t.Run("test modified since it was read", func(t *testing.T) {
+		conn := &fakeConn{affected: 0}
+
+		err := NewFoodRepository(conn).UpdateIfUnmodified(context.Background(), food, updatedAt)
+
+		errs, ok := errors.Cause(err).(consts.Errors)
+		assert.True(t, ok)
+		assert.Equal(t, consts.CodePreconditionFailed, errs[len(errs)-1].StatusCode)
+		assert.Equal(t, consts.FoodModifiedMessage, err.Error())
+	})
+}
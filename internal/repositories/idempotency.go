@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Idempotency stores in redis the responses replayed to the retries of a request, keyed by the
+// idempotency key and whom sent it
+type Idempotency interface {
+	Get(ctx context.Context, key string) (entity.IdempotentResponse, bool, error)
+	Save(ctx context.Context, key string, resp entity.IdempotentResponse, ttl time.Duration) error
+	Lock(ctx context.Context, key string, ttl time.Duration) (string, bool, error)
+	Unlock(ctx context.Context, key string, token string) error
+}
+
+type idempotencyImplementation struct {
+	cache cache.Cacher
+}
+
+func NewIdempotencyRepository(cache cache.Cacher) Idempotency {
+	return &idempotencyImplementation{cache}
+}
+
+// Get response stored for key, false when there is none
+func (r idempotencyImplementation) Get(ctx context.Context, key string) (resp entity.IdempotentResponse, found bool, err error) {
+	errorEvent := consts.ErrorEvent("get_idempotent_response")
+	ctx = tracer.SpanStart(ctx, "get_idempotent_response")
+	defer tracer.SpanFinish(ctx)
+
+	b, err := r.cache.Get(ctx, consts.IdempotencyKeyPrefix+key)
+	if err == nil && len(b) == 0 {
+		return resp, false, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(b, &resp)
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return resp, false, err
+	}
+
+	return resp, true, nil
+}
+
+// Save the response of key for ttl
+func (r idempotencyImplementation) Save(ctx context.Context, key string, resp entity.IdempotentResponse, ttl time.Duration) error {
+	errorEvent := consts.ErrorEvent("save_idempotent_response")
+	ctx = tracer.SpanStart(ctx, "save_idempotent_response")
+	defer tracer.SpanFinish(ctx)
+
+	b, err := json.Marshal(resp)
+	if err == nil {
+		err = r.cache.Set(ctx, consts.IdempotencyKeyPrefix+key, b, ttl)
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Lock marks the request of key in flight for at most ttl, false when it already is. The token
+// returned releases the lock
+func (r idempotencyImplementation) Lock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	errorEvent := consts.ErrorEvent("lock_idempotency_key")
+	ctx = tracer.SpanStart(ctx, "lock_idempotency_key")
+	defer tracer.SpanFinish(ctx)
+
+	token := uuid.New().String()
+	ok, err := r.cache.SetNX(ctx, consts.IdempotencyLockKeyPrefix+key, token, ttl)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return "", false, err
+	}
+
+	return token, ok, nil
+}
+
+// Unlock ends the request of key in flight. A request outliving its lock leaves alone the lock
+// taken since by a retry, only the holder of the token releases it
+func (r idempotencyImplementation) Unlock(ctx context.Context, key string, token string) error {
+	errorEvent := consts.ErrorEvent("unlock_idempotency_key")
+	ctx = tracer.SpanStart(ctx, "unlock_idempotency_key")
+	defer tracer.SpanFinish(ctx)
+
+	if _, err := r.cache.DeleteIfEqual(ctx, consts.IdempotencyLockKeyPrefix+key, token); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/postgres"
+	"sharefood/pkg/tracer"
+	"time"
+)
+
+// LoginAttempt counts failed logins in redis, lockouts are also kept in the database for review
+type LoginAttempt interface {
+	Fail(ctx context.Context, key string, window time.Duration) (int64, error)
+	Reset(ctx context.Context, key string) error
+	Lock(ctx context.Context, key string, duration time.Duration) error
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	RecordLockout(ctx context.Context, lockout entity.LoginLockout) error
+}
+
+type loginAttemptImplementation struct {
+	cache cache.Cacher
+	conn  postgres.Adapter
+}
+
+func NewLoginAttemptRepository(cache cache.Cacher, conn postgres.Adapter) LoginAttempt {
+	return &loginAttemptImplementation{cache, conn}
+}
+
+// Fail counts one more failed attempt for key within the window, returning the count so far
+func (r loginAttemptImplementation) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	errorEvent := consts.ErrorEvent("fail_login_attempt")
+	ctx = tracer.SpanStart(ctx, "fail_login_attempt")
+	defer tracer.SpanFinish(ctx)
+
+	failures, err := r.cache.Increment(ctx, consts.LoginFailureKeyPrefix+key, window)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+// Reset forgets the failed attempts of key
+func (r loginAttemptImplementation) Reset(ctx context.Context, key string) error {
+	errorEvent := consts.ErrorEvent("reset_login_attempt")
+	ctx = tracer.SpanStart(ctx, "reset_login_attempt")
+	defer tracer.SpanFinish(ctx)
+
+	if err := r.cache.Delete(ctx, consts.LoginFailureKeyPrefix+key); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Lock key for the duration, the failed attempts start over once the lock ends
+func (r loginAttemptImplementation) Lock(ctx context.Context, key string, duration time.Duration) error {
+	errorEvent := consts.ErrorEvent("lock_login")
+	ctx = tracer.SpanStart(ctx, "lock_login")
+	defer tracer.SpanFinish(ctx)
+
+	err := r.cache.Set(ctx, consts.LoginLockKeyPrefix+key, 1, duration)
+	if err == nil {
+		err = r.cache.Delete(ctx, consts.LoginFailureKeyPrefix+key)
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// LockedFor remaining lock time of key, zero when not locked
+func (r loginAttemptImplementation) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	errorEvent := consts.ErrorEvent("get_login_lock")
+	ctx = tracer.SpanStart(ctx, "get_login_lock")
+	defer tracer.SpanFinish(ctx)
+
+	ttl, err := r.cache.TTL(ctx, consts.LoginLockKeyPrefix+key)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return 0, err
+	}
+
+	return ttl, nil
+}
+
+// RecordLockout keeps the lockout event
+func (r loginAttemptImplementation) RecordLockout(ctx context.Context, lockout entity.LoginLockout) (err error) {
+	errorEvent := consts.ErrorEvent("record_login_lockout")
+	ctx = tracer.SpanStart(ctx, "record_login_lockout")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+	INSERT INTO login_lockouts(id_login_lockout, id_user, email, ip_address, reason, attempts, locked_until)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.conn.Exec(ctx, query, lockout.ID, lockout.IDUser, lockout.Email, lockout.IPAddress, lockout.Reason, lockout.Attempts, lockout.LockedUntil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/tracer"
+)
+
+// Maintenance stores in redis the maintenance mode toggled at runtime, shared by every instance
+type Maintenance interface {
+	Get(ctx context.Context) (entity.Maintenance, error)
+	Save(ctx context.Context, state entity.Maintenance) error
+}
+
+type maintenanceImplementation struct {
+	cache      cache.Cacher
+	configured entity.Maintenance
+}
+
+// NewMaintenanceRepository the configured state applies until the mode is toggled
+func NewMaintenanceRepository(cache cache.Cacher, configured entity.Maintenance) Maintenance {
+	return &maintenanceImplementation{cache, configured}
+}
+
+// Get current state, the configured one along the error when redis cannot be read
+func (r maintenanceImplementation) Get(ctx context.Context) (state entity.Maintenance, err error) {
+	errorEvent := consts.ErrorEvent("get_maintenance")
+	ctx = tracer.SpanStart(ctx, "get_maintenance")
+	defer tracer.SpanFinish(ctx)
+
+	b, err := r.cache.Get(ctx, consts.MaintenanceKey)
+	if err == nil && len(b) == 0 {
+		return r.configured, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(b, &state)
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return r.configured, err
+	}
+
+	return state, nil
+}
+
+// Save the state toggled, it replaces the configured one until the next toggle
+func (r maintenanceImplementation) Save(ctx context.Context, state entity.Maintenance) error {
+	errorEvent := consts.ErrorEvent("save_maintenance")
+	ctx = tracer.SpanStart(ctx, "save_maintenance")
+	defer tracer.SpanFinish(ctx)
+
+	b, err := json.Marshal(state)
+	if err == nil {
+		err = r.cache.Set(ctx, consts.MaintenanceKey, b, 0)
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/tracer"
+	"time"
+)
+
+// OIDCSession keeps the nonce and PKCE verifier of a login in redis between the redirect and the callback
+type OIDCSession interface {
+	Save(ctx context.Context, state string, session entity.OIDCSession, ttl time.Duration) error
+	Take(ctx context.Context, state string) (entity.OIDCSession, error)
+}
+
+type oidcSessionImplementation struct {
+	cache cache.Cacher
+}
+
+func NewOIDCSessionRepository(cache cache.Cacher) OIDCSession {
+	return &oidcSessionImplementation{cache}
+}
+
+// Save the session under its state
+func (r oidcSessionImplementation) Save(ctx context.Context, state string, session entity.OIDCSession, ttl time.Duration) error {
+	errorEvent := consts.ErrorEvent("save_oidc_session")
+	ctx = tracer.SpanStart(ctx, "save_oidc_session")
+	defer tracer.SpanFinish(ctx)
+
+	b, err := json.Marshal(session)
+	if err == nil {
+		err = r.cache.Set(ctx, consts.OIDCSessionKeyPrefix+state, b, ttl)
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Take the session of the state, a state can only be taken once
+func (r oidcSessionImplementation) Take(ctx context.Context, state string) (session entity.OIDCSession, err error) {
+	errorEvent := consts.ErrorEvent("take_oidc_session")
+	ctx = tracer.SpanStart(ctx, "take_oidc_session")
+	defer tracer.SpanFinish(ctx)
+
+	key := consts.OIDCSessionKeyPrefix + state
+	b, err := r.cache.Get(ctx, key)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return session, err
+	}
+
+	if len(b) == 0 {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.OIDCStateNotValid))
+		tracer.SpanError(ctx, err)
+		return session, err
+	}
+
+	if err := r.cache.Delete(ctx, key); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return session, err
+	}
+
+	if err := json.Unmarshal(b, &session); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return session, err
+	}
+
+	return session, nil
+}
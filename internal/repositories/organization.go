@@ -0,0 +1,333 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/postgres"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+)
+
+type Organization interface {
+	Create(ctx context.Context, organization *entity.Organization, idOwner uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (entity.Organization, error)
+	ListByUser(ctx context.Context, idUser uuid.UUID) ([]entity.Organization, error)
+	ListMembers(ctx context.Context, id uuid.UUID) ([]entity.OrganizationMember, error)
+	GetMemberRole(ctx context.Context, id uuid.UUID, idUser uuid.UUID) (string, error)
+	AddMember(ctx context.Context, id uuid.UUID, idUser uuid.UUID, role string) error
+	RemoveMember(ctx context.Context, id uuid.UUID, idUser uuid.UUID) error
+}
+
+type organizationImplementation struct {
+	conn postgres.Adapter
+}
+
+func NewOrganizationRepository(conn postgres.Adapter) Organization {
+	return &organizationImplementation{conn}
+}
+
+// Create organization with the creator as its first owner
+func (r organizationImplementation) Create(ctx context.Context, organization *entity.Organization, idOwner uuid.UUID) (err error) {
+	errorEvent := consts.ErrorEvent("create_organization")
+	ctx = tracer.SpanStart(ctx, "create_organization")
+	defer tracer.SpanFinish(ctx)
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	query := `
+	INSERT INTO organizations(id_organization, name, kind)
+	VALUES ($1, $2, $3)
+	`
+
+	_, err = tx.ExecContext(ctx, query, organization.ID, organization.Name, organization.Kind)
+	if err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	query = `
+	INSERT INTO organization_members(id_organization, id_user, role)
+	VALUES ($1, $2, $3)
+	`
+
+	_, err = tx.ExecContext(ctx, query, organization.ID, idOwner, consts.OrganizationMemberOwner)
+	if err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Get single organization by ID
+func (r organizationImplementation) GetByID(ctx context.Context, id uuid.UUID) (organization entity.Organization, err error) {
+	errorEvent := consts.ErrorEvent("get_organization")
+	ctx = tracer.SpanStart(ctx, "get_organization")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		SELECT id_organization, name, kind, created_at, updated_at
+		FROM organizations
+		WHERE id_organization = $1 AND deleted_at IS NULL
+	`
+
+	err = r.conn.QueryRow(ctx, query, id).Scan(
+		&organization.ID,
+		&organization.Name,
+		&organization.Kind,
+		&organization.CreatedAt,
+		&organization.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.OrganizationNotFoundMessage))
+		tracer.SpanError(ctx, err)
+		return organization, err
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return organization, err
+	}
+
+	return organization, nil
+}
+
+// List the organizations the user is a member of
+func (r organizationImplementation) ListByUser(ctx context.Context, idUser uuid.UUID) (organizations []entity.Organization, err error) {
+	errorEvent := consts.ErrorEvent("list_user_organizations")
+	ctx = tracer.SpanStart(ctx, "list_user_organizations")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		SELECT o.id_organization, o.name, o.kind, o.created_at, o.updated_at, m.role
+		FROM organizations o
+		INNER JOIN organization_members m ON m.id_organization = o.id_organization
+		WHERE m.id_user = $1 AND o.deleted_at IS NULL
+		ORDER BY o.name
+	`
+
+	rows, err := r.conn.QueryRows(ctx, query, idUser)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations = []entity.Organization{}
+	for rows.Next() {
+		var organization entity.Organization
+		err := rows.Scan(
+			&organization.ID,
+			&organization.Name,
+			&organization.Kind,
+			&organization.CreatedAt,
+			&organization.UpdatedAt,
+			&organization.MemberRole,
+		)
+		if err != nil {
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return nil, err
+		}
+
+		organizations = append(organizations, organization)
+	}
+
+	return organizations, nil
+}
+
+// List the members of an organization
+func (r organizationImplementation) ListMembers(ctx context.Context, id uuid.UUID) (members []entity.OrganizationMember, err error) {
+	errorEvent := consts.ErrorEvent("list_organization_members")
+	ctx = tracer.SpanStart(ctx, "list_organization_members")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		SELECT m.id_organization, m.id_user, u.name, u.email, m.role, m.created_at
+		FROM organization_members m
+		INNER JOIN users u ON u.id_user = m.id_user
+		WHERE m.id_organization = $1 AND u.deleted_at IS NULL
+		ORDER BY m.created_at
+	`
+
+	rows, err := r.conn.QueryRows(ctx, query, id)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	members = []entity.OrganizationMember{}
+	for rows.Next() {
+		var member entity.OrganizationMember
+		err := rows.Scan(
+			&member.IDOrganization,
+			&member.IDUser,
+			&member.Name,
+			&member.Email,
+			&member.Role,
+			&member.CreatedAt,
+		)
+		if err != nil {
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return nil, err
+		}
+
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// Get the member role of the user, empty when the user is not a member
+func (r organizationImplementation) GetMemberRole(ctx context.Context, id uuid.UUID, idUser uuid.UUID) (role string, err error) {
+	errorEvent := consts.ErrorEvent("get_organization_member_role")
+	ctx = tracer.SpanStart(ctx, "get_organization_member_role")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		SELECT m.role
+		FROM organization_members m
+		INNER JOIN organizations o ON o.id_organization = m.id_organization
+		WHERE m.id_organization = $1 AND m.id_user = $2 AND o.deleted_at IS NULL
+	`
+
+	err = r.conn.QueryRow(ctx, query, id, idUser).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return "", err
+	}
+
+	return role, nil
+}
+
+// Add a member to the organization
+func (r organizationImplementation) AddMember(ctx context.Context, id uuid.UUID, idUser uuid.UUID, role string) (err error) {
+	errorEvent := consts.ErrorEvent("add_organization_member")
+	ctx = tracer.SpanStart(ctx, "add_organization_member")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+	INSERT INTO organization_members(id_organization, id_user, role)
+	VALUES ($1, $2, $3)
+	`
+
+	_, err = r.conn.Exec(ctx, query, id, idUser, role)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Remove a member, the last owner of an organization can not be removed
+func (r organizationImplementation) RemoveMember(ctx context.Context, id uuid.UUID, idUser uuid.UUID) (err error) {
+	errorEvent := consts.ErrorEvent("remove_organization_member")
+	ctx = tracer.SpanStart(ctx, "remove_organization_member")
+	defer tracer.SpanFinish(ctx)
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	// the owner rows stay locked until the member is removed, so concurrent removals of the owners
+	// are counted one after the other
+	query1 := `
+	SELECT id_user
+	FROM organization_members
+	WHERE id_organization = $1 AND role = $2
+	FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, query1, id, consts.OrganizationMemberOwner)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	owners, isOwner := 0, false
+	for rows.Next() {
+		var owner uuid.UUID
+		if err = rows.Scan(&owner); err != nil {
+			break
+		}
+
+		owners++
+		isOwner = isOwner || owner == idUser
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	rows.Close()
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	if isOwner && owners <= 1 {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.OrganizationLastOwner))
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	query2 := `
+	DELETE FROM organization_members
+	WHERE id_organization = $1 AND id_user = $2
+	`
+	result, err := tx.ExecContext(ctx, query2, id, idUser)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.OrganizationMemberNotFound))
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/cryptox"
+	"sharefood/pkg/postgres"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PartnerAPIKey stores the partner api keys, their secrets are encrypted at rest as
+// the signature check needs them in clear
+type PartnerAPIKey interface {
+	Create(ctx context.Context, key entity.PartnerAPIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (entity.PartnerAPIKey, error)
+	List(ctx context.Context) ([]entity.PartnerAPIKey, error)
+	Rotate(ctx context.Context, id uuid.UUID, secret string, rotatedAt time.Time, previousExpiresAt time.Time) error
+	Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+	Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	UseNonce(ctx context.Context, id uuid.UUID, nonce string, ttl time.Duration) (bool, error)
+}
+
+type partnerAPIKeyImplementation struct {
+	cache      cache.Cacher
+	conn       postgres.Adapter
+	encryptKey []byte
+}
+
+func NewPartnerAPIKeyRepository(cache cache.Cacher, conn postgres.Adapter, encryptKey []byte) PartnerAPIKey {
+	return &partnerAPIKeyImplementation{cache, conn, encryptKey}
+}
+
+// Create stores a newly issued key
+func (r partnerAPIKeyImplementation) Create(ctx context.Context, key entity.PartnerAPIKey) (err error) {
+	errorEvent := consts.ErrorEvent("create_partner_api_key")
+	ctx = tracer.SpanStart(ctx, "create_partner_api_key")
+	defer tracer.SpanFinish(ctx)
+
+	secret, err := cryptox.EncryptBase64AES(key.Secret, r.encryptKey)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	query := `
+	INSERT INTO partner_api_keys(id_api_key, name, id_organization, id_user, scopes, secret, created_by, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = r.conn.Exec(
+		ctx,
+		query,
+		key.ID,
+		key.Name,
+		key.IDOrganization,
+		key.IDUser,
+		pq.Array(key.Scopes),
+		secret,
+		key.CreatedBy,
+		key.CreatedAt,
+	)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByID key with its secrets decrypted, a zero key when it does not exist
+func (r partnerAPIKeyImplementation) GetByID(ctx context.Context, id uuid.UUID) (key entity.PartnerAPIKey, err error) {
+	errorEvent := consts.ErrorEvent("get_partner_api_key")
+	ctx = tracer.SpanStart(ctx, "get_partner_api_key")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		SELECT
+			id_api_key,
+			name,
+			id_organization,
+			id_user,
+			scopes,
+			secret,
+			COALESCE(previous_secret, ''),
+			previous_secret_expires_at,
+			created_by,
+			created_at,
+			rotated_at,
+			revoked_at,
+			last_used_at
+		FROM partner_api_keys
+		WHERE id_api_key = $1
+	`
+
+	err = r.conn.QueryRow(ctx, query, id).Scan(
+		&key.ID,
+		&key.Name,
+		&key.IDOrganization,
+		&key.IDUser,
+		pq.Array(&key.Scopes),
+		&key.Secret,
+		&key.PreviousSecret,
+		&key.PreviousSecretExpiresAt,
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.RotatedAt,
+		&key.RevokedAt,
+		&key.LastUsedAt,
+	)
+	if err == sql.ErrNoRows {
+		return entity.PartnerAPIKey{}, nil
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return entity.PartnerAPIKey{}, err
+	}
+
+	key.Secret, err = cryptox.DecryptBase64AES(key.Secret, r.encryptKey)
+	if err == nil && key.PreviousSecret != "" {
+		key.PreviousSecret, err = cryptox.DecryptBase64AES(key.PreviousSecret, r.encryptKey)
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return entity.PartnerAPIKey{}, err
+	}
+
+	return key, nil
+}
+
+// List every key, revoked ones included, without their secrets
+func (r partnerAPIKeyImplementation) List(ctx context.Context) (keys []entity.PartnerAPIKey, err error) {
+	errorEvent := consts.ErrorEvent("list_partner_api_keys")
+	ctx = tracer.SpanStart(ctx, "list_partner_api_keys")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		SELECT
+			id_api_key,
+			name,
+			id_organization,
+			id_user,
+			scopes,
+			previous_secret_expires_at,
+			created_by,
+			created_at,
+			rotated_at,
+			revoked_at,
+			last_used_at
+		FROM partner_api_keys
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.conn.QueryRows(ctx, query)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys = []entity.PartnerAPIKey{}
+	for rows.Next() {
+		var key entity.PartnerAPIKey
+		err := rows.Scan(
+			&key.ID,
+			&key.Name,
+			&key.IDOrganization,
+			&key.IDUser,
+			pq.Array(&key.Scopes),
+			&key.PreviousSecretExpiresAt,
+			&key.CreatedBy,
+			&key.CreatedAt,
+			&key.RotatedAt,
+			&key.RevokedAt,
+			&key.LastUsedAt,
+		)
+		if err != nil {
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// Rotate replaces the secret, the current one keeps being accepted until previousExpiresAt
+func (r partnerAPIKeyImplementation) Rotate(ctx context.Context, id uuid.UUID, secret string, rotatedAt time.Time, previousExpiresAt time.Time) (err error) {
+	errorEvent := consts.ErrorEvent("rotate_partner_api_key")
+	ctx = tracer.SpanStart(ctx, "rotate_partner_api_key")
+	defer tracer.SpanFinish(ctx)
+
+	encrypted, err := cryptox.EncryptBase64AES(secret, r.encryptKey)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	query := `
+		UPDATE partner_api_keys SET
+			previous_secret = secret,
+			previous_secret_expires_at = $1,
+			secret = $2,
+			rotated_at = $3
+		WHERE id_api_key = $4 AND revoked_at IS NULL
+	`
+
+	_, err = r.conn.Exec(ctx, query, previousExpiresAt, encrypted, rotatedAt, id)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Revoke the key, its signatures are rejected right away
+func (r partnerAPIKeyImplementation) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) (err error) {
+	errorEvent := consts.ErrorEvent("revoke_partner_api_key")
+	ctx = tracer.SpanStart(ctx, "revoke_partner_api_key")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		UPDATE partner_api_keys SET
+			revoked_at = $1,
+			previous_secret = NULL,
+			previous_secret_expires_at = NULL
+		WHERE id_api_key = $2 AND revoked_at IS NULL
+	`
+
+	_, err = r.conn.Exec(ctx, query, revokedAt, id)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Touch records the last time the key signed a request
+func (r partnerAPIKeyImplementation) Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) (err error) {
+	errorEvent := consts.ErrorEvent("touch_partner_api_key")
+	ctx = tracer.SpanStart(ctx, "touch_partner_api_key")
+	defer tracer.SpanFinish(ctx)
+
+	query := `UPDATE partner_api_keys SET last_used_at = $1 WHERE id_api_key = $2`
+
+	_, err = r.conn.Exec(ctx, query, usedAt, id)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// UseNonce marks the nonce of the key as used for ttl, false when it already was
+func (r partnerAPIKeyImplementation) UseNonce(ctx context.Context, id uuid.UUID, nonce string, ttl time.Duration) (bool, error) {
+	errorEvent := consts.ErrorEvent("use_partner_nonce")
+	ctx = tracer.SpanStart(ctx, "use_partner_nonce")
+	defer tracer.SpanFinish(ctx)
+
+	ok, err := r.cache.SetNX(ctx, consts.PartnerNonceKeyPrefix+id.String()+":"+nonce, 1, ttl)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return false, err
+	}
+
+	return ok, nil
+}
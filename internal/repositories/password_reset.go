@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/postgres"
+	"sharefood/pkg/tracer"
+	"time"
+)
+
+type PasswordReset interface {
+	Create(context.Context, *entity.PasswordReset) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (entity.PasswordReset, error)
+	Redeem(ctx context.Context, reset entity.PasswordReset, hashedPassword string) error
+}
+
+type passwordResetImplementation struct {
+	conn postgres.Adapter
+}
+
+func NewPasswordResetRepository(conn postgres.Adapter) PasswordReset {
+	return &passwordResetImplementation{conn}
+}
+
+// Create new reset token, older unused token of the same user are invalidated
+func (r passwordResetImplementation) Create(ctx context.Context, reset *entity.PasswordReset) (err error) {
+	errorEvent := consts.ErrorEvent("create_password_reset")
+	ctx = tracer.SpanStart(ctx, "create_password_reset")
+	defer tracer.SpanFinish(ctx)
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	query1 := `
+	UPDATE password_resets
+	SET
+		expired_at = $1
+	WHERE
+		id_user = $2 AND used_at IS NULL AND expired_at > $1;
+	`
+	_, err = tx.ExecContext(ctx, query1, time.Now().Local(), reset.IDUser)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	query2 := `
+	INSERT INTO password_resets(id_password_reset, id_user, token_hash, expired_at)
+	VALUES ($1, $2, $3, $4)
+	`
+	_, err = tx.ExecContext(ctx, query2, reset.ID, reset.IDUser, reset.TokenHash, reset.ExpiredAt)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Get reset token by the token hash
+func (r passwordResetImplementation) GetByTokenHash(ctx context.Context, tokenHash string) (reset entity.PasswordReset, err error) {
+	errorEvent := consts.ErrorEvent("get_password_reset")
+	ctx = tracer.SpanStart(ctx, "get_password_reset")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		SELECT id_password_reset, id_user, token_hash, expired_at, used_at, created_at
+		FROM password_resets
+		WHERE token_hash = $1
+	`
+
+	err = r.conn.QueryRow(ctx, query, tokenHash).Scan(
+		&reset.ID,
+		&reset.IDUser,
+		&reset.TokenHash,
+		&reset.ExpiredAt,
+		&reset.UsedAt,
+		&reset.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.ResetTokenNotValid))
+		tracer.SpanError(ctx, err)
+		return reset, err
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return reset, err
+	}
+
+	return reset, nil
+}
+
+// Redeem marks the token as used, replaces the password and revokes every issued session
+func (r passwordResetImplementation) Redeem(ctx context.Context, reset entity.PasswordReset, hashedPassword string) (err error) {
+	errorEvent := consts.ErrorEvent("redeem_password_reset")
+	ctx = tracer.SpanStart(ctx, "redeem_password_reset")
+	defer tracer.SpanFinish(ctx)
+
+	// the jwt issued at claim is kept to the microsecond, so is the revocation time
+	now := time.Now().Local()
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	// the used_at guard makes concurrent redemption of the same token fail
+	query1 := `
+	UPDATE password_resets
+	SET
+		used_at = $1
+	WHERE
+		id_password_reset = $2 AND used_at IS NULL;
+	`
+	result, err := tx.ExecContext(ctx, query1, now, reset.ID)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.ResetTokenNotValid))
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	query2 := `
+	UPDATE users
+	SET
+		password = $1,
+		sessions_revoked_at = $2
+	WHERE
+		id_user = $3;
+	`
+	_, err = tx.ExecContext(ctx, query2, hashedPassword, now, reset.IDUser)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/postgres"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PhoneVerification interface {
+	Create(context.Context, *entity.PhoneVerification) error
+	GetLatestByUser(ctx context.Context, idUser uuid.UUID) (entity.PhoneVerification, error)
+	ConsumeAttempt(ctx context.Context, id uuid.UUID, maxAttempt int) error
+	Verify(ctx context.Context, verification entity.PhoneVerification) error
+}
+
+type phoneVerificationImplementation struct {
+	conn postgres.Adapter
+}
+
+func NewPhoneVerificationRepository(conn postgres.Adapter) PhoneVerification {
+	return &phoneVerificationImplementation{conn}
+}
+
+// Create new otp code
+func (r phoneVerificationImplementation) Create(ctx context.Context, verification *entity.PhoneVerification) (err error) {
+	errorEvent := consts.ErrorEvent("create_phone_verification")
+	ctx = tracer.SpanStart(ctx, "create_phone_verification")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+	INSERT INTO phone_verifications(id_phone_verification, id_user, phone_number, code_hash, expired_at)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err = r.conn.Exec(ctx, query, verification.ID, verification.IDUser, verification.PhoneNumber, verification.CodeHash, verification.ExpiredAt)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Get the last otp code sent to the user, older codes are no longer valid
+func (r phoneVerificationImplementation) GetLatestByUser(ctx context.Context, idUser uuid.UUID) (verification entity.PhoneVerification, err error) {
+	errorEvent := consts.ErrorEvent("get_phone_verification")
+	ctx = tracer.SpanStart(ctx, "get_phone_verification")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		SELECT id_phone_verification, id_user, phone_number, code_hash, attempts, expired_at, verified_at, created_at
+		FROM phone_verifications
+		WHERE id_user = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	err = r.conn.QueryRow(ctx, query, idUser).Scan(
+		&verification.ID,
+		&verification.IDUser,
+		&verification.PhoneNumber,
+		&verification.CodeHash,
+		&verification.Attempts,
+		&verification.ExpiredAt,
+		&verification.VerifiedAt,
+		&verification.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.VerificationCodeNotValid))
+		tracer.SpanError(ctx, err)
+		return verification, err
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return verification, err
+	}
+
+	return verification, nil
+}
+
+// ConsumeAttempt counts an attempt at the otp code, in the same statement that checks the attempts
+// left so the guesses running in parallel cannot go over maxAttempt
+func (r phoneVerificationImplementation) ConsumeAttempt(ctx context.Context, id uuid.UUID, maxAttempt int) (err error) {
+	errorEvent := consts.ErrorEvent("consume_phone_verification_attempt")
+	ctx = tracer.SpanStart(ctx, "consume_phone_verification_attempt")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		UPDATE phone_verifications SET
+			attempts = attempts + 1
+		WHERE id_phone_verification = $1 AND attempts < $2
+		RETURNING attempts;
+	`
+
+	var attempts int
+	err = r.conn.QueryRow(ctx, query, id, maxAttempt).Scan(&attempts)
+	if err == sql.ErrNoRows {
+		err := errorEvent.WithCode(consts.CodeReachMaxLimit).WrapError(consts.Error(consts.VerificationCodeExhausted))
+		tracer.SpanError(ctx, err)
+		return err
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Verify marks the otp code as used and the user phone number as verified
+func (r phoneVerificationImplementation) Verify(ctx context.Context, verification entity.PhoneVerification) (err error) {
+	errorEvent := consts.ErrorEvent("verify_phone")
+	ctx = tracer.SpanStart(ctx, "verify_phone")
+	defer tracer.SpanFinish(ctx)
+
+	now := time.Now().Local()
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	query1 := `
+	UPDATE phone_verifications
+	SET
+		verified_at = $1
+	WHERE
+		id_phone_verification = $2 AND verified_at IS NULL;
+	`
+	result, err := tx.ExecContext(ctx, query1, now, verification.ID)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.VerificationCodeNotValid))
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	// the code only verifies the phone number it was sent to
+	query2 := `
+	UPDATE users
+	SET
+		phone_verified_at = $1
+	WHERE
+		id_user = $2 AND phone_number = $3;
+	`
+	result, err = tx.ExecContext(ctx, query2, now, verification.IDUser, verification.PhoneNumber)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.VerificationCodeNotValid))
+		tracer.SpanError(ctx, err)
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
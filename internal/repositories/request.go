@@ -200,6 +200,7 @@ func (r requestImplementation) GetRequestFoodByIDRequest(ctx context.Context, id
 		requests.status,
 		requests.quantity,
 		foods.id_user AS giver,
+		foods.id_organization,
 		foods.quantity AS stock
 	FROM requests
 	INNER JOIN foods
@@ -215,6 +216,7 @@ func (r requestImplementation) GetRequestFoodByIDRequest(ctx context.Context, id
 		&reqFood.Status,
 		&reqFood.Quantity,
 		&reqFood.IDUserFood,
+		&reqFood.IDOrgFood,
 		&reqFood.Stock,
 	)
 	if err == sql.ErrNoRows {
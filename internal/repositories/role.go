@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/postgres"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Role interface {
+	Grant(ctx context.Context, idUser uuid.UUID, role string, grantedBy uuid.UUID) error
+	Revoke(ctx context.Context, idUser uuid.UUID, role string, revokedAt time.Time) error
+	ListTwoFactor(ctx context.Context) ([]entity.TwoFactorRole, error)
+	RequireTwoFactor(ctx context.Context, role string, enforcedBy uuid.UUID) error
+	UnrequireTwoFactor(ctx context.Context, role string) error
+}
+
+type roleImplementation struct {
+	conn postgres.Adapter
+}
+
+func NewRoleRepository(conn postgres.Adapter) Role {
+	return &roleImplementation{conn}
+}
+
+// Grant a role to the user, granting a role the user already has does nothing
+func (r roleImplementation) Grant(ctx context.Context, idUser uuid.UUID, role string, grantedBy uuid.UUID) (err error) {
+	errorEvent := consts.ErrorEvent("grant_role")
+	ctx = tracer.SpanStart(ctx, "grant_role")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+	INSERT INTO user_roles(id_user, role, granted_by)
+	SELECT id_user, $2, $3 FROM users WHERE id_user = $1 AND deleted_at IS NULL
+	ON CONFLICT (id_user, role) DO NOTHING
+	`
+
+	_, err = r.conn.Exec(ctx, query, idUser, role, grantedBy)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Revoke a role from the user, the sessions issued before revokedAt are revoked
+// as their tokens still carry the role
+func (r roleImplementation) Revoke(ctx context.Context, idUser uuid.UUID, role string, revokedAt time.Time) (err error) {
+	errorEvent := consts.ErrorEvent("revoke_role")
+	ctx = tracer.SpanStart(ctx, "revoke_role")
+	defer tracer.SpanFinish(ctx)
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM user_roles WHERE id_user = $1 AND role = $2`, idUser, role)
+	if err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		tx.Rollback()
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE users SET sessions_revoked_at = $1 WHERE id_user = $2`, revokedAt, idUser)
+	if err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// ListTwoFactor roles only granted to accounts with two-factor authentication
+func (r roleImplementation) ListTwoFactor(ctx context.Context) (roles []entity.TwoFactorRole, err error) {
+	errorEvent := consts.ErrorEvent("list_two_factor_roles")
+	ctx = tracer.SpanStart(ctx, "list_two_factor_roles")
+	defer tracer.SpanFinish(ctx)
+
+	rows, err := r.conn.QueryRows(ctx, `SELECT role, enforced_by, created_at FROM two_factor_roles ORDER BY role`)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles = []entity.TwoFactorRole{}
+	for rows.Next() {
+		var role entity.TwoFactorRole
+		if err := rows.Scan(&role.Role, &role.EnforcedBy, &role.CreatedAt); err != nil {
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return nil, err
+		}
+
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
+// RequireTwoFactor withholds the permissions of the role from accounts without two-factor authentication,
+// requiring it for a role that already requires it does nothing
+func (r roleImplementation) RequireTwoFactor(ctx context.Context, role string, enforcedBy uuid.UUID) (err error) {
+	errorEvent := consts.ErrorEvent("require_two_factor_role")
+	ctx = tracer.SpanStart(ctx, "require_two_factor_role")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+	INSERT INTO two_factor_roles(role, enforced_by)
+	VALUES ($1, $2)
+	ON CONFLICT (role) DO NOTHING
+	`
+
+	_, err = r.conn.Exec(ctx, query, role, enforcedBy)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// UnrequireTwoFactor grants the permissions of the role to every holder again
+func (r roleImplementation) UnrequireTwoFactor(ctx context.Context, role string) (err error) {
+	errorEvent := consts.ErrorEvent("unrequire_two_factor_role")
+	ctx = tracer.SpanStart(ctx, "unrequire_two_factor_role")
+	defer tracer.SpanFinish(ctx)
+
+	_, err = r.conn.Exec(ctx, `DELETE FROM two_factor_roles WHERE role = $1`, role)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sharefood/internal/consts"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/cryptox"
+	"sharefood/pkg/postgres"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactor stores the totp secret and recovery codes of the accounts, the secret is encrypted
+// at rest as the codes are derived from it
+type TwoFactor interface {
+	SaveSecret(ctx context.Context, idUser uuid.UUID, secret string) error
+	GetSecret(ctx context.Context, idUser uuid.UUID) (string, error)
+	Enable(ctx context.Context, idUser uuid.UUID, enabledAt time.Time, recoveryCodeHashes []string) error
+	Disable(ctx context.Context, idUser uuid.UUID) error
+	UseRecoveryCode(ctx context.Context, idUser uuid.UUID, codeHash string, usedAt time.Time) (bool, error)
+	UseCode(ctx context.Context, idUser uuid.UUID, step int64, ttl time.Duration) (bool, error)
+	Attempt(ctx context.Context, challengeID string, ttl time.Duration) (int64, error)
+	Complete(ctx context.Context, challengeID string, ttl time.Duration) (bool, error)
+}
+
+type twoFactorImplementation struct {
+	cache      cache.Cacher
+	conn       postgres.Adapter
+	encryptKey []byte
+}
+
+func NewTwoFactorRepository(cache cache.Cacher, conn postgres.Adapter, encryptKey []byte) TwoFactor {
+	return &twoFactorImplementation{cache, conn, encryptKey}
+}
+
+// SaveSecret starts an enrollment, the secret of an enabled account is left alone
+func (r twoFactorImplementation) SaveSecret(ctx context.Context, idUser uuid.UUID, secret string) (err error) {
+	errorEvent := consts.ErrorEvent("save_two_factor_secret")
+	ctx = tracer.SpanStart(ctx, "save_two_factor_secret")
+	defer tracer.SpanFinish(ctx)
+
+	encrypted, err := cryptox.EncryptBase64AES(secret, r.encryptKey)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	query := `
+		UPDATE users SET two_factor_secret = $1
+		WHERE id_user = $2 AND two_factor_enabled_at IS NULL AND deleted_at IS NULL
+	`
+
+	_, err = r.conn.Exec(ctx, query, encrypted, idUser)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetSecret decrypted secret of the account, empty when no enrollment has been started
+func (r twoFactorImplementation) GetSecret(ctx context.Context, idUser uuid.UUID) (secret string, err error) {
+	errorEvent := consts.ErrorEvent("get_two_factor_secret")
+	ctx = tracer.SpanStart(ctx, "get_two_factor_secret")
+	defer tracer.SpanFinish(ctx)
+
+	query := `SELECT COALESCE(two_factor_secret, '') FROM users WHERE id_user = $1 AND deleted_at IS NULL`
+
+	err = r.conn.QueryRow(ctx, query, idUser).Scan(&secret)
+	if err == sql.ErrNoRows || (err == nil && secret == "") {
+		return "", nil
+	}
+	if err == nil {
+		secret, err = cryptox.DecryptBase64AES(secret, r.encryptKey)
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// Enable confirms the enrollment and replaces the recovery codes
+func (r twoFactorImplementation) Enable(ctx context.Context, idUser uuid.UUID, enabledAt time.Time, recoveryCodeHashes []string) (err error) {
+	errorEvent := consts.ErrorEvent("enable_two_factor")
+	ctx = tracer.SpanStart(ctx, "enable_two_factor")
+	defer tracer.SpanFinish(ctx)
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	query := `
+		UPDATE users SET two_factor_enabled_at = $1
+		WHERE id_user = $2 AND two_factor_secret IS NOT NULL AND two_factor_enabled_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, enabledAt, idUser)
+	if err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeDuplicateEntry).WrapError(consts.Error(consts.TwoFactorAlreadyEnabled))
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE id_user = $1`, idUser); err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		query := `INSERT INTO user_recovery_codes(id_user, code_hash, created_at) VALUES ($1, $2, $3)`
+		if _, err = tx.ExecContext(ctx, query, idUser, codeHash, enabledAt); err != nil {
+			tx.Rollback()
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Disable removes the secret and the recovery codes of the account
+func (r twoFactorImplementation) Disable(ctx context.Context, idUser uuid.UUID) (err error) {
+	errorEvent := consts.ErrorEvent("disable_two_factor")
+	ctx = tracer.SpanStart(ctx, "disable_two_factor")
+	defer tracer.SpanFinish(ctx)
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	queries := []string{
+		`UPDATE users SET two_factor_secret = NULL, two_factor_enabled_at = NULL WHERE id_user = $1`,
+		`DELETE FROM user_recovery_codes WHERE id_user = $1`,
+	}
+	for _, query := range queries {
+		if _, err = tx.ExecContext(ctx, query, idUser); err != nil {
+			tx.Rollback()
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used, false when the code is unknown or already used
+func (r twoFactorImplementation) UseRecoveryCode(ctx context.Context, idUser uuid.UUID, codeHash string, usedAt time.Time) (bool, error) {
+	errorEvent := consts.ErrorEvent("use_recovery_code")
+	ctx = tracer.SpanStart(ctx, "use_recovery_code")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		UPDATE user_recovery_codes SET used_at = $1
+		WHERE id_user = $2 AND code_hash = $3 AND used_at IS NULL
+	`
+
+	result, err := r.conn.Exec(ctx, query, usedAt, idUser, codeHash)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return false, err
+	}
+
+	affected, _ := result.RowsAffected()
+
+	return affected > 0, nil
+}
+
+// UseCode marks the time step of a code as used for ttl, false when a code of the step already signed in
+func (r twoFactorImplementation) UseCode(ctx context.Context, idUser uuid.UUID, step int64, ttl time.Duration) (bool, error) {
+	errorEvent := consts.ErrorEvent("use_two_factor_code")
+	ctx = tracer.SpanStart(ctx, "use_two_factor_code")
+	defer tracer.SpanFinish(ctx)
+
+	ok, err := r.cache.SetNX(ctx, fmt.Sprintf("%s%s:%d", consts.TwoFactorCodeKeyPrefix, idUser, step), 1, ttl)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return false, err
+	}
+
+	return ok, nil
+}
+
+// Attempt counts a code attempt for the challenge
+func (r twoFactorImplementation) Attempt(ctx context.Context, challengeID string, ttl time.Duration) (int64, error) {
+	errorEvent := consts.ErrorEvent("two_factor_attempt")
+	ctx = tracer.SpanStart(ctx, "two_factor_attempt")
+	defer tracer.SpanFinish(ctx)
+
+	n, err := r.cache.Increment(ctx, consts.TwoFactorAttemptKeyPrefix+challengeID, ttl)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Complete marks the challenge as exchanged for a token, false when it already was
+func (r twoFactorImplementation) Complete(ctx context.Context, challengeID string, ttl time.Duration) (bool, error) {
+	errorEvent := consts.ErrorEvent("complete_two_factor_challenge")
+	ctx = tracer.SpanStart(ctx, "complete_two_factor_challenge")
+	defer tracer.SpanFinish(ctx)
+
+	ok, err := r.cache.SetNX(ctx, consts.TwoFactorCompleteKeyPrefix+challengeID, 1, ttl)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return false, err
+	}
+
+	return ok, nil
+}
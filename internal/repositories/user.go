@@ -4,19 +4,36 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sharefood/internal/common"
+	"sharefood/internal/consts"
 	"sharefood/internal/entity"
-	"sharefood/pkg/logger"
 	"sharefood/pkg/postgres"
+	"sharefood/pkg/tracer"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type User interface {
-	List(context.Context) ([]entity.User, error)
-	GetByID(context.Context, int64) (entity.User, error)
+	List(ctx context.Context, filter entity.UserFilter) ([]entity.User, uint64, error)
+	GetByID(context.Context, uuid.UUID) (entity.User, error)
 	GetByEmail(context.Context, string) (entity.User, error)
 	Create(context.Context, *entity.User) error
 	IsRegistered(context.Context, string) bool
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error
+	UpdateProfile(ctx context.Context, user entity.User) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, password string, revokedAt time.Time) error
+	UpdateImageURL(ctx context.Context, id uuid.UUID, imageURL string) error
+	SetSuspended(ctx context.Context, id uuid.UUID, suspendedAt *time.Time) error
+	Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
+// userRolesColumn selects the roles of the user row as a text array
+const userRolesColumn = "ARRAY(SELECT role FROM user_roles WHERE user_roles.id_user = users.id_user ORDER BY role) AS roles"
+
 type userImplementation struct {
 	conn postgres.Adapter
 }
@@ -25,48 +42,113 @@ func NewUserRepository(conn postgres.Adapter) User {
 	return &userImplementation{conn}
 }
 
-// Get all users in the repository
-func (r userImplementation) List(ctx context.Context) (users []entity.User, err error) {
-	query := "SELECT id_user, name, email, password, image_url from users"
-	rows, err := r.conn.QueryRows(ctx, query)
-	fmt.Println(err)
+// List users matching the filter, along with the total of matching users
+func (r userImplementation) List(ctx context.Context, filter entity.UserFilter) (users []entity.User, total uint64, err error) {
+	errorEvent := consts.ErrorEvent("list_users")
+	ctx = tracer.SpanStart(ctx, "list_users")
+	defer tracer.SpanFinish(ctx)
+
+	where := []string{"deleted_at IS NULL"}
+	args := []interface{}{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		where = append(where, fmt.Sprintf("(name ILIKE $%d OR email ILIKE $%d OR phone_number ILIKE $%d)", len(args), len(args), len(args)))
+	}
+
+	switch filter.Status {
+	case consts.UserStatusActive:
+		where = append(where, "suspended_at IS NULL")
+	case consts.UserStatusSuspended:
+		where = append(where, "suspended_at IS NOT NULL")
+	}
+
+	conditions := strings.Join(where, " AND ")
+
+	err = r.conn.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE "+conditions, args...).Scan(&total)
 	if err != nil {
-		logger.Error(err)
-		return nil, err
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, common.PageToOffset(filter.Limit, filter.Page))
+	query := fmt.Sprintf(`
+		SELECT id_user, name, email, phone_number, image_url, bio, pickup_address, pickup_latitude, pickup_longitude,
+			%s, suspended_at, email_verified_at, phone_verified_at, two_factor_enabled_at
+		FROM users
+		WHERE %s
+		ORDER BY name, id_user
+		LIMIT $%d OFFSET $%d
+	`, userRolesColumn, conditions, len(args)-1, len(args))
+
+	rows, err := r.conn.QueryRows(ctx, query, args...)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return nil, 0, err
 	}
 	defer rows.Close()
 
+	users = []entity.User{}
 	for rows.Next() {
 		var user entity.User
 		err := rows.Scan(
 			&user.ID,
 			&user.Name,
 			&user.Email,
-			&user.Password,
+			&user.PhoneNumber,
 			&user.ImageUrl,
+			&user.Bio,
+			&user.PickupAddress,
+			&user.PickupLatitude,
+			&user.PickupLongitude,
+			pq.Array(&user.Roles),
+			&user.SuspendedAt,
+			&user.EmailVerifiedAt,
+			&user.PhoneVerifiedAt,
+			&user.TwoFactorEnabledAt,
 		)
-
 		if err != nil {
-			return nil, err
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return nil, 0, err
 		}
 
 		users = append(users, user)
 	}
 
-	return users, nil
+	return users, total, nil
 }
 
 // Get single user by ID
-func (r userImplementation) GetByID(ctx context.Context, id int64) (user entity.User, err error) {
+func (r userImplementation) GetByID(ctx context.Context, id uuid.UUID) (user entity.User, err error) {
 	query := `
-		SELECT name
+		SELECT id_user, name, email, phone_number, password, image_url, bio, pickup_address, pickup_latitude, pickup_longitude,
+			` + userRolesColumn + `, suspended_at, sessions_revoked_at, email_verified_at, phone_verified_at,
+			two_factor_enabled_at
 		FROM users
-		WHERE id = $1
+		WHERE (id_user = $1) AND (deleted_at IS NULL)
 	`
 
 	row := r.conn.QueryRow(ctx, query, id)
 	err = row.Scan(
+		&user.ID,
 		&user.Name,
+		&user.Email,
+		&user.PhoneNumber,
+		&user.Password,
+		&user.ImageUrl,
+		&user.Bio,
+		&user.PickupAddress,
+		&user.PickupLatitude,
+		&user.PickupLongitude,
+		pq.Array(&user.Roles),
+		&user.SuspendedAt,
+		&user.SessionsRevokedAt,
+		&user.EmailVerifiedAt,
+		&user.PhoneVerifiedAt,
+		&user.TwoFactorEnabledAt,
 	)
 	if err != nil {
 		err = fmt.Errorf("scanning user %w", err)
@@ -76,14 +158,25 @@ func (r userImplementation) GetByID(ctx context.Context, id int64) (user entity.
 	return user, nil
 }
 
-// Register User
+// Register User along with the granted roles
 func (r userImplementation) Create(ctx context.Context, user *entity.User) (err error) {
+	errorEvent := consts.ErrorEvent("create_user")
+	ctx = tracer.SpanStart(ctx, "create_user")
+	defer tracer.SpanFinish(ctx)
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
 	query := `
 	INSERT INTO users(id_user, email, name, phone_number, password) 
 	VALUES ($1, $2, $3, $4, $5)
 	`
 
-	_, err = r.conn.Exec(
+	_, err = tx.ExecContext(
 		ctx,
 		query,
 		user.ID,
@@ -92,9 +185,26 @@ func (r userImplementation) Create(ctx context.Context, user *entity.User) (err
 		user.PhoneNumber,
 		user.Password,
 	)
-
 	if err != nil {
-		fmt.Println(fmt.Errorf("executing query: %w", err))
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	for _, role := range user.Roles {
+		_, err = tx.ExecContext(ctx, `INSERT INTO user_roles(id_user, role) VALUES ($1, $2)`, user.ID, role)
+		if err != nil {
+			tx.Rollback()
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
 		return err
 	}
 
@@ -104,7 +214,7 @@ func (r userImplementation) Create(ctx context.Context, user *entity.User) (err
 // Get single user by email
 func (r userImplementation) GetByEmail(ctx context.Context, email string) (user entity.User, err error) {
 	query := `
-		SELECT id_user, name, email, phone_number, password, image_url
+		SELECT id_user, name, email, phone_number, password, image_url, ` + userRolesColumn + `, suspended_at, email_verified_at, phone_verified_at, two_factor_enabled_at
 		FROM users
 		WHERE (email = $1) AND (deleted_at IS NULL)
 	`
@@ -118,6 +228,11 @@ func (r userImplementation) GetByEmail(ctx context.Context, email string) (user
 		&user.PhoneNumber,
 		&user.Password,
 		&user.ImageUrl,
+		pq.Array(&user.Roles),
+		&user.SuspendedAt,
+		&user.EmailVerifiedAt,
+		&user.PhoneVerifiedAt,
+		&user.TwoFactorEnabledAt,
 	)
 
 	if err != nil {
@@ -144,3 +259,337 @@ func (r userImplementation) IsRegistered(ctx context.Context, email string) bool
 	}
 	return true
 }
+
+// Mark email verified, only when the email is still the one the link was issued for
+func (r userImplementation) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (err error) {
+	errorEvent := consts.ErrorEvent("mark_email_verified")
+	ctx = tracer.SpanStart(ctx, "mark_email_verified")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		UPDATE users SET
+			email_verified_at = $1
+		WHERE id_user = $2 AND email = $3 AND deleted_at IS NULL;
+	`
+
+	result, err := r.conn.Exec(ctx, query, time.Now().Local(), id, email)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.VerificationTokenNotValid))
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Update the editable profile fields, a changed phone number has to be verified again
+func (r userImplementation) UpdateProfile(ctx context.Context, user entity.User) (err error) {
+	errorEvent := consts.ErrorEvent("update_profile")
+	ctx = tracer.SpanStart(ctx, "update_profile")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		UPDATE users SET
+			name = $1,
+			phone_number = $2,
+			bio = $3,
+			pickup_address = $4,
+			pickup_latitude = $5,
+			pickup_longitude = $6,
+			phone_verified_at = CASE WHEN phone_number = $2 THEN phone_verified_at ELSE NULL END
+		WHERE id_user = $7 AND deleted_at IS NULL;
+	`
+
+	_, err = r.conn.Exec(
+		ctx,
+		query,
+		user.Name,
+		user.PhoneNumber,
+		user.Bio,
+		user.PickupAddress,
+		user.PickupLatitude,
+		user.PickupLongitude,
+		user.ID,
+	)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Update password and revoke the sessions issued before revokedAt
+func (r userImplementation) UpdatePassword(ctx context.Context, id uuid.UUID, password string, revokedAt time.Time) (err error) {
+	errorEvent := consts.ErrorEvent("update_password")
+	ctx = tracer.SpanStart(ctx, "update_password")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		UPDATE users SET
+			password = $1,
+			sessions_revoked_at = $2
+		WHERE id_user = $3 AND deleted_at IS NULL;
+	`
+
+	_, err = r.conn.Exec(ctx, query, password, revokedAt, id)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Update the avatar url
+func (r userImplementation) UpdateImageURL(ctx context.Context, id uuid.UUID, imageURL string) (err error) {
+	errorEvent := consts.ErrorEvent("update_image_url")
+	ctx = tracer.SpanStart(ctx, "update_image_url")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		UPDATE users SET
+			image_url = $1
+		WHERE id_user = $2 AND deleted_at IS NULL;
+	`
+
+	_, err = r.conn.Exec(ctx, query, imageURL, id)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Set or clear the suspension of a user
+func (r userImplementation) SetSuspended(ctx context.Context, id uuid.UUID, suspendedAt *time.Time) (err error) {
+	errorEvent := consts.ErrorEvent("set_user_suspended")
+	ctx = tracer.SpanStart(ctx, "set_user_suspended")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		UPDATE users SET
+			suspended_at = $1
+		WHERE id_user = $2 AND deleted_at IS NULL;
+	`
+
+	result, err := r.conn.Exec(ctx, query, suspendedAt, id)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.UserNotFoundMessage))
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete soft deletes the account and anonymizes its personal data, the foods and requests stay
+// as donation history but the listings are taken down and the partner API keys it owns revoked
+func (r userImplementation) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) (err error) {
+	errorEvent := consts.ErrorEvent("delete_user")
+	ctx = tracer.SpanStart(ctx, "delete_user")
+	defer tracer.SpanFinish(ctx)
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	query := `
+		UPDATE users SET
+			name = $1,
+			email = $2,
+			phone_number = '',
+			password = '',
+			image_url = '',
+			bio = '',
+			pickup_address = '',
+			pickup_latitude = '',
+			pickup_longitude = '',
+			email_verified_at = NULL,
+			phone_verified_at = NULL,
+			two_factor_secret = NULL,
+			two_factor_enabled_at = NULL,
+			sessions_revoked_at = $3,
+			deleted_at = $3
+		WHERE id_user = $4 AND deleted_at IS NULL;
+	`
+
+	result, err := tx.ExecContext(ctx, query, consts.DeletedAccountName, fmt.Sprintf(consts.DeletedAccountEmailFormat, id), deletedAt, id)
+	if err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.UserNotFoundMessage))
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	queries := []string{
+		`DELETE FROM user_roles WHERE id_user = $1`,
+		`DELETE FROM organization_members WHERE id_user = $1`,
+		`DELETE FROM password_resets WHERE id_user = $1`,
+		`DELETE FROM phone_verifications WHERE id_user = $1`,
+		`DELETE FROM user_recovery_codes WHERE id_user = $1`,
+	}
+	for _, query := range queries {
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			tx.Rollback()
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return err
+		}
+	}
+
+	query = `
+		UPDATE foods SET
+			is_active = false,
+			deleted_at = $1
+		WHERE id_user = $2 AND id_organization IS NULL AND deleted_at IS NULL;
+	`
+
+	if _, err = tx.ExecContext(ctx, query, deletedAt, id); err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	// the keys sign requests as their owner, they must not outlive the account
+	query = `
+		UPDATE partner_api_keys SET
+			revoked_at = $1,
+			previous_secret = NULL,
+			previous_secret_expires_at = NULL
+		WHERE id_user = $2 AND revoked_at IS NULL;
+	`
+
+	if _, err = tx.ExecContext(ctx, query, deletedAt, id); err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge hard deletes the accounts deleted before deletedBefore, along with the rows left of them. The
+// foods nobody ever requested are deleted, the requested ones make up the donation history so only their
+// pickup location and image are dropped. An account still referenced by that history, by an
+// organization food or as the author of a setting stays anonymized instead of deleted. It returns the
+// number of accounts deleted
+func (r userImplementation) Purge(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	errorEvent := consts.ErrorEvent("purge_users")
+	ctx = tracer.SpanStart(ctx, "purge_users")
+	defer tracer.SpanFinish(ctx)
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return 0, err
+	}
+
+	deletedUsers := `SELECT id_user FROM users WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	query := `
+		DELETE FROM foods
+		WHERE id_organization IS NULL
+			AND id_user IN (` + deletedUsers + `)
+			AND NOT EXISTS (SELECT 1 FROM requests WHERE requests.id_food = foods.id_food);
+	`
+
+	if _, err = tx.ExecContext(ctx, query, deletedBefore); err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return 0, err
+	}
+
+	query = `
+		UPDATE foods SET
+			image_url = '',
+			latitude = '',
+			longitude = ''
+		WHERE id_organization IS NULL
+			AND id_user IN (` + deletedUsers + `)
+			AND (image_url != '' OR latitude != '' OR longitude != '');
+	`
+
+	if _, err = tx.ExecContext(ctx, query, deletedBefore); err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return 0, err
+	}
+
+	purgeableUsers := deletedUsers + `
+			AND NOT EXISTS (SELECT 1 FROM foods WHERE foods.id_user = users.id_user)
+			AND NOT EXISTS (SELECT 1 FROM requests WHERE requests.id_user = users.id_user)
+			AND NOT EXISTS (SELECT 1 FROM two_factor_roles WHERE two_factor_roles.enforced_by = users.id_user)
+			AND NOT EXISTS (SELECT 1 FROM partner_api_keys WHERE partner_api_keys.id_user = users.id_user OR partner_api_keys.created_by = users.id_user)`
+
+	queries := []string{
+		`DELETE FROM user_identities WHERE id_user IN (` + deletedUsers + `)`,
+		`DELETE FROM login_lockouts WHERE id_user IN (` + deletedUsers + `)`,
+		`UPDATE user_roles SET granted_by = NULL WHERE granted_by IN (` + purgeableUsers + `)`,
+	}
+	for _, query := range queries {
+		if _, err = tx.ExecContext(ctx, query, deletedBefore); err != nil {
+			tx.Rollback()
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			tracer.SpanError(ctx, err)
+			return 0, err
+		}
+	}
+
+	query = `DELETE FROM users WHERE id_user IN (` + purgeableUsers + `)`
+
+	result, err := tx.ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		tx.Rollback()
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return 0, err
+	}
+
+	purged, _ = result.RowsAffected()
+
+	if err = tx.Commit(); err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return 0, err
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/postgres"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+)
+
+type UserIdentity interface {
+	GetUserID(ctx context.Context, provider string, subject string) (uuid.UUID, error)
+	Create(ctx context.Context, identity entity.UserIdentity) error
+}
+
+type userIdentityImplementation struct {
+	conn postgres.Adapter
+}
+
+func NewUserIdentityRepository(conn postgres.Adapter) UserIdentity {
+	return &userIdentityImplementation{conn}
+}
+
+// GetUserID of the account linked to the provider identity, uuid.Nil when none is linked yet
+func (r userIdentityImplementation) GetUserID(ctx context.Context, provider string, subject string) (idUser uuid.UUID, err error) {
+	errorEvent := consts.ErrorEvent("get_user_identity")
+	ctx = tracer.SpanStart(ctx, "get_user_identity")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+		SELECT id_user
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	err = r.conn.QueryRow(ctx, query, provider, subject).Scan(&idUser)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return uuid.Nil, err
+	}
+
+	return idUser, nil
+}
+
+// Create links the provider identity to an account
+func (r userIdentityImplementation) Create(ctx context.Context, identity entity.UserIdentity) (err error) {
+	errorEvent := consts.ErrorEvent("create_user_identity")
+	ctx = tracer.SpanStart(ctx, "create_user_identity")
+	defer tracer.SpanFinish(ctx)
+
+	query := `
+	INSERT INTO user_identities(provider, subject, id_user, email)
+	VALUES ($1, $2, $3, $4)
+	`
+
+	_, err = r.conn.Exec(ctx, query, identity.Provider, identity.Subject, identity.IDUser, identity.Email)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return err
+	}
+
+	return nil
+}
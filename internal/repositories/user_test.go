@@ -0,0 +1,85 @@
+// Package repositories
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_GetByEmail(t *testing.T) {
+	verifiedAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	row := func(emailVerifiedAt interface{}) map[string]driver.Value {
+		return map[string]driver.Value{
+			"id_user":           uuid.New().String(),
+			"name":              "budi",
+			"email":             "budi@sharefood.id",
+			"phone_number":      "+6281234567890",
+			"password":          "hashed",
+			"image_url":         "",
+			"roles":             []byte("{user}"),
+			"suspended_at":      nil,
+			"email_verified_at": emailVerifiedAt,
+			"phone_verified_at": nil,
+		}
+	}
+
+	t.Run("test verified account to be linked", func(t *testing.T) {
+		conn, _ := newFakeConn(t, row(verifiedAt))
+
+		user, err := NewUserRepository(conn).GetByEmail(context.Background(), "budi@sharefood.id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "budi@sharefood.id", user.Email)
+		assert.Equal(t, []string{"user"}, user.Roles)
+		if assert.NotNil(t, user.EmailVerifiedAt) {
+			assert.True(t, verifiedAt.Equal(*user.EmailVerifiedAt))
+		}
+		assert.Nil(t, user.PhoneVerifiedAt)
+	})
+
+	t.Run("test unverified account", func(t *testing.T) {
+		conn, _ := newFakeConn(t, row(nil))
+
+		user, err := NewUserRepository(conn).GetByEmail(context.Background(), "budi@sharefood.id")
+
+		assert.NoError(t, err)
+		assert.Nil(t, user.EmailVerifiedAt)
+	})
+
+	t.Run("test not found", func(t *testing.T) {
+		conn, _ := newFakeConn(t, nil)
+
+		_, err := NewUserRepository(conn).GetByEmail(context.Background(), "budi@sharefood.id")
+
+		assert.True(t, errors.Is(err, sql.ErrNoRows))
+	})
+}
+
+func TestUser_Delete(t *testing.T) {
+	id := uuid.New()
+	deletedAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	conn, db := newFakeConn(t, nil)
+
+	err := NewUserRepository(conn).Delete(context.Background(), id, deletedAt)
+
+	assert.NoError(t, err)
+	assert.True(t, db.committed)
+
+	revoked := false
+	for _, exec := range db.executed() {
+		if strings.Contains(exec.query, "UPDATE partner_api_keys SET") {
+			revoked = true
+			assert.Contains(t, exec.query, "revoked_at = $1")
+			assert.Equal(t, []driver.Value{deletedAt, id.String()}, exec.args)
+		}
+	}
+	assert.True(t, revoked, "partner API keys of the account are revoked with it")
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"sharefood/internal/consts"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/tracer"
+	"time"
+)
+
+// VerificationThrottle paces the verification codes and links sent and the guesses at them, in redis
+type VerificationThrottle interface {
+	Cooldown(ctx context.Context, key string, duration time.Duration) (bool, error)
+	Count(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+type verificationThrottleImplementation struct {
+	cache cache.Cacher
+}
+
+func NewVerificationThrottleRepository(cache cache.Cacher) VerificationThrottle {
+	return &verificationThrottleImplementation{cache}
+}
+
+// Cooldown starts the cooldown of key for the duration, false when it is still cooling down
+func (r verificationThrottleImplementation) Cooldown(ctx context.Context, key string, duration time.Duration) (bool, error) {
+	errorEvent := consts.ErrorEvent("cooldown_verification")
+	ctx = tracer.SpanStart(ctx, "cooldown_verification")
+	defer tracer.SpanFinish(ctx)
+
+	started, err := r.cache.SetNX(ctx, consts.VerificationCooldownKeyPrefix+key, 1, duration)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return false, err
+	}
+
+	return started, nil
+}
+
+// Count counts one more for key within the window, returning the count so far
+func (r verificationThrottleImplementation) Count(ctx context.Context, key string, window time.Duration) (int64, error) {
+	errorEvent := consts.ErrorEvent("count_verification")
+	ctx = tracer.SpanStart(ctx, "count_verification")
+	defer tracer.SpanFinish(ctx)
+
+	count, err := r.cache.Increment(ctx, consts.VerificationCountKeyPrefix+key, window)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		tracer.SpanError(ctx, err)
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"sharefood/internal/appctx"
 	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/openapi"
 	"sharefood/pkg/routerkit"
 )
 
@@ -15,4 +16,5 @@ type httpHandlerFunc func(request *http.Request, svc contract.UseCase, conf *app
 // Router is a contract router and must implement this interface
 type Router interface {
 	Route() *routerkit.Router
+	Spec() *openapi.Document
 }
@@ -0,0 +1,73 @@
+// Package router
+package router
+
+import (
+	"strings"
+
+	"sharefood/internal/middleware"
+	ucaseContract "sharefood/internal/ucase/contract"
+)
+
+// group routes sharing a path prefix and the middlewares run in front of them, the routes are
+// registered flat on the router of the version so matching does not depend on how they are grouped
+type group struct {
+	rtr     *router
+	version *version
+	prefix  string
+	chain   middleware.Chain
+	// security schemes the routes require, for the OpenAPI document
+	security []string
+}
+
+// group starts a route group of the api version v under prefix
+func (rtr *router) group(v *version, prefix string, mws ...middleware.Middleware) *group {
+	return &group{
+		rtr:     rtr,
+		version: v,
+		prefix:  prefix,
+		chain:   middleware.NewChain(mws...),
+	}
+}
+
+// group nests a group under prefix, the middlewares of g run before mws
+func (g *group) group(prefix string, mws ...middleware.Middleware) *group {
+	return &group{
+		rtr:      g.rtr,
+		version:  g.version,
+		prefix:   g.prefix + prefix,
+		chain:    g.chain.Append(mws...),
+		security: g.security,
+	}
+}
+
+// secured documents the routes of g, and the groups nested after, as requiring the schemes
+func (g *group) secured(schemes ...string) *group {
+	// copied, the nested groups share the slice of g
+	g.security = append(append([]string{}, g.security...), schemes...)
+	return g
+}
+
+// handle registers svc on the path under the group prefix, the middlewares of the group run
+// first, then the rate limit of the route and mws
+func (g *group) handle(method string, path string, hfn httpHandlerFunc, svc ucaseContract.UseCase, mws ...middleware.Middleware) {
+	path = g.prefix + path
+	chain := g.chain.Append(g.rtr.rateLimit(method, g.version.name, path)...).Append(mws...)
+	g.version.handle(method, path, g.rtr.handle(hfn, svc, chain...))
+	g.rtr.document(g.version, method, path, svc, g.security)
+}
+
+// rateLimit middleware limiting the route of the version when a positive limit is configured for
+// it, a rule without the version limits the route in every version and its alias
+func (rtr *router) rateLimit(method string, version string, path string) []middleware.Middleware {
+	if rtr.limiter == nil {
+		return nil
+	}
+
+	for _, rule := range rtr.config.RateLimit.Routes {
+		if rule.Limit > 0 && strings.EqualFold(rule.Method, method) && (rule.Path == path || rule.Path == "/"+version+path) {
+			return []middleware.Middleware{middleware.Before(middleware.NewRateLimit(rtr.limiter, rule))}
+		}
+	}
+
+	return nil
+}
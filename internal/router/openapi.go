@@ -0,0 +1,144 @@
+// Package router
+package router
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	ucaseContract "sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/openapi"
+	"sharefood/pkg/routerkit"
+)
+
+// docsPage renders the OpenAPI document served next to it
+//
+//go:embed docs.html
+var docsPage string
+
+var docsTemplate = template.Must(template.New("docs").Parse(docsPage))
+
+// renderDocs page loading the Redoc bundle of conf, checked against its integrity when there is one
+func renderDocs(conf appctx.OpenAPI) ([]byte, error) {
+	script := conf.RedocScript
+	if script == "" {
+		script = consts.OpenAPIRedocScriptDefault
+	}
+
+	b := bytes.Buffer{}
+	err := docsTemplate.Execute(&b, struct {
+		Script    string
+		Integrity string
+	}{script, conf.RedocIntegrity})
+
+	return b.Bytes(), err
+}
+
+// document records the route of the version for the OpenAPI document, what it reads and answers
+// is described by svc when it is documented. The unversioned aliases are left out
+func (rtr *router) document(v *version, method string, path string, svc ucaseContract.UseCase, security []string) {
+	route := openapi.Route{
+		Method:     method,
+		Path:       "/" + v.name + path,
+		Tag:        strings.Split(strings.Trim(path, "/"), "/")[0],
+		Security:   security,
+		Deprecated: v.deprecation != nil,
+	}
+
+	if d, ok := svc.(ucaseContract.Documented); ok {
+		doc := d.Doc()
+		route.Summary = doc.Summary
+		route.Payload = doc.Payload
+		route.Upload = doc.Upload
+		route.Result = doc.Result
+		route.Alternatives = doc.Alternatives
+		route.Code = doc.Code
+	}
+
+	rtr.routes = append(rtr.routes, route)
+}
+
+// openAPI document of the routes registered, every response in the appctx.Response envelope
+func (rtr *router) openAPI() *openapi.Document {
+	version := rtr.config.OpenAPI.Version
+	if version == "" {
+		version = consts.OpenAPIVersionDefault
+	}
+
+	doc := openapi.New(openapi.Info{
+		Title:       rtr.config.App.AppName,
+		Version:     version,
+		Description: fmt.Sprintf("The paths without a version alias the ones of %s during the migration to the versioned paths.", consts.APIVersion1),
+	})
+	doc.SecurityScheme(consts.SecurityBearer, openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+	})
+	doc.SecurityScheme(consts.SecurityPartnerSignature, openapi.SecurityScheme{
+		Type: "apiKey",
+		In:   "header",
+		Name: consts.HeaderAPIKey,
+		Description: fmt.Sprintf("id of the partner API key, the request is signed with its secret in %s along with %s and %s",
+			consts.HeaderSignature, consts.HeaderTimestamp, consts.HeaderNonce),
+	})
+	doc.Envelope("Response", appctx.Response{}, "data", map[string]interface{}{
+		"message": "",
+		"meta":    entity.Meta{},
+	})
+
+	for _, route := range rtr.routes {
+		doc.Add(route)
+	}
+	doc.Sort()
+
+	return doc
+}
+
+// serveOpenAPI serves the OpenAPI document of the routes and the page rendering it
+func (rtr *router) serveOpenAPI() {
+	if !rtr.config.OpenAPI.Enable {
+		return
+	}
+
+	b, err := json.Marshal(rtr.openAPI())
+	if err != nil {
+		logger.Fatal(err, logger.EventName("openapi"))
+	}
+
+	page, err := renderDocs(rtr.config.OpenAPI)
+	if err != nil {
+		logger.Fatal(err, logger.EventName("openapi"))
+	}
+	if rtr.config.OpenAPI.RedocIntegrity == "" {
+		logger.Warn("openapi redoc_integrity not set, the Redoc bundle of the docs page is not checked", logger.EventName("openapi"))
+	}
+
+	rtr.router.HandleFunc(consts.OpenAPIPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(consts.HeaderContentTypeKey, consts.HeaderContentTypeJSON)
+		w.Write(b)
+	}).Methods(http.MethodGet)
+
+	rtr.router.HandleFunc(consts.OpenAPIDocsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(consts.HeaderContentTypeKey, consts.HeaderContentTypeHTML)
+		w.Write(page)
+	}).Methods(http.MethodGet)
+}
+
+// Spec builds the routes without connecting to their dependencies, for their OpenAPI document only
+func (rtr *router) Spec() *openapi.Document {
+	kit := routerkit.NewRouter()
+
+	rtr.routes = nil
+	rtr.register(kit, kit.PathPrefix("/").Subrouter(), dependencies{})
+
+	return rtr.openAPI()
+}
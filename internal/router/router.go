@@ -3,35 +3,61 @@ package router
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"net"
 	"net/http"
+	"net/url"
 	"runtime/debug"
+	"strings"
+	"time"
 
 	"sharefood/internal/appctx"
 	"sharefood/internal/bootstrap"
 	"sharefood/internal/consts"
+	"sharefood/internal/entity"
 	"sharefood/internal/handler"
 	"sharefood/internal/middleware"
 	"sharefood/internal/repositories"
 	"sharefood/internal/ucase"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/etag"
+	"sharefood/pkg/jwtx"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/mailer"
 	"sharefood/pkg/msg"
+	"sharefood/pkg/oidc"
+	"sharefood/pkg/openapi"
+	"sharefood/pkg/postgres"
+	"sharefood/pkg/ratelimit"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/routerkit"
+	"sharefood/pkg/sms"
+	"sharefood/pkg/storage"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
 
 	//"sharefood/pkg/mariadb"
 	//"sharefood/internal/repositories"
 	"sharefood/internal/ucase/food"
+	"sharefood/internal/ucase/maintenance"
+	"sharefood/internal/ucase/organization"
+	"sharefood/internal/ucase/partner"
 	"sharefood/internal/ucase/request"
 	"sharefood/internal/ucase/user"
 
 	ucaseContract "sharefood/internal/ucase/contract"
 
-	"github.com/pkg/errors"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type router struct {
-	config *appctx.Config
-	router *routerkit.Router
+	config  *appctx.Config
+	router  *routerkit.Router
+	limiter *ratelimit.Limiter
+	// routes registered, for the OpenAPI document
+	routes []openapi.Route
 }
 
 // NewRouter initialize new router wil return Router Interface
@@ -39,13 +65,48 @@ func NewRouter(cfg *appctx.Config) Router {
 	bootstrap.RegistryMessage()
 	bootstrap.RegistryLogger(cfg)
 
+	trustedProxies, err := util.ParseCIDRs(cfg.App.TrustedProxies)
+	if err != nil {
+		logger.Fatal(logger.MessageFormat("trusted proxies not valid: %v", err))
+	}
+
+	mws := []func(http.Handler) http.Handler{middleware.NewStripUserID(), middleware.NewClientIP(trustedProxies), middleware.NewAccessLog(cfg.AccessLog)}
+	if cfg.Metrics.Enable {
+		mws = append(mws, middleware.NewMetrics())
+	}
+	// innermost, the access log and the metrics see the bytes sent
+	if cfg.Compression.Enable {
+		mws = append(mws, middleware.NewCompression(cfg.Compression))
+	}
+
+	opts := []routerkit.RouterOption{
+		routerkit.WithServiceName(cfg.App.AppName),
+		routerkit.WithMiddlewares(mws...),
+		routerkit.WithTrustedProxies(trustedProxies...),
+	}
+
+	if cfg.CORS.Enable {
+		opts = append(opts, routerkit.WithCORS(routerkit.CORS{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   cfg.CORS.AllowedMethods,
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			ExposedHeaders:   cfg.CORS.ExposedHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           time.Duration(cfg.CORS.MaxAgeSecond) * time.Second,
+		}))
+	}
+
 	return &router{
 		config: cfg,
-		router: routerkit.NewRouter(routerkit.WithServiceName(cfg.App.AppName)),
+		router: routerkit.NewRouter(opts...),
 	}
 }
 
-func (rtr *router) handle(hfn httpHandlerFunc, svc ucaseContract.UseCase, mdws ...middleware.MiddlewareFunc) http.HandlerFunc {
+func (rtr *router) handle(hfn httpHandlerFunc, svc ucaseContract.UseCase, mdws ...middleware.Middleware) http.HandlerFunc {
+	h := middleware.NewChain(mdws...).Then(func(w http.ResponseWriter, r *http.Request, conf *appctx.Config) appctx.Response {
+		return hfn(r, svc, conf)
+	})
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		lang := r.Header.Get(consts.HeaderLanguageKey)
 		if !msg.HaveLang(consts.RespOK, lang) {
@@ -53,6 +114,11 @@ func (rtr *router) handle(hfn httpHandlerFunc, svc ucaseContract.UseCase, mdws .
 			r.Header.Set(consts.HeaderLanguageKey, lang)
 		}
 
+		// one id correlates the logs, the trace and the response of the request
+		requestID := requestid.Parse(r.Header.Get(consts.HeaderRequestIDKey))
+		w.Header().Set(consts.HeaderRequestIDKey, requestID.String())
+		tracer.SpanTag(r.Context(), logger.RequestIDKey, requestID.String())
+
 		defer func() {
 			err := recover()
 			if err != nil {
@@ -72,53 +138,141 @@ func (rtr *router) handle(hfn httpHandlerFunc, svc ucaseContract.UseCase, mdws .
 
 		ctx := context.WithValue(r.Context(), "access", map[string]interface{}{
 			"path":      r.URL.Path,
-			"remote_ip": r.RemoteAddr,
+			"remote_ip": util.ClientIP(r),
 			"method":    r.Method,
 		})
 
-		req := r.WithContext(ctx)
+		ctx = requestid.NewContext(ctx, requestID)
 
-		// validate middleware
-		if err := middleware.FilterFunc(w, req, rtr.config, mdws); err != nil {
-			logger.Error(errors.Wrap(err, "error on middleware"))
-
-			switch e := err.(type) {
-			case middleware.Error:
-				rtr.response(w, e.Response)
-			default:
-				rtr.response(w, *appctx.NewResponse().
-					WithCode(http.StatusInternalServerError).
-					WithMessage(http.StatusText(http.StatusInternalServerError)))
-			}
-
-			return
-		}
+		req := r.WithContext(ctx)
 
-		resp := hfn(req, svc, rtr.config)
+		resp := h(w, req, rtr.config)
 		resp.WithLang(lang)
-		rtr.response(w, resp)
+		rtr.response(w, req, resp)
 	}
 }
 
 // response prints as a json and formatted string for DGP legacy
-func (rtr *router) response(w http.ResponseWriter, resp appctx.Response) {
+func (rtr *router) response(w http.ResponseWriter, r *http.Request, resp appctx.Response) {
 	w.Header().Set(consts.HeaderContentTypeKey, consts.HeaderContentTypeJSON)
 	resp.Generate()
+
+	read := resp.Code == consts.CodeSuccess && (r.Method == http.MethodGet || r.Method == http.MethodHead)
+	if read && resp.ETag == "" {
+		// tagged by the data, the meta carries the id of the request
+		b, _ := json.Marshal(resp.Data)
+		resp.ETag = etag.Weak(append([]byte(r.Header.Get(consts.HeaderLanguageKey)+"\n"), b...))
+	}
+
+	if resp.ETag != "" {
+		w.Header().Set(consts.HeaderETagKey, resp.ETag)
+	}
+
+	// neither cached nor compressed, see middleware.NewCompression
+	if resp.Secret {
+		w.Header().Set(consts.HeaderCacheControlKey, consts.CacheControlNoStore)
+	}
+
+	for _, c := range resp.Cookies {
+		http.SetCookie(w, c)
+	}
+
+	if read && etag.MatchWeak(r.Header.Get(consts.HeaderIfNoneMatchKey), resp.ETag) {
+		w.WriteHeader(consts.CodeNotModified)
+		return
+	}
+
 	w.WriteHeader(resp.Code)
 	w.Write(resp.Byte())
 	// return
 }
 
+// serveFileStorage serves objects of the file storage driver under the path of the public url
+func (rtr *router) serveFileStorage() {
+	cfg := rtr.config.Storage
+	if cfg.Driver != "" && cfg.Driver != "file" {
+		return
+	}
+
+	publicURL, err := url.Parse(cfg.PublicURL)
+	if err != nil || strings.Trim(publicURL.Path, "/") == "" {
+		return
+	}
+
+	prefix := "/" + strings.Trim(publicURL.Path, "/") + "/"
+	files := http.FileServer(http.Dir(cfg.Bucket))
+	rtr.router.PathPrefix(prefix).Handler(http.StripPrefix(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// no directory listing, the object names carry the account id
+		if r.URL.Path == "" || strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))).Methods(http.MethodGet)
+}
+
+// serveJWKS serves the public keys access tokens are verified with, for the services validating them
+func (rtr *router) serveJWKS(keys *jwtx.KeySet) {
+	jwks, err := keys.JWKS()
+	if err != nil {
+		logger.Fatal(err, logger.EventName("jwks"))
+	}
+
+	b, err := json.Marshal(jwks)
+	if err != nil {
+		logger.Fatal(err, logger.EventName("jwks"))
+	}
+
+	rtr.router.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(consts.HeaderContentTypeKey, consts.HeaderContentTypeJSON)
+		w.Header().Set(consts.HeaderCacheControlKey, consts.JWKSCacheControl)
+		w.Write(b)
+	}).Methods(http.MethodGet)
+}
+
+// serveMetrics serves the prometheus metrics for the scraper, the routes and traffic they tell about
+// are not for the public
+func (rtr *router) serveMetrics() {
+	cfg := rtr.config.Metrics
+	if !cfg.Enable {
+		return
+	}
+
+	allowedIPs := cfg.AllowedIPs
+	if len(allowedIPs) == 0 {
+		allowedIPs = []string{"127.0.0.1", "::1"}
+	}
+
+	allowed, err := util.ParseCIDRs(allowedIPs)
+	if err != nil {
+		logger.Fatal(logger.MessageFormat("metrics allowed ips not valid: %v", err))
+	}
+
+	rtr.router.Handle("/metrics", scraperOnly(promhttp.Handler(), allowed, cfg.Token)).Methods(http.MethodGet)
+}
+
+// scraperOnly lets through the requests from the allowed addresses or bearing the token, when one is set
+func scraperOnly(next http.Handler, allowed []*net.IPNet, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if util.ContainsIP(allowed, util.ClientIP(r)) ||
+			(token != "" && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	})
+}
+
 // Route preparing http router and will return mux router object
 func (rtr *router) Route() *routerkit.Router {
 
 	root := rtr.router.PathPrefix("/").Subrouter()
-	//in := root.PathPrefix("/in/").Subrouter()
 	liveness := root.PathPrefix("/").Subrouter()
-	//inV1 := in.PathPrefix("/v1/").Subrouter()
 
 	// open tracer setup
-	bootstrap.RegistryOpenTracing(rtr.config)
+	bootstrap.RegistryTracer(rtr.config)
 
 	//db := bootstrap.RegistryMariaMasterSlave(rtr.config.WriteDB, rtr.config.ReadDB, rtr.config.App.Timezone))
 	//db := bootstrap.RegistryMariaDB(rtr.config.WriteDB, rtr.config.App.Timezone)
@@ -135,107 +289,262 @@ func (rtr *router) Route() *routerkit.Router {
 	// db := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", host, port, user, password, dbname)
 	// result :=
 
-	// database connection
-	db := bootstrap.RegistryPostgreSQLMasterSlave(rtr.config.ReadDB, rtr.config.WriteDB, rtr.config.App.Timezone)
+	// redis connection
+	rds := bootstrap.RegistryRedisNative(rtr.config)
+	cacher := cache.NewCache(rds)
 
-	// repository
-	userRepository := repositories.NewUserRepository(db)
-	foodRepository := repositories.NewFoodRepository(db)
-	requestRepository := repositories.NewRequestRepository(db)
+	// rate limiting, the routes limited are configured in app.yaml
+	rtr.limiter = bootstrap.RegistryRateLimiter(rtr.config, cacher)
 
-	// User usecase
-	listUser := user.NewUserList(userRepository)
-	registerUser := user.NewUserRegister(userRepository)
-	loginUser := user.NewUserLogin(userRepository)
-
-	// Food usecase
-	listFood := food.NewFoodList(foodRepository)
-	getFood := food.NewFoodGet(foodRepository)
-	createFood := food.NewFoodCreate(foodRepository)
-
-	// Myfood usecase
-	listMyFood := food.NewMyFoodList(foodRepository)
-	getMyFood := food.NewMyFoodGet(foodRepository)
-	updateMyFood := food.NewMyFoodUpdate(foodRepository)
-	deleteMyFood := food.NewMyFoodDelete(foodRepository)
+	// database connection
+	db := bootstrap.RegistryPostgreSQLMasterSlave(rtr.config.WriteDB, rtr.config.ReadDB, rtr.config.App.Timezone)
 
-	// Request usecase
-	listRequestFood := request.NewRequestFoodList(requestRepository)
-	listRequestUser := request.NewRequestUserList(requestRepository)
-	createRequestFood := request.NewRequestFoodCreate(requestRepository, foodRepository)
-	actionRequestFood := request.NewRequestAction(requestRepository, foodRepository)
+	// readiness
+	readinessTimeout := time.Duration(rtr.config.Readiness.TimeoutSecond) * time.Second
+	if rtr.config.Readiness.TimeoutSecond < 1 {
+		readinessTimeout = consts.ReadinessTimeoutSecondDefault * time.Second
+	}
+	ready := ucase.NewReadinessCheck(readinessTimeout, bootstrap.RegistryReadinessChecks(rtr.config, db, rds)...)
 
-	root.HandleFunc("/users", rtr.handle(
+	liveness.HandleFunc("/readiness", rtr.handle(
 		handler.HttpRequest,
-		listUser,
-		middleware.ValidateBearerToken,
+		ready,
 	)).Methods(http.MethodGet)
 
-	root.HandleFunc("/user/register", rtr.handle(
-		handler.HttpRequest,
-		registerUser,
-	)).Methods(http.MethodPost)
+	// metrics
+	bootstrap.RegistryMetrics(rtr.config, db, rds)
+	rtr.serveMetrics()
 
-	root.HandleFunc("/user/login", rtr.handle(
-		handler.HttpRequest,
-		loginUser,
-	)).Methods(http.MethodPost)
+	// mailer
+	mail := bootstrap.RegistryMailer(rtr.config)
 
-	root.HandleFunc("/foods", rtr.handle(
-		handler.HttpRequest,
-		listFood, middleware.ValidateBearerToken,
-	)).Methods(http.MethodGet)
+	// sms
+	smsSender := bootstrap.RegistrySMSSender(rtr.config)
 
-	root.HandleFunc("/foods", rtr.handle(
-		handler.HttpRequest,
-		createFood, middleware.ValidateBearerToken,
-	)).Methods(http.MethodPost)
+	// storage
+	store := bootstrap.RegistryStorage(rtr.config)
+	rtr.serveFileStorage()
 
-	root.HandleFunc("/foods/{id}", rtr.handle(
-		handler.HttpRequest,
-		getFood, middleware.ValidateBearerToken,
-	)).Methods(http.MethodGet)
+	// identity providers
+	oidcProviders := bootstrap.RegistryOIDCProviders(rtr.config)
 
-	root.HandleFunc("/my-foods", rtr.handle(
-		handler.HttpRequest,
-		listMyFood, middleware.ValidateBearerToken,
-	)).Methods(http.MethodGet)
+	// access token keys
+	jwtKeys := bootstrap.RegistryJWTKeys(rtr.config)
+	rtr.serveJWKS(jwtKeys)
 
-	root.HandleFunc("/my-foods/request", rtr.handle(
-		handler.HttpRequest,
-		listRequestUser, middleware.ValidateBearerToken,
-	)).Methods(http.MethodGet)
+	rtr.register(rtr.router, root, dependencies{
+		db:            db,
+		cacher:        cacher,
+		mail:          mail,
+		smsSender:     smsSender,
+		store:         store,
+		oidcProviders: oidcProviders,
+		jwtKeys:       jwtKeys,
+	})
 
-	// acc or reject requests
-	root.HandleFunc("/my-foods/request/action", rtr.handle(
-		handler.HttpRequest,
-		actionRequestFood, middleware.ValidateBearerToken,
-	)).Methods(http.MethodPost)
+	// api document
+	rtr.serveOpenAPI()
 
-	root.HandleFunc("/my-foods/request/{id}", rtr.handle(
-		handler.HttpRequest,
-		listRequestFood, middleware.ValidateBearerToken,
-	)).Methods(http.MethodGet)
+	return rtr.router
 
-	root.HandleFunc("/foods/request/{id}", rtr.handle(
-		handler.HttpRequest,
-		createRequestFood, middleware.ValidateBearerToken,
-	)).Methods(http.MethodPost)
+}
 
-	root.HandleFunc("/my-foods/{id}", rtr.handle(
-		handler.HttpRequest,
-		getMyFood, middleware.ValidateBearerToken,
-	)).Methods(http.MethodGet)
+// dependencies the routes are built with, left zero when the routes are built for their api
+// document only
+type dependencies struct {
+	db            postgres.Adapter
+	cacher        cache.Cacher
+	mail          mailer.Mailer
+	smsSender     sms.Sender
+	store         storage.Storage
+	oidcProviders map[string]*oidc.Provider
+	jwtKeys       *jwtx.KeySet
+}
 
-	root.HandleFunc("/my-foods/{id}", rtr.handle(
-		handler.HttpRequest,
-		updateMyFood, middleware.ValidateBearerToken,
-	)).Methods(http.MethodPut)
+// register builds the use cases on deps and registers their routes under the versions of kit, the
+// routes of v1 are aliased on root
+func (rtr *router) register(kit *routerkit.Router, root *mux.Router, deps dependencies) {
+	// repository
+	userRepository := repositories.NewUserRepository(deps.db)
+	foodRepository := repositories.NewFoodRepository(deps.db)
+	requestRepository := repositories.NewRequestRepository(deps.db)
+	passwordResetRepository := repositories.NewPasswordResetRepository(deps.db)
+	phoneVerificationRepository := repositories.NewPhoneVerificationRepository(deps.db)
+	roleRepository := repositories.NewRoleRepository(deps.db)
+	organizationRepository := repositories.NewOrganizationRepository(deps.db)
+	loginAttemptRepository := repositories.NewLoginAttemptRepository(deps.cacher, deps.db)
+	verificationThrottleRepository := repositories.NewVerificationThrottleRepository(deps.cacher)
+	userIdentityRepository := repositories.NewUserIdentityRepository(deps.db)
+	oidcSessionRepository := repositories.NewOIDCSessionRepository(deps.cacher)
+	partnerAPIKeyRepository := repositories.NewPartnerAPIKeyRepository(deps.cacher, deps.db, []byte(rtr.config.App.EncryptKey))
+	twoFactorRepository := repositories.NewTwoFactorRepository(deps.cacher, deps.db, []byte(rtr.config.App.EncryptKey))
+	idempotencyRepository := repositories.NewIdempotencyRepository(deps.cacher)
+	maintenanceRepository := repositories.NewMaintenanceRepository(deps.cacher, entity.Maintenance{
+		Enabled:          rtr.config.App.Maintenance,
+		RetryAfterSecond: rtr.config.App.MaintenanceRetryAfterSecond,
+	})
+
+	// middleware
+	validateBearerToken := middleware.NewValidateBearerToken(userRepository, roleRepository, deps.jwtKeys)
+	idempotency := middleware.NewIdempotency(idempotencyRepository)
+
+	maintenanceAllowedIPs, err := util.ParseCIDRs(rtr.config.App.MaintenanceAllowedIPs)
+	if err != nil {
+		logger.Fatal(logger.MessageFormat("maintenance allowed ips not valid: %v", err))
+	}
+	maintenanceMode := middleware.Before(middleware.NewMaintenance(maintenanceRepository, maintenanceAllowedIPs, deps.jwtKeys))
 
-	root.HandleFunc("/my-foods/{id}", rtr.handle(
-		handler.HttpRequest,
-		deleteMyFood, middleware.ValidateBearerToken,
-	)).Methods(http.MethodDelete)
+	// User usecase
+	listUser := user.NewUserList(userRepository)
+	suspendUser := user.NewUserSuspend(userRepository)
+	unsuspendUser := user.NewUserUnsuspend(userRepository)
+	grantRole := user.NewRoleGrant(userRepository, roleRepository)
+	revokeRole := user.NewRoleRevoke(userRepository, roleRepository)
+	listTwoFactorRole := user.NewTwoFactorRoleList(roleRepository)
+	requireTwoFactorRole := user.NewTwoFactorRoleRequire(roleRepository)
+	unrequireTwoFactorRole := user.NewTwoFactorRoleUnrequire(roleRepository)
+	registerUser := user.NewUserRegister(userRepository, phoneVerificationRepository, verificationThrottleRepository, deps.mail, deps.smsSender, deps.jwtKeys)
+	loginUser := user.NewUserLogin(userRepository, loginAttemptRepository, deps.jwtKeys)
+	loginTwoFactor := user.NewUserLoginTwoFactor(userRepository, twoFactorRepository, loginAttemptRepository, deps.jwtKeys)
+	forgotPassword := user.NewPasswordForgot(userRepository, passwordResetRepository, deps.mail)
+	resetPassword := user.NewPasswordReset(passwordResetRepository)
+	verifyEmail := user.NewEmailVerify(userRepository, deps.jwtKeys)
+	resendEmailVerification := user.NewEmailVerificationResend(userRepository, verificationThrottleRepository, deps.mail, deps.jwtKeys)
+	sendPhoneOTP := user.NewPhoneOTPSend(userRepository, phoneVerificationRepository, verificationThrottleRepository, deps.smsSender)
+	verifyPhone := user.NewPhoneVerify(userRepository, phoneVerificationRepository, verificationThrottleRepository)
+	oidcLogin := user.NewOIDCLogin(deps.oidcProviders, oidcSessionRepository)
+	oidcCallback := user.NewOIDCCallback(deps.oidcProviders, oidcSessionRepository, userRepository, userIdentityRepository, deps.jwtKeys)
+
+	// Profile usecase
+	getProfile := user.NewProfileGet(userRepository)
+	updateProfile := user.NewProfileUpdate(userRepository)
+	updatePassword := user.NewProfilePasswordUpdate(userRepository, deps.jwtKeys)
+	uploadAvatar := user.NewProfileAvatarUpload(userRepository, deps.store)
+	exportProfile := user.NewProfileExport(userRepository, foodRepository, requestRepository, organizationRepository)
+	deleteProfile := user.NewProfileDelete(userRepository, organizationRepository, deps.store)
+	enrollTwoFactor := user.NewTwoFactorEnroll(userRepository, twoFactorRepository)
+	confirmTwoFactor := user.NewTwoFactorConfirm(userRepository, twoFactorRepository)
+	disableTwoFactor := user.NewTwoFactorDisable(userRepository, twoFactorRepository)
+
+	// Food usecase
+	listFood := food.NewFoodList(foodRepository)
+	getFood := food.NewFoodGet(foodRepository)
+	createFood := food.NewFoodCreate(foodRepository, organizationRepository)
+
+	// Myfood usecase
+	listMyFood := food.NewMyFoodList(foodRepository)
+	getMyFood := food.NewMyFoodGet(foodRepository, organizationRepository)
+	updateMyFood := food.NewMyFoodUpdate(foodRepository, organizationRepository)
+	deleteMyFood := food.NewMyFoodDelete(foodRepository, organizationRepository)
+
+	// Request usecase
+	listRequestFood := request.NewRequestFoodList(requestRepository, foodRepository, organizationRepository)
+	listRequestUser := request.NewRequestUserList(requestRepository)
+	createRequestFood := request.NewRequestFoodCreate(requestRepository, foodRepository)
+	actionRequestFood := request.NewRequestAction(requestRepository, foodRepository, organizationRepository)
+
+	// Organization usecase
+	createOrganization := organization.NewOrganizationCreate(organizationRepository)
+	listOrganization := organization.NewOrganizationList(organizationRepository)
+	getOrganization := organization.NewOrganizationGet(organizationRepository)
+	addOrganizationMember := organization.NewOrganizationMemberAdd(organizationRepository, userRepository)
+	removeOrganizationMember := organization.NewOrganizationMemberRemove(organizationRepository)
+
+	// Partner usecase
+	issuePartnerKey := partner.NewKeyIssue(partnerAPIKeyRepository, organizationRepository)
+	listPartnerKey := partner.NewKeyList(partnerAPIKeyRepository)
+	rotatePartnerKey := partner.NewKeyRotate(partnerAPIKeyRepository)
+	revokePartnerKey := partner.NewKeyRevoke(partnerAPIKeyRepository)
+
+	// Maintenance usecase
+	getMaintenance := maintenance.NewMaintenanceGet(maintenanceRepository)
+	enableMaintenance := maintenance.NewMaintenanceEnable(maintenanceRepository)
+	disableMaintenance := maintenance.NewMaintenanceDisable(maintenanceRepository)
+
+	// api versions, the unversioned paths alias v1 until the clients are migrated
+	v1 := rtr.version(kit, consts.APIVersion1, root)
+
+	// route groups
+	// sign in stays open in maintenance so the moderators and admins can get a token through
+	signIn := rtr.group(v1, "")
+	public := rtr.group(v1, "", maintenanceMode)
+	authenticated := rtr.group(v1, "", maintenanceMode, middleware.Before(validateBearerToken), idempotency).secured(consts.SecurityBearer)
+	users := authenticated.group("/users")
+	twoFactorRoles := authenticated.group("/two-factor/roles", middleware.Before(middleware.RequirePermission(consts.PermissionRoleManage)))
+	partnerKeys := authenticated.group("/partner-keys", middleware.Before(middleware.RequirePermission(consts.PermissionPartnerManage)))
+	partnerAPI := rtr.group(v1, "/partner", maintenanceMode, middleware.Before(middleware.NewValidateSignature(partnerAPIKeyRepository, userRepository, consts.PartnerScopeFoodWrite)), idempotency).secured(consts.SecurityPartnerSignature)
+	userVerify := authenticated.group("/user/verify")
+	me := authenticated.group("/me")
+	foods := authenticated.group("/foods")
+	myFoods := authenticated.group("/my-foods")
+	organizations := authenticated.group("/organizations")
+	maintenanceAdmin := authenticated.group("/maintenance", middleware.Before(middleware.RequirePermission(consts.PermissionMaintenanceManage)))
+
+	users.handle(http.MethodGet, "", handler.HttpRequest, listUser, middleware.Before(middleware.RequirePermission(consts.PermissionUserRead)))
+	users.handle(http.MethodPost, "/{id}/roles", handler.HttpRequest, grantRole, middleware.Before(middleware.RequirePermission(consts.PermissionRoleManage)))
+	users.handle(http.MethodDelete, "/{id}/roles/{role}", handler.HttpRequest, revokeRole, middleware.Before(middleware.RequirePermission(consts.PermissionRoleManage)))
+	users.handle(http.MethodPost, "/{id}/suspend", handler.HttpRequest, suspendUser, middleware.Before(middleware.RequirePermission(consts.PermissionUserSuspend)))
+	users.handle(http.MethodPost, "/{id}/unsuspend", handler.HttpRequest, unsuspendUser, middleware.Before(middleware.RequirePermission(consts.PermissionUserSuspend)))
+
+	twoFactorRoles.handle(http.MethodGet, "", handler.HttpRequest, listTwoFactorRole)
+	twoFactorRoles.handle(http.MethodPut, "/{role}", handler.HttpRequest, requireTwoFactorRole)
+	twoFactorRoles.handle(http.MethodDelete, "/{role}", handler.HttpRequest, unrequireTwoFactorRole)
+
+	partnerKeys.handle(http.MethodGet, "", handler.HttpRequest, listPartnerKey)
+	partnerKeys.handle(http.MethodPost, "", handler.HttpRequest, issuePartnerKey)
+	partnerKeys.handle(http.MethodPost, "/{id}/rotate", handler.HttpRequest, rotatePartnerKey)
+	partnerKeys.handle(http.MethodDelete, "/{id}", handler.HttpRequest, revokePartnerKey)
+
+	partnerAPI.handle(http.MethodPost, "/foods", handler.HttpRequest, createFood)
+
+	public.handle(http.MethodPost, "/user/register", handler.HttpRequest, registerUser)
+	signIn.handle(http.MethodPost, "/user/login", handler.HttpRequest, loginUser)
+	signIn.handle(http.MethodPost, "/user/login/2fa", handler.HttpRequest, loginTwoFactor)
+	signIn.handle(http.MethodGet, "/auth/oidc/{provider}/login", handler.HttpRequest, oidcLogin)
+	signIn.handle(http.MethodGet, "/auth/oidc/{provider}/callback", handler.HttpRequest, oidcCallback)
+	public.handle(http.MethodPost, "/user/password/forgot", handler.HttpRequest, forgotPassword)
+	public.handle(http.MethodPost, "/user/password/reset", handler.HttpRequest, resetPassword)
+	public.handle(http.MethodGet, "/user/verify/email", handler.HttpRequest, verifyEmail)
+
+	userVerify.handle(http.MethodPost, "/email/resend", handler.HttpRequest, resendEmailVerification)
+	userVerify.handle(http.MethodPost, "/phone/send", handler.HttpRequest, sendPhoneOTP)
+	userVerify.handle(http.MethodPost, "/phone", handler.HttpRequest, verifyPhone)
+
+	me.handle(http.MethodGet, "", handler.HttpRequest, getProfile)
+	me.handle(http.MethodPut, "", handler.HttpRequest, updateProfile)
+	me.handle(http.MethodDelete, "", handler.HttpRequest, deleteProfile)
+	me.handle(http.MethodGet, "/export", handler.HttpRequest, exportProfile)
+	me.handle(http.MethodPost, "/2fa", handler.HttpRequest, enrollTwoFactor)
+	me.handle(http.MethodPost, "/2fa/confirm", handler.HttpRequest, confirmTwoFactor)
+	me.handle(http.MethodDelete, "/2fa", handler.HttpRequest, disableTwoFactor)
+	me.handle(http.MethodPut, "/password", handler.HttpRequest, updatePassword)
+	me.handle(http.MethodPost, "/avatar", handler.HttpRequest, uploadAvatar)
+
+	foods.handle(http.MethodGet, "", handler.HttpRequest, listFood)
+	foods.handle(http.MethodPost, "", handler.HttpRequest, createFood,
+		middleware.Before(middleware.RequirePermission(consts.PermissionFoodShare)), middleware.Before(middleware.RequireVerified))
+	foods.handle(http.MethodGet, "/{id}", handler.HttpRequest, getFood)
+	foods.handle(http.MethodPost, "/request/{id}", handler.HttpRequest, createRequestFood,
+		middleware.Before(middleware.RequirePermission(consts.PermissionFoodRequest)), middleware.Before(middleware.RequireVerified))
+
+	myFoods.handle(http.MethodGet, "", handler.HttpRequest, listMyFood, middleware.Before(middleware.RequirePermission(consts.PermissionFoodShare)))
+	myFoods.handle(http.MethodGet, "/request", handler.HttpRequest, listRequestUser, middleware.Before(middleware.RequirePermission(consts.PermissionFoodRequest)))
+	// acc or reject requests
+	myFoods.handle(http.MethodPost, "/request/action", handler.HttpRequest, actionRequestFood, middleware.Before(middleware.RequirePermission(consts.PermissionFoodShare)))
+	myFoods.handle(http.MethodGet, "/request/{id}", handler.HttpRequest, listRequestFood, middleware.Before(middleware.RequirePermission(consts.PermissionFoodShare)))
+	myFoods.handle(http.MethodGet, "/{id}", handler.HttpRequest, getMyFood, middleware.Before(middleware.RequirePermission(consts.PermissionFoodShare)))
+	myFoods.handle(http.MethodPut, "/{id}", handler.HttpRequest, updateMyFood, middleware.Before(middleware.RequirePermission(consts.PermissionFoodShare)))
+	myFoods.handle(http.MethodDelete, "/{id}", handler.HttpRequest, deleteMyFood, middleware.Before(middleware.RequirePermission(consts.PermissionFoodShare)))
+
+	organizations.handle(http.MethodPost, "", handler.HttpRequest, createOrganization)
+	organizations.handle(http.MethodGet, "", handler.HttpRequest, listOrganization)
+	organizations.handle(http.MethodGet, "/{id}", handler.HttpRequest, getOrganization)
+	organizations.handle(http.MethodPost, "/{id}/members", handler.HttpRequest, addOrganizationMember)
+	organizations.handle(http.MethodDelete, "/{id}/members/{id_user}", handler.HttpRequest, removeOrganizationMember)
+
+	maintenanceAdmin.handle(http.MethodGet, "", handler.HttpRequest, getMaintenance)
+	maintenanceAdmin.handle(http.MethodPut, "", handler.HttpRequest, enableMaintenance)
+	maintenanceAdmin.handle(http.MethodDelete, "", handler.HttpRequest, disableMaintenance)
 
 	// this is use case for example purpose, please delete
 	//repoExample := repositories.NewExample(db)
@@ -247,21 +556,7 @@ func (rtr *router) Route() *routerkit.Router {
 
 	// this route for example rest, please delete
 	// example list
-	//inV1.HandleFunc("/example", rtr.handle(
-	//    handler.HttpRequest,
-	//    el,
-	//)).Methods(http.MethodGet)
-
-	//inV1.HandleFunc("/example", rtr.handle(
-	//    handler.HttpRequest,
-	//    ec,
-	//)).Methods(http.MethodPost)
-
-	//inV1.HandleFunc("/example/{id:[0-9]+}", rtr.handle(
-	//    handler.HttpRequest,
-	//    ed,
-	//)).Methods(http.MethodDelete)
-
-	return rtr.router
-
+	//public.handle(http.MethodGet, "/example", handler.HttpRequest, el)
+	//public.handle(http.MethodPost, "/example", handler.HttpRequest, ec)
+	//public.handle(http.MethodDelete, "/example/{id:[0-9]+}", handler.HttpRequest, ed)
 }
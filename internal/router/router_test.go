@@ -0,0 +1,136 @@
+// Package router
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/pkg/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScraperOnly(t *testing.T) {
+	allowed, _ := util.ParseCIDRs([]string{"127.0.0.1", "10.0.0.0/8"})
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testCase := map[string]struct {
+		token         string
+		remoteAddr    string
+		authorization string
+		code          int
+	}{
+		"loopback":                 {"", "127.0.0.1:4321", "", http.StatusOK},
+		"allowed range":            {"", "10.1.2.3:4321", "", http.StatusOK},
+		"public":                   {"", "192.0.2.1:4321", "", http.StatusForbidden},
+		"public with the token":    {"scrape-token", "192.0.2.1:4321", "Bearer scrape-token", http.StatusOK},
+		"public with another":      {"scrape-token", "192.0.2.1:4321", "Bearer guess", http.StatusForbidden},
+		"empty token never passes": {"", "192.0.2.1:4321", "Bearer ", http.StatusForbidden},
+	}
+
+	for name, tc := range testCase {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.RemoteAddr = tc.remoteAddr
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+
+		w := httptest.NewRecorder()
+		scraperOnly(metrics, allowed, tc.token).ServeHTTP(w, req)
+
+		assert.Equal(t, tc.code, w.Code, name)
+	}
+}
+
+func TestRenderDocs(t *testing.T) {
+	t.Run("test default pinned script", func(t *testing.T) {
+		page, err := renderDocs(appctx.OpenAPI{})
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(page), `src="`+consts.OpenAPIRedocScriptDefault+`"`)
+		assert.NotContains(t, string(page), "integrity")
+	})
+
+	t.Run("test integrity", func(t *testing.T) {
+		page, err := renderDocs(appctx.OpenAPI{
+			RedocScript:    "https://cdn.example.com/redoc@2.1.5/redoc.standalone.js",
+			RedocIntegrity: "sha384-abc+/def=",
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(page), `src="https://cdn.example.com/redoc@2.1.5/redoc.standalone.js"`)
+		assert.Contains(t, string(page), `integrity="sha384-abc&#43;/def=" crossorigin="anonymous"`)
+	})
+}
+
+func TestResponse(t *testing.T) {
+	rtr := &router{config: &appctx.Config{}}
+	data := map[string]string{"id_food": "1"}
+
+	serve := func(method string, ifNoneMatch string, resp *appctx.Response) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/foods/1", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set(consts.HeaderIfNoneMatchKey, ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		rtr.response(rec, req, *resp)
+
+		return rec
+	}
+
+	t.Run("test read is tagged by its data", func(t *testing.T) {
+		rec := serve(http.MethodGet, "", appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(data))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Regexp(t, `^W/".+"$`, rec.Header().Get(consts.HeaderETagKey))
+		assert.NotEmpty(t, rec.Body.String())
+	})
+
+	t.Run("test not modified", func(t *testing.T) {
+		tag := serve(http.MethodGet, "", appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(data)).Header().Get(consts.HeaderETagKey)
+
+		rec := serve(http.MethodGet, tag, appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(data))
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, tag, rec.Header().Get(consts.HeaderETagKey))
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("test strong version tag matched weakly", func(t *testing.T) {
+		rec := serve(http.MethodGet, `W/"abc"`, appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(data).WithETag(`"abc"`))
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, `"abc"`, rec.Header().Get(consts.HeaderETagKey))
+	})
+
+	t.Run("test modified", func(t *testing.T) {
+		rec := serve(http.MethodGet, `W/"stale"`, appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(data))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEmpty(t, rec.Body.String())
+	})
+
+	t.Run("test write is not answered not modified", func(t *testing.T) {
+		rec := serve(http.MethodPut, `*`, appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(data).WithETag(`"abc"`))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"abc"`, rec.Header().Get(consts.HeaderETagKey))
+	})
+
+	t.Run("test secret is not stored", func(t *testing.T) {
+		rec := serve(http.MethodPost, "", appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(data).WithSecret())
+
+		assert.Equal(t, consts.CacheControlNoStore, rec.Header().Get(consts.HeaderCacheControlKey))
+	})
+
+	t.Run("test other responses are left to the caches", func(t *testing.T) {
+		rec := serve(http.MethodPost, "", appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(data))
+
+		assert.Empty(t, rec.Header().Get(consts.HeaderCacheControlKey))
+	})
+}
@@ -0,0 +1,96 @@
+// Package router
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/routerkit"
+
+	"github.com/gorilla/mux"
+)
+
+// version of the api, its routes are served under /name and, for the version the unversioned
+// paths alias, on the root as well
+type version struct {
+	name        string
+	mux         *mux.Router
+	deprecation *routerkit.Deprecation
+	// alias root the routes are aliased on, nil when they are not
+	alias            *mux.Router
+	aliasDeprecation *routerkit.Deprecation
+}
+
+// version starts the api version name on kit, its routes are aliased on alias when it is not nil
+func (rtr *router) version(kit *routerkit.Router, name string, alias *mux.Router) *version {
+	v := &version{
+		name:        name,
+		mux:         kit.Version(name),
+		deprecation: deprecation(rtr.config.APIVersion.Deprecations[name]),
+		alias:       alias,
+	}
+
+	if alias != nil {
+		v.aliasDeprecation = deprecation(rtr.config.APIVersion.Alias)
+	}
+
+	return v
+}
+
+// handle registers h on the path under the version, and on the alias pointing the clients to
+// the versioned path
+func (v *version) handle(method string, path string, h http.Handler) {
+	v.mux.Handle(path, deprecated(h, v.deprecation)).Methods(method)
+	if v.alias == nil {
+		return
+	}
+
+	v.alias.Handle(path, deprecated(successor(h, v.name), v.aliasDeprecation)).Methods(method)
+}
+
+// deprecated wraps h advertising d, h is returned as is when d is nil
+func deprecated(h http.Handler, d *routerkit.Deprecation) http.Handler {
+	if d == nil {
+		return h
+	}
+
+	return routerkit.Deprecated(h, *d)
+}
+
+// successor wraps h linking the path of the request under the version replacing it, the versioned path
+// is kept in the context so the request is recognized as the same one sent to it
+func successor(h http.Handler, version string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add(consts.HeaderLinkKey, fmt.Sprintf(`</%s%s>; rel="successor-version"`, version, r.URL.EscapedPath()))
+
+		ctx := context.WithValue(r.Context(), consts.CtxCanonicalPath, "/"+version+r.URL.Path)
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// deprecation of the config, nil when it has no deprecation date
+func deprecation(cfg appctx.Deprecation) *routerkit.Deprecation {
+	if cfg.DeprecatedAt == "" {
+		return nil
+	}
+
+	at, err := time.Parse(time.RFC3339, cfg.DeprecatedAt)
+	if err != nil {
+		logger.Fatal(logger.MessageFormat("deprecation date not valid: %v", err))
+	}
+
+	d := &routerkit.Deprecation{At: at, Link: cfg.Link}
+	if cfg.SunsetAt != "" {
+		d.Sunset, err = time.Parse(time.RFC3339, cfg.SunsetAt)
+		if err != nil {
+			logger.Fatal(logger.MessageFormat("sunset date not valid: %v", err))
+		}
+	}
+
+	return d
+}
@@ -11,6 +11,7 @@ import (
 	"sharefood/internal/consts"
 	"sharefood/internal/router"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/tracer"
 )
 
 // NewHTTPServer creates http server instance
@@ -61,6 +62,11 @@ func (h *httpServer) Run(ctx context.Context) error {
 		logger.Fatal(logger.MessageFormat("server Shutdown Failed:%v", err), logger.EventName(consts.LogEventNameServiceTerminated))
 	}
 
+	// flush the spans not exported yet
+	if errTracer := tracer.Shutdown(ctxShutDown); errTracer != nil {
+		logger.Warn(logger.MessageFormat("tracer shutdown: %v", errTracer), logger.EventName(consts.LogEventNameServiceTerminated))
+	}
+
 	logger.Info("server exited properly", logger.EventName(consts.LogEventNameServiceTerminated))
 
 	if err == http.ErrServerClosed {
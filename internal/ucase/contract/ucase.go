@@ -12,6 +12,26 @@ type UseCase interface {
 	Serve(data *appctx.Data) appctx.Response
 }
 
+// Doc of an http use case, for the api document
+type Doc struct {
+	Summary string
+	// Payload value of the type the use case casts the request into, nil when it reads none
+	Payload interface{}
+	// Upload name of the multipart form field the use case reads a file from
+	Upload string
+	// Result value of the type of the data of the success response, nil when it has none
+	Result interface{}
+	// Alternatives values of the other types the data of the success response may be of
+	Alternatives []interface{}
+	// Code status of the success response
+	Code int
+}
+
+// Documented is a use case describing itself in the api document
+type Documented interface {
+	Doc() Doc
+}
+
 // MessageProcessor is use case queue message processor contract
 type MessageProcessor interface {
 	Serve(ctx context.Context, data *appctx.ConsumerData) error
@@ -7,20 +7,33 @@ import (
 	"sharefood/internal/entity"
 	"sharefood/internal/repositories"
 	"sharefood/internal/response"
+	"sharefood/internal/ucase"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
 
 	"github.com/google/uuid"
 )
 
 type foodCreate struct {
-	foodRepository repositories.Food
+	foodRepository         repositories.Food
+	organizationRepository repositories.Organization
 }
 
-func NewFoodCreate(foodRepository repositories.Food) contract.UseCase {
+func NewFoodCreate(foodRepository repositories.Food, organizationRepository repositories.Organization) contract.UseCase {
 	return &foodCreate{
-		foodRepository: foodRepository,
+		foodRepository:         foodRepository,
+		organizationRepository: organizationRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *foodCreate) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Share a food",
+		Payload: entity.Food{},
+		Code:    consts.CodeCreated,
 	}
 }
 
@@ -32,7 +45,7 @@ func (u *foodCreate) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "create_food")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
 	payload := entity.Food{}
 
@@ -57,6 +70,28 @@ func (u *foodCreate) Serve(data *appctx.Data) appctx.Response {
 	// pass user id to payload
 	payload.IDUser = uuidUser
 
+	// a partner api key shares on behalf of the organization it was issued for
+	source := "user"
+	if key, ok := request.Context().Value(consts.CtxPartnerAPIKey).(entity.PartnerAPIKey); ok {
+		payload.IDOrganization = &key.IDOrganization
+		source = "partner"
+	}
+
+	// only a member may share on behalf of an organization
+	if payload.IDOrganization != nil {
+		allowed, errAccess := ucase.CanManageFood(ctx, u.organizationRepository, uuidUser, uuidUser, payload.IDOrganization)
+		if errAccess != nil {
+			logger.Error(logger.MessageFormat("[food-create] %v", errAccess))
+			err := errorEvent.WrapError(errAccess)
+			return *response.Failed(ctx, &transactionID, err)
+		}
+
+		if !allowed {
+			err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.StatusForbidden))
+			return *response.Failed(ctx, &transactionID, err)
+		}
+	}
+
 	fmt.Println(payload)
 
 	// create food to db
@@ -67,5 +102,7 @@ func (u *foodCreate) Serve(data *appctx.Data) appctx.Response {
 		return *response.Failed(ctx, &transactionID, err)
 	}
 
+	foodsCreated.WithLabelValues(source).Inc()
+
 	return *response.Success(ctx, consts.CodeCreated, &transactionID, nil)
 }
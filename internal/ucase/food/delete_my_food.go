@@ -5,8 +5,10 @@ import (
 	"sharefood/internal/consts"
 	"sharefood/internal/repositories"
 	"sharefood/internal/response"
+	"sharefood/internal/ucase"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
 
 	"github.com/google/uuid"
@@ -14,12 +16,22 @@ import (
 )
 
 type myFoodDelete struct {
-	foodRepositories repositories.Food
+	foodRepositories         repositories.Food
+	organizationRepositories repositories.Organization
 }
 
-func NewMyFoodDelete(foodRepositories repositories.Food) contract.UseCase {
+func NewMyFoodDelete(foodRepositories repositories.Food, organizationRepositories repositories.Organization) contract.UseCase {
 	return &myFoodDelete{
-		foodRepositories: foodRepositories,
+		foodRepositories:         foodRepositories,
+		organizationRepositories: organizationRepositories,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *myFoodDelete) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Delete a food of mine",
+		Code:    consts.CodeSuccess,
 	}
 }
 
@@ -31,7 +43,7 @@ func (u *myFoodDelete) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "delete_my_food")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
 	params := mux.Vars(data.Request)
 	rawID := params["id"]
@@ -62,7 +74,14 @@ func (u *myFoodDelete) Serve(data *appctx.Data) appctx.Response {
 	}
 
 	//check permission
-	if uuidUser != food.IDUser {
+	allowed, errAccess := ucase.CanManageFood(ctx, u.organizationRepositories, uuidUser, food.IDUser, food.IDOrganization)
+	if errAccess != nil {
+		logger.Error(logger.MessageFormat("[food-delete] %v", errAccess))
+		err := errorEvent.WrapError(errAccess)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !allowed {
 		logger.Error(logger.MessageFormat("[food-delete] id user not match"))
 		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.StatusForbidden))
 		return *response.Failed(ctx, &transactionID, err)
@@ -3,10 +3,12 @@ package food
 import (
 	"sharefood/internal/appctx"
 	"sharefood/internal/consts"
+	"sharefood/internal/entity"
 	"sharefood/internal/repositories"
 	"sharefood/internal/response"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
 
 	"github.com/google/uuid"
@@ -23,6 +25,15 @@ func NewFoodGet(foodRepositories repositories.Food) contract.UseCase {
 	}
 }
 
+// Doc implements contract.Documented
+func (u *foodGet) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Detail of a shared food",
+		Result:  entity.Food{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
 // Serve implements contract.UseCase
 func (u *foodGet) Serve(data *appctx.Data) appctx.Response {
 	request := data.Request
@@ -31,7 +42,7 @@ func (u *foodGet) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "get_detail_shared_food")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
 	params := mux.Vars(data.Request)
 	rawID := params["id"]
@@ -55,6 +66,6 @@ func (u *foodGet) Serve(data *appctx.Data) appctx.Response {
 		// return *appctx.NewResponse().WithCode(consts.CodeNotFound).WithError("Invalid Food ID").WithMessage("Get Food Failed").WithEntity("getDetailSharedFood").WithState("getDetailSharedFoodFailed").WithStatus(consts.StatusFailed)
 	}
 
-	return *response.Success(ctx, consts.CodeSuccess, &transactionID, food)
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, food).WithETag(foodETag(food))
 	// return *appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(food).WithMessage("Get Food Success").WithEntity("getDetailSharedFood").WithState("getDetailSharedFoodSuccess").WithStatus(consts.StatusSuccess)
 }
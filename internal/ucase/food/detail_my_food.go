@@ -3,10 +3,13 @@ package food
 import (
 	"sharefood/internal/appctx"
 	"sharefood/internal/consts"
+	"sharefood/internal/entity"
 	"sharefood/internal/repositories"
 	"sharefood/internal/response"
+	"sharefood/internal/ucase"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
 
 	"github.com/google/uuid"
@@ -14,12 +17,23 @@ import (
 )
 
 type myFoodGet struct {
-	foodRepositories repositories.Food
+	foodRepositories         repositories.Food
+	organizationRepositories repositories.Organization
 }
 
-func NewMyFoodGet(foodRepositories repositories.Food) contract.UseCase {
+func NewMyFoodGet(foodRepositories repositories.Food, organizationRepositories repositories.Organization) contract.UseCase {
 	return &myFoodGet{
-		foodRepositories: foodRepositories,
+		foodRepositories:         foodRepositories,
+		organizationRepositories: organizationRepositories,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *myFoodGet) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Detail of a food of mine",
+		Result:  entity.Food{},
+		Code:    consts.CodeSuccess,
 	}
 }
 
@@ -31,7 +45,7 @@ func (u *myFoodGet) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "get_detail_my_food")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
 	params := mux.Vars(data.Request)
 	rawID := params["id"]
@@ -61,11 +75,18 @@ func (u *myFoodGet) Serve(data *appctx.Data) appctx.Response {
 		return *response.Failed(ctx, &transactionID, err)
 	}
 
-	if uuidUser != food.IDUser {
+	allowed, errAccess := ucase.CanManageFood(ctx, u.organizationRepositories, uuidUser, food.IDUser, food.IDOrganization)
+	if errAccess != nil {
+		logger.Error(logger.MessageFormat("[food-get] %v", errAccess))
+		err := errorEvent.WrapError(errAccess)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !allowed {
 		logger.Error(logger.MessageFormat("[food-get] id user not match"))
 		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.StatusForbidden))
 		return *response.Failed(ctx, &transactionID, err)
 	}
 
-	return *response.Success(ctx, consts.CodeSuccess, &transactionID, food)
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, food).WithETag(foodETag(food))
 }
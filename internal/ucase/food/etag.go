@@ -0,0 +1,15 @@
+package food
+
+import (
+	"sharefood/internal/entity"
+	"sharefood/pkg/etag"
+)
+
+// foodETag tag of the version of food, none when its update time is unknown
+func foodETag(food entity.Food) string {
+	if food.UpdatedAt.IsZero() {
+		return ""
+	}
+
+	return etag.FromTime(food.UpdatedAt)
+}
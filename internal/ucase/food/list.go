@@ -3,13 +3,13 @@ package food
 import (
 	"sharefood/internal/appctx"
 	"sharefood/internal/consts"
+	"sharefood/internal/entity"
 	"sharefood/internal/repositories"
 	"sharefood/internal/response"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
-
-	"github.com/google/uuid"
 )
 
 type foodList struct {
@@ -22,6 +22,15 @@ func NewFoodList(foodRepositories repositories.Food) contract.UseCase {
 	}
 }
 
+// Doc implements contract.Documented
+func (u *foodList) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "List the shared foods",
+		Result:  []entity.Food{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
 // Serve implements contract.UseCase
 func (u *foodList) Serve(data *appctx.Data) appctx.Response {
 	request := data.Request
@@ -30,7 +39,7 @@ func (u *foodList) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "get_foods")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
 	foods, errList := u.foodRepositories.List(data.Request.Context())
 	if errList != nil {
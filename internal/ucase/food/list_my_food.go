@@ -3,10 +3,12 @@ package food
 import (
 	"sharefood/internal/appctx"
 	"sharefood/internal/consts"
+	"sharefood/internal/entity"
 	"sharefood/internal/repositories"
 	"sharefood/internal/response"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
 
 	"github.com/google/uuid"
@@ -22,6 +24,15 @@ func NewMyFoodList(foodRepositories repositories.Food) contract.UseCase {
 	}
 }
 
+// Doc implements contract.Documented
+func (u *myFoodList) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "List my foods, along with the foods of my organizations",
+		Result:  []entity.Food{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
 // Serve implements contract.UseCase
 func (u *myFoodList) Serve(data *appctx.Data) appctx.Response {
 	request := data.Request
@@ -30,7 +41,7 @@ func (u *myFoodList) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "list_my_foods")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
 	idUser := data.Request.Header.Get("idUser")
 	uuidUser, errFood := uuid.Parse(idUser)
@@ -0,0 +1,12 @@
+package food
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var foodsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "sharefood",
+	Name:      "foods_created_total",
+	Help:      "Number of food listings created, by source user or partner.",
+}, []string{"source"})
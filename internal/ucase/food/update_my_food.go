@@ -1,14 +1,16 @@
 package food
 
 import (
-	"fmt"
 	"sharefood/internal/appctx"
 	"sharefood/internal/consts"
 	"sharefood/internal/entity"
 	"sharefood/internal/repositories"
 	"sharefood/internal/response"
+	"sharefood/internal/ucase"
 	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/etag"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
 
 	"github.com/google/uuid"
@@ -16,12 +18,24 @@ import (
 )
 
 type myFoodUpdate struct {
-	foodRepositories repositories.Food
+	foodRepositories         repositories.Food
+	organizationRepositories repositories.Organization
 }
 
-func NewMyFoodUpdate(foodRepositories repositories.Food) contract.UseCase {
+func NewMyFoodUpdate(foodRepositories repositories.Food, organizationRepositories repositories.Organization) contract.UseCase {
 	return &myFoodUpdate{
-		foodRepositories: foodRepositories,
+		foodRepositories:         foodRepositories,
+		organizationRepositories: organizationRepositories,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *myFoodUpdate) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Update a food of mine",
+		Payload: entity.Food{},
+		Result:  entity.Food{},
+		Code:    consts.CodeSuccess,
 	}
 }
 
@@ -33,7 +47,7 @@ func (u *myFoodUpdate) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "update_my_foods")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
 	// get id food from param
 	params := mux.Vars(data.Request)
@@ -80,17 +94,35 @@ func (u *myFoodUpdate) Serve(data *appctx.Data) appctx.Response {
 		return *response.Failed(ctx, &transactionID, err)
 	}
 
-	if uuidUser != oldFood.IDUser {
+	allowed, errAccess := ucase.CanManageFood(ctx, u.organizationRepositories, uuidUser, oldFood.IDUser, oldFood.IDOrganization)
+	if errAccess != nil {
+		logger.Error(logger.MessageFormat("[food-update] %v", errAccess))
+		err := errorEvent.WrapError(errAccess)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !allowed {
 		logger.Error(logger.MessageFormat("[food-get] id user not match"))
 		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.StatusForbidden))
 		return *response.Failed(ctx, &transactionID, err)
 	}
 
-	fmt.Println(payload)
+	// the food must still be the version the client read
+	ifMatch := request.Header.Get(consts.HeaderIfMatchKey)
+	if ifMatch != "" && !etag.MatchStrong(ifMatch, foodETag(oldFood)) {
+		logger.Error(logger.MessageFormat("[food-update] food changed since it was read"))
+		err := errorEvent.WithCode(consts.CodePreconditionFailed).WrapError(consts.Error(consts.FoodModifiedMessage))
+		return *response.Failed(ctx, &transactionID, err)
+	}
 
-	// do update with payload
+	// do update with payload, unless the food changes meanwhile when it is conditional
 	payload.ID = uuidFood
-	errUpdateFood := u.foodRepositories.Update(ctx, &payload)
+	var errUpdateFood error
+	if ifMatch != "" && ifMatch != etag.Any {
+		errUpdateFood = u.foodRepositories.UpdateIfUnmodified(ctx, &payload, oldFood.UpdatedAt)
+	} else {
+		errUpdateFood = u.foodRepositories.Update(ctx, &payload)
+	}
 	if errUpdateFood != nil {
 		err := errorEvent.WithMessage(consts.UpdateFoodErrorMessage).WrapError(errUpdateFood)
 		tracer.SpanError(ctx, err)
@@ -107,5 +139,5 @@ func (u *myFoodUpdate) Serve(data *appctx.Data) appctx.Response {
 		return *response.Failed(ctx, &transactionID, err)
 	}
 
-	return *response.Success(ctx, consts.CodeSuccess, &transactionID, outputFood)
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, outputFood).WithETag(foodETag(outputFood))
 }
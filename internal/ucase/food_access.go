@@ -0,0 +1,23 @@
+package ucase
+
+import (
+	"context"
+	"sharefood/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// CanManageFood a listing owned by an organization is managed by any of its members,
+// otherwise only by the user who shared it
+func CanManageFood(ctx context.Context, organizationRepository repositories.Organization, idUser uuid.UUID, idOwner uuid.UUID, idOrganization *uuid.UUID) (bool, error) {
+	if idOrganization == nil {
+		return idUser == idOwner, nil
+	}
+
+	role, err := organizationRepository.GetMemberRole(ctx, *idOrganization, idUser)
+	if err != nil {
+		return false, err
+	}
+
+	return role != "", nil
+}
@@ -2,24 +2,25 @@ package ucase
 
 import (
 	"sharefood/internal/entity"
+	"sharefood/pkg/jwtx"
 	"time"
 
 	"github.com/dgrijalva/jwt-go/v4"
 )
 
-func GenerateJWT(user entity.User, secret []byte) (entity.TokenResponse, error) {
-	expiredAt := time.Now().Add(time.Hour * 2).Local()
+// GenerateJWT signs an access token for the user with the signing key of the key set
+func GenerateJWT(user entity.User, keys *jwtx.KeySet) (entity.TokenResponse, error) {
+	issuedAt := time.Now().Local()
+	expiredAt := issuedAt.Add(time.Hour * 2)
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, entity.TokenClaims{
-		ID: user.ID,
+	signedToken, errToken := keys.Sign(entity.TokenClaims{
+		ID:    user.ID,
+		Roles: user.Roles,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: &jwt.Time{
-				Time: expiredAt,
-			},
+			ExpiresAt: jwt.At(expiredAt),
+			IssuedAt:  jwt.At(issuedAt),
 		},
 	})
-
-	signedToken, errToken := token.SignedString(secret)
 	if errToken != nil {
 		return entity.TokenResponse{}, errToken
 	}
@@ -0,0 +1,71 @@
+package maintenance
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type maintenanceDisable struct {
+	maintenanceRepository repositories.Maintenance
+}
+
+// NewMaintenanceDisable turns the maintenance mode off for every instance, the configured mode
+// no longer applies until it is enabled again
+func NewMaintenanceDisable(maintenanceRepository repositories.Maintenance) contract.UseCase {
+	return &maintenanceDisable{
+		maintenanceRepository: maintenanceRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *maintenanceDisable) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Disable the maintenance mode",
+		Result:  entity.Maintenance{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *maintenanceDisable) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("disable_maintenance", request)
+	errorEvent := consts.ErrorEvent("disable_maintenance")
+	ctx := tracer.SpanStart(request.Context(), "disable_maintenance")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	disabledBy, err := uuid.Parse(request.Header.Get(consts.HeaderUserIDKey))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[disable-maintenance] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	now := time.Now()
+	state := entity.Maintenance{
+		Enabled:   false,
+		UpdatedBy: &disabledBy,
+		UpdatedAt: &now,
+	}
+
+	err = u.maintenanceRepository.Save(ctx, state)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[disable-maintenance] %v", err))
+		err := errorEvent.WithMessage(consts.MaintenanceErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, state)
+}
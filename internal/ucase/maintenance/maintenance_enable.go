@@ -0,0 +1,94 @@
+package maintenance
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type maintenanceEnable struct {
+	maintenanceRepository repositories.Maintenance
+}
+
+// NewMaintenanceEnable turns the maintenance mode on for every instance, until it is disabled
+func NewMaintenanceEnable(maintenanceRepository repositories.Maintenance) contract.UseCase {
+	return &maintenanceEnable{
+		maintenanceRepository: maintenanceRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *maintenanceEnable) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Enable the maintenance mode",
+		Payload: entity.MaintenanceInput{},
+		Result:  entity.Maintenance{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *maintenanceEnable) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("enable_maintenance", request)
+	errorEvent := consts.ErrorEvent("enable_maintenance")
+	ctx := tracer.SpanStart(request.Context(), "enable_maintenance")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	// the body is optional
+	payload := entity.MaintenanceInput{}
+	if request.ContentLength != 0 {
+		if err := data.Cast(&payload); err != nil {
+			logger.Error(logger.MessageFormat("[enable-maintenance] parsing body request error: %v", err))
+			err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+			return *response.Failed(ctx, &transactionID, err)
+		}
+	}
+
+	if payload.RetryAfterSecond < 0 {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.MaintenanceRetryAfterNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if payload.RetryAfterSecond == 0 {
+		payload.RetryAfterSecond = data.Config.App.MaintenanceRetryAfterSecond
+	}
+	if payload.RetryAfterSecond < 1 {
+		payload.RetryAfterSecond = consts.MaintenanceRetryAfterSecondDefault
+	}
+
+	enabledBy, err := uuid.Parse(request.Header.Get(consts.HeaderUserIDKey))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[enable-maintenance] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	now := time.Now()
+	state := entity.Maintenance{
+		Enabled:          true,
+		RetryAfterSecond: payload.RetryAfterSecond,
+		UpdatedBy:        &enabledBy,
+		UpdatedAt:        &now,
+	}
+
+	err = u.maintenanceRepository.Save(ctx, state)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[enable-maintenance] %v", err))
+		err := errorEvent.WithMessage(consts.MaintenanceErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, state)
+}
@@ -0,0 +1,53 @@
+package maintenance
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+)
+
+type maintenanceGet struct {
+	maintenanceRepository repositories.Maintenance
+}
+
+// NewMaintenanceGet shows whether the maintenance mode is on
+func NewMaintenanceGet(maintenanceRepository repositories.Maintenance) contract.UseCase {
+	return &maintenanceGet{
+		maintenanceRepository: maintenanceRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *maintenanceGet) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "State of the maintenance mode",
+		Result:  entity.Maintenance{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *maintenanceGet) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("get_maintenance", request)
+	errorEvent := consts.ErrorEvent("get_maintenance")
+	ctx := tracer.SpanStart(request.Context(), "get_maintenance")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	state, err := u.maintenanceRepository.Get(ctx)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[get-maintenance] %v", err))
+		err := errorEvent.WithMessage(consts.MaintenanceErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, state)
+}
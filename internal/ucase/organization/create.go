@@ -0,0 +1,94 @@
+package organization
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/thedevsaddam/govalidator"
+)
+
+type organizationCreate struct {
+	organizationRepository repositories.Organization
+}
+
+func NewOrganizationCreate(organizationRepository repositories.Organization) contract.UseCase {
+	return &organizationCreate{
+		organizationRepository: organizationRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *organizationCreate) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Create an organization",
+		Payload: entity.OrganizationInput{},
+		Result:  entity.Organization{},
+		Code:    consts.CodeCreated,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *organizationCreate) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("create_organization", request)
+	errorEvent := consts.ErrorEvent("create_organization")
+	ctx := tracer.SpanStart(request.Context(), "create_organization")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.OrganizationInput{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-create] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data: &payload,
+		Rules: govalidator.MapData{
+			"name": []string{"required", "between:3,100"},
+			"kind": []string{"required", "in:" + strings.Join(consts.OrganizationKinds, ",")},
+		},
+	})
+	if ev := v.ValidateStruct(); len(ev) != 0 {
+		logger.Warn(logger.MessageFormat("[organization-create] validate request param err: %s", util.DumpToString(ev)))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(util.DumpToString(ev)))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idUser, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-create] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	organization := entity.Organization{
+		ID:   uuid.New(),
+		Name: payload.Name,
+		Kind: payload.Kind,
+	}
+
+	err = u.organizationRepository.Create(ctx, &organization, idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-create] %v", err))
+		err := errorEvent.WithMessage(consts.OrganizationErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	organization.MemberRole = consts.OrganizationMemberOwner
+
+	return *response.Success(ctx, consts.CodeCreated, &transactionID, organization)
+}
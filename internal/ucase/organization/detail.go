@@ -0,0 +1,93 @@
+package organization
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type organizationGet struct {
+	organizationRepository repositories.Organization
+}
+
+func NewOrganizationGet(organizationRepository repositories.Organization) contract.UseCase {
+	return &organizationGet{
+		organizationRepository: organizationRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *organizationGet) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Detail of an organization with its members",
+		Result:  entity.OrganizationDetail{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *organizationGet) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("get_organization", request)
+	errorEvent := consts.ErrorEvent("get_organization")
+	ctx := tracer.SpanStart(request.Context(), "get_organization")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	idOrganization, err := uuid.Parse(mux.Vars(data.Request)["id"])
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-get] parsing id error: %v", err))
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idUser, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-get] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	role, err := u.organizationRepository.GetMemberRole(ctx, idOrganization, idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-get] %v", err))
+		err := errorEvent.WithMessage(consts.OrganizationErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// an organization is only visible to its members
+	if role == "" {
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.OrganizationNotFoundMessage))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	organization, err := u.organizationRepository.GetByID(ctx, idOrganization)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-get] %v", err))
+		err := errorEvent.WithMessage(consts.OrganizationErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+	organization.MemberRole = role
+
+	members, err := u.organizationRepository.ListMembers(ctx, idOrganization)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-get] %v", err))
+		err := errorEvent.WithMessage(consts.OrganizationErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, entity.OrganizationDetail{
+		Organization: organization,
+		Members:      members,
+	})
+}
@@ -0,0 +1,61 @@
+package organization
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+)
+
+type organizationList struct {
+	organizationRepository repositories.Organization
+}
+
+func NewOrganizationList(organizationRepository repositories.Organization) contract.UseCase {
+	return &organizationList{
+		organizationRepository: organizationRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *organizationList) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "List my organizations",
+		Result:  []entity.Organization{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *organizationList) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("list_organizations", request)
+	errorEvent := consts.ErrorEvent("list_organizations")
+	ctx := tracer.SpanStart(request.Context(), "list_organizations")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	idUser, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-list] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	organizations, err := u.organizationRepository.ListByUser(ctx, idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-list] %v", err))
+		err := errorEvent.WithMessage(consts.OrganizationErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, organizations)
+}
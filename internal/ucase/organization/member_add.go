@@ -0,0 +1,124 @@
+package organization
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/thedevsaddam/govalidator"
+)
+
+type organizationMemberAdd struct {
+	organizationRepository repositories.Organization
+	userRepository         repositories.User
+}
+
+func NewOrganizationMemberAdd(organizationRepository repositories.Organization, userRepository repositories.User) contract.UseCase {
+	return &organizationMemberAdd{
+		organizationRepository: organizationRepository,
+		userRepository:         userRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *organizationMemberAdd) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Add a member to an organization",
+		Payload: entity.OrganizationMemberInput{},
+		Result:  entity.OrganizationMember{},
+		Code:    consts.CodeCreated,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *organizationMemberAdd) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("add_organization_member", request)
+	errorEvent := consts.ErrorEvent("add_organization_member")
+	ctx := tracer.SpanStart(request.Context(), "add_organization_member")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	idOrganization, err := uuid.Parse(mux.Vars(data.Request)["id"])
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-member-add] parsing id error: %v", err))
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	payload := entity.OrganizationMemberInput{}
+	err = data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-member-add] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data: &payload,
+		Rules: govalidator.MapData{
+			"email": []string{"required", "email"},
+			"role":  []string{"required", "in:" + consts.OrganizationMemberOwner + "," + consts.OrganizationMemberStaff},
+		},
+	})
+	if ev := v.ValidateStruct(); len(ev) != 0 {
+		logger.Warn(logger.MessageFormat("[organization-member-add] validate request param err: %s", util.DumpToString(ev)))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(util.DumpToString(ev)))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idUser, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-member-add] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if err := ensureOwner(ctx, u.organizationRepository, errorEvent, idOrganization, idUser); err != nil {
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	member, err := u.userRepository.GetByEmail(ctx, payload.Email)
+	if err != nil {
+		logger.Warn(logger.MessageFormat("[organization-member-add] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.UserNotFoundMessage))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	role, err := u.organizationRepository.GetMemberRole(ctx, idOrganization, member.ID)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-member-add] %v", err))
+		err := errorEvent.WithMessage(consts.OrganizationErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if role != "" {
+		err := errorEvent.WithCode(consts.CodeDuplicateEntry).WrapError(consts.Error(consts.OrganizationMemberExists))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.organizationRepository.AddMember(ctx, idOrganization, member.ID, payload.Role)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-member-add] %v", err))
+		err := errorEvent.WithMessage(consts.OrganizationErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeCreated, &transactionID, entity.OrganizationMember{
+		IDOrganization: idOrganization,
+		IDUser:         member.ID,
+		Name:           member.Name,
+		Email:          member.Email,
+		Role:           payload.Role,
+	})
+}
@@ -0,0 +1,94 @@
+package organization
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type organizationMemberRemove struct {
+	organizationRepository repositories.Organization
+}
+
+func NewOrganizationMemberRemove(organizationRepository repositories.Organization) contract.UseCase {
+	return &organizationMemberRemove{
+		organizationRepository: organizationRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *organizationMemberRemove) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Remove a member from an organization",
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *organizationMemberRemove) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("remove_organization_member", request)
+	errorEvent := consts.ErrorEvent("remove_organization_member")
+	ctx := tracer.SpanStart(request.Context(), "remove_organization_member")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	params := mux.Vars(data.Request)
+	idOrganization, err := uuid.Parse(params["id"])
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-member-remove] parsing id error: %v", err))
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idMember, err := uuid.Parse(params["id_user"])
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-member-remove] parsing id error: %v", err))
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idUser, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-member-remove] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// members may leave on their own, everyone else is removed by an owner
+	if idMember != idUser {
+		if err := ensureOwner(ctx, u.organizationRepository, errorEvent, idOrganization, idUser); err != nil {
+			return *response.Failed(ctx, &transactionID, err)
+		}
+	}
+
+	role, err := u.organizationRepository.GetMemberRole(ctx, idOrganization, idMember)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-member-remove] %v", err))
+		err := errorEvent.WithMessage(consts.OrganizationErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if role == "" {
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.OrganizationMemberNotFound))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.organizationRepository.RemoveMember(ctx, idOrganization, idMember)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[organization-member-remove] %v", err))
+		err := errorEvent.WithMessage(consts.OrganizationErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
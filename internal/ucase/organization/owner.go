@@ -0,0 +1,27 @@
+package organization
+
+import (
+	"context"
+	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ensureOwner fails unless the user is an owner of the organization,
+// non-members get not found so the organization stays hidden from them
+func ensureOwner(ctx context.Context, organizationRepository repositories.Organization, errorEvent *consts.WrappedError, idOrganization uuid.UUID, idUser uuid.UUID) error {
+	role, err := organizationRepository.GetMemberRole(ctx, idOrganization, idUser)
+	if err != nil {
+		return errorEvent.WithMessage(consts.OrganizationErrorMessage).WrapError(err)
+	}
+
+	switch role {
+	case consts.OrganizationMemberOwner:
+		return nil
+	case "":
+		return errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.OrganizationNotFoundMessage))
+	default:
+		return errorEvent.WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.StatusForbidden))
+	}
+}
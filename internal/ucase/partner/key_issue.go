@@ -0,0 +1,139 @@
+package partner
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type keyIssue struct {
+	partnerAPIKeyRepository repositories.PartnerAPIKey
+	organizationRepository  repositories.Organization
+}
+
+// NewKeyIssue issues an api key for a partner system of an organization, the secret is only shown in the response
+func NewKeyIssue(partnerAPIKeyRepository repositories.PartnerAPIKey, organizationRepository repositories.Organization) contract.UseCase {
+	return &keyIssue{
+		partnerAPIKeyRepository: partnerAPIKeyRepository,
+		organizationRepository:  organizationRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *keyIssue) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Issue a partner API key, the secret is only shown once",
+		Payload: entity.PartnerAPIKeyInput{},
+		Result:  entity.PartnerAPIKeySecret{},
+		Code:    consts.CodeCreated,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *keyIssue) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("issue_partner_api_key", request)
+	errorEvent := consts.ErrorEvent("issue_partner_api_key")
+	ctx := tracer.SpanStart(request.Context(), "issue_partner_api_key")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.PartnerAPIKeyInput{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[issue-partner-api-key] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if payload.Name == "" || len(payload.Scopes) == 0 || !validScopes(payload.Scopes) {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.PartnerScopeNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	createdBy, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[issue-partner-api-key] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	_, err = u.organizationRepository.GetByID(ctx, payload.IDOrganization)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[issue-partner-api-key] %v", err))
+		err := errorEvent.WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// the foods shared with the key are owned by this member
+	role, err := u.organizationRepository.GetMemberRole(ctx, payload.IDOrganization, payload.IDUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[issue-partner-api-key] %v", err))
+		err := errorEvent.WithMessage(consts.PartnerKeyErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if role == "" {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.PartnerUserNotMember))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	secret, err := util.GenerateSecureToken(consts.PartnerKeySecretBytes)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[issue-partner-api-key] %v", err))
+		err := errorEvent.WithMessage(consts.PartnerKeyErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	key := entity.PartnerAPIKey{
+		ID:             uuid.New(),
+		Name:           payload.Name,
+		IDOrganization: payload.IDOrganization,
+		IDUser:         payload.IDUser,
+		Scopes:         payload.Scopes,
+		Secret:         secret,
+		CreatedBy:      createdBy,
+		CreatedAt:      time.Now(),
+	}
+
+	err = u.partnerAPIKeyRepository.Create(ctx, key)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[issue-partner-api-key] %v", err))
+		err := errorEvent.WithMessage(consts.PartnerKeyErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeCreated, &transactionID, entity.PartnerAPIKeySecret{
+		PartnerAPIKey: key,
+		Secret:        secret,
+	}).WithSecret()
+}
+
+func validScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		valid := false
+		for _, s := range consts.PartnerScopes {
+			if s == scope {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			return false
+		}
+	}
+
+	return true
+}
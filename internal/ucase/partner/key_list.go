@@ -0,0 +1,53 @@
+package partner
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+)
+
+type keyList struct {
+	partnerAPIKeyRepository repositories.PartnerAPIKey
+}
+
+// NewKeyList lists the partner api keys, without their secrets
+func NewKeyList(partnerAPIKeyRepository repositories.PartnerAPIKey) contract.UseCase {
+	return &keyList{
+		partnerAPIKeyRepository: partnerAPIKeyRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *keyList) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "List the partner API keys",
+		Result:  []entity.PartnerAPIKey{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *keyList) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("list_partner_api_keys", request)
+	errorEvent := consts.ErrorEvent("list_partner_api_keys")
+	ctx := tracer.SpanStart(request.Context(), "list_partner_api_keys")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	keys, err := u.partnerAPIKeyRepository.List(ctx)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[list-partner-api-keys] %v", err))
+		err := errorEvent.WithMessage(consts.PartnerKeyErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, keys)
+}
@@ -0,0 +1,74 @@
+package partner
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type keyRevoke struct {
+	partnerAPIKeyRepository repositories.PartnerAPIKey
+}
+
+// NewKeyRevoke revokes the key in the path, its current and previous secret stop working right away
+func NewKeyRevoke(partnerAPIKeyRepository repositories.PartnerAPIKey) contract.UseCase {
+	return &keyRevoke{
+		partnerAPIKeyRepository: partnerAPIKeyRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *keyRevoke) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Revoke a partner API key",
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *keyRevoke) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("revoke_partner_api_key", request)
+	errorEvent := consts.ErrorEvent("revoke_partner_api_key")
+	ctx := tracer.SpanStart(request.Context(), "revoke_partner_api_key")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	id, err := uuid.Parse(mux.Vars(data.Request)["id"])
+	if err != nil {
+		logger.Error(logger.MessageFormat("[revoke-partner-api-key] parsing id error: %v", err))
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	key, err := u.partnerAPIKeyRepository.GetByID(ctx, id)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[revoke-partner-api-key] %v", err))
+		err := errorEvent.WithMessage(consts.PartnerKeyErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if key.ID == uuid.Nil {
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.PartnerKeyNotFoundMessage))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.partnerAPIKeyRepository.Revoke(ctx, key.ID, time.Now())
+	if err != nil {
+		logger.Error(logger.MessageFormat("[revoke-partner-api-key] %v", err))
+		err := errorEvent.WithMessage(consts.PartnerKeyErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
@@ -0,0 +1,98 @@
+package partner
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type keyRotate struct {
+	partnerAPIKeyRepository repositories.PartnerAPIKey
+}
+
+// NewKeyRotate gives the key in the path a new secret, the old one keeps working for a grace period
+// so the partner can roll the new secret out without downtime
+func NewKeyRotate(partnerAPIKeyRepository repositories.PartnerAPIKey) contract.UseCase {
+	return &keyRotate{
+		partnerAPIKeyRepository: partnerAPIKeyRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *keyRotate) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Rotate the secret of a partner API key",
+		Result:  entity.PartnerAPIKeySecret{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *keyRotate) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("rotate_partner_api_key", request)
+	errorEvent := consts.ErrorEvent("rotate_partner_api_key")
+	ctx := tracer.SpanStart(request.Context(), "rotate_partner_api_key")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	id, err := uuid.Parse(mux.Vars(data.Request)["id"])
+	if err != nil {
+		logger.Error(logger.MessageFormat("[rotate-partner-api-key] parsing id error: %v", err))
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	key, err := u.partnerAPIKeyRepository.GetByID(ctx, id)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[rotate-partner-api-key] %v", err))
+		err := errorEvent.WithMessage(consts.PartnerKeyErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if key.ID == uuid.Nil || key.IsRevoked() {
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.PartnerKeyNotFoundMessage))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	grace := data.Config.Auth.PartnerKeyRotationGraceHour
+	if grace < 1 {
+		grace = consts.PartnerKeyRotationGraceHourDefault
+	}
+
+	secret, err := util.GenerateSecureToken(consts.PartnerKeySecretBytes)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[rotate-partner-api-key] %v", err))
+		err := errorEvent.WithMessage(consts.PartnerKeyErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	rotatedAt := time.Now()
+	previousExpiresAt := rotatedAt.Add(time.Duration(grace) * time.Hour)
+	err = u.partnerAPIKeyRepository.Rotate(ctx, key.ID, secret, rotatedAt, previousExpiresAt)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[rotate-partner-api-key] %v", err))
+		err := errorEvent.WithMessage(consts.PartnerKeyErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	key.RotatedAt = &rotatedAt
+	key.PreviousSecretExpiresAt = &previousExpiresAt
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, entity.PartnerAPIKeySecret{
+		PartnerAPIKey: key,
+		Secret:        secret,
+	}).WithSecret()
+}
@@ -0,0 +1,36 @@
+package ucase
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/health"
+	"sharefood/pkg/logger"
+	"time"
+)
+
+type readinessCheck struct {
+	timeout time.Duration
+	checks  []health.Check
+}
+
+// NewReadinessCheck reports the status and latency of each dependency, the service is not ready
+// while a critical one is down
+func NewReadinessCheck(timeout time.Duration, checks ...health.Check) contract.UseCase {
+	return &readinessCheck{timeout: timeout, checks: checks}
+}
+
+func (u *readinessCheck) Serve(data *appctx.Data) appctx.Response {
+	report := health.Run(data.Request.Context(), u.timeout, u.checks...)
+	for _, res := range report.Dependencies {
+		if res.Status == health.StatusDown {
+			logger.Warn(logger.MessageFormat("[readiness] %s down: %s", res.Name, res.Error))
+		}
+	}
+
+	if !report.Ready() {
+		return *appctx.NewResponse().WithCode(consts.CodeServerBusy).WithMessage("not ready").WithData(report)
+	}
+
+	return *appctx.NewResponse().WithCode(consts.CodeSuccess).WithMessage("ok").WithData(report)
+}
@@ -0,0 +1,47 @@
+// Package ucase
+package ucase
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/pkg/health"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadinessCheck_Serve(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	t.Run("test ready", func(t *testing.T) {
+		svc := NewReadinessCheck(time.Second,
+			health.Check{Name: consts.ReadinessDBWrite, Critical: true, Check: up},
+			health.Check{Name: consts.ReadinessKafka, Check: down},
+		)
+
+		result := svc.Serve(&appctx.Data{Request: httptest.NewRequest("GET", "/readiness", nil)})
+
+		assert.Equal(t, consts.CodeSuccess, result.Code)
+		assert.True(t, result.Data.(health.Report).Ready())
+	})
+
+	t.Run("test critical dependency down", func(t *testing.T) {
+		svc := NewReadinessCheck(time.Second,
+			health.Check{Name: consts.ReadinessDBWrite, Critical: true, Check: down},
+		)
+
+		result := svc.Serve(&appctx.Data{Request: httptest.NewRequest("GET", "/readiness", nil)})
+
+		assert.Equal(t, consts.CodeServerBusy, result.Code)
+		assert.Equal(t, health.StatusDown, result.Data.(health.Report).Dependencies[0].Status)
+
+		// the error is logged, the public answer only tells the status
+		assert.NotContains(t, string(result.Byte()), "connection refused")
+	})
+}
@@ -6,22 +6,36 @@ import (
 	"sharefood/internal/entity"
 	"sharefood/internal/repositories"
 	"sharefood/internal/response"
+	"sharefood/internal/ucase"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
 
 	"github.com/google/uuid"
 )
 
 type requestAction struct {
-	requestRepository repositories.Request
-	foodRepository    repositories.Food
+	requestRepository      repositories.Request
+	foodRepository         repositories.Food
+	organizationRepository repositories.Organization
 }
 
-func NewRequestAction(requestRepository repositories.Request, foodRepository repositories.Food) contract.UseCase {
+func NewRequestAction(requestRepository repositories.Request, foodRepository repositories.Food, organizationRepository repositories.Organization) contract.UseCase {
 	return &requestAction{
-		requestRepository: requestRepository,
-		foodRepository:    foodRepository,
+		requestRepository:      requestRepository,
+		foodRepository:         foodRepository,
+		organizationRepository: organizationRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *requestAction) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Accept or reject a request for a food of mine",
+		Payload: entity.RequestAction{},
+		Result:  entity.RequestWithFood{},
+		Code:    consts.CodeSuccess,
 	}
 }
 
@@ -33,7 +47,7 @@ func (u *requestAction) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "request_action")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
 	payload := entity.RequestAction{}
 
@@ -60,8 +74,15 @@ func (u *requestAction) Serve(data *appctx.Data) appctx.Response {
 		return *response.Failed(ctx, &transactionID, err)
 	}
 
-	// kalo food-id usernya sama kaya user header berarti boleh action (cuma user yang punya yang boleh action)
-	if reqFood.IDUserFood != uuidUser {
+	// only who manages the food may act: the giver, or any member of the organization owning it
+	allowed, err := ucase.CanManageFood(ctx, u.organizationRepository, uuidUser, reqFood.IDUserFood, reqFood.IDOrgFood)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[request-action] %v", err))
+		err := errorEvent.WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !allowed {
 		logger.Error(logger.MessageFormat("[request-action] id user not match"))
 		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.StatusForbidden))
 		return *response.Failed(ctx, &transactionID, err)
@@ -75,6 +96,7 @@ func (u *requestAction) Serve(data *appctx.Data) appctx.Response {
 			err := errorEvent.WithMessage(consts.ActionRequestNotValid).WithCode(consts.CodeUnprocessableEntity).WrapError(err)
 			return *response.Failed(ctx, &transactionID, err)
 		}
+		requestsActioned.WithLabelValues(payload.Action).Inc()
 		return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
 
 	} else if payload.Action == "accept" {
@@ -92,6 +114,7 @@ func (u *requestAction) Serve(data *appctx.Data) appctx.Response {
 			err := errorEvent.WithMessage(consts.ActionRequestNotValid).WithCode(consts.CodeUnprocessableEntity).WrapError(err)
 			return *response.Failed(ctx, &transactionID, err)
 		}
+		requestsActioned.WithLabelValues(payload.Action).Inc()
 		return *response.Success(ctx, consts.CodeSuccess, &transactionID, reqFood)
 
 	} else {
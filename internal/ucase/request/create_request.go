@@ -8,6 +8,7 @@ import (
 	"sharefood/internal/response"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
 
 	"github.com/google/uuid"
@@ -26,6 +27,15 @@ func NewRequestFoodCreate(requestRepository repositories.Request, foodRepository
 	}
 }
 
+// Doc implements contract.Documented
+func (u *requestCreate) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Request a food",
+		Payload: entity.Request{},
+		Code:    consts.CodeCreated,
+	}
+}
+
 // Serve implements contract.UseCase
 func (u *requestCreate) Serve(data *appctx.Data) appctx.Response {
 	request := data.Request
@@ -34,7 +44,7 @@ func (u *requestCreate) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "create_request")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
 	payload := entity.Request{}
 
@@ -97,5 +107,7 @@ func (u *requestCreate) Serve(data *appctx.Data) appctx.Response {
 		return *response.Failed(ctx, &transactionID, err)
 	}
 
+	requestsCreated.Inc()
+
 	return *response.Success(ctx, consts.CodeCreated, &transactionID, nil)
 }
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"sharefood/internal/appctx"
 	"sharefood/internal/consts"
+	"sharefood/internal/entity"
 	"sharefood/internal/repositories"
 	"sharefood/internal/response"
+	"sharefood/internal/ucase"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
 
 	"github.com/google/uuid"
@@ -15,12 +18,25 @@ import (
 )
 
 type requestFoodList struct {
-	requestRepository repositories.Request
+	requestRepository      repositories.Request
+	foodRepository         repositories.Food
+	organizationRepository repositories.Organization
 }
 
-func NewRequestFoodList(requestRepository repositories.Request) contract.UseCase {
+func NewRequestFoodList(requestRepository repositories.Request, foodRepository repositories.Food, organizationRepository repositories.Organization) contract.UseCase {
 	return &requestFoodList{
-		requestRepository: requestRepository,
+		requestRepository:      requestRepository,
+		foodRepository:         foodRepository,
+		organizationRepository: organizationRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *requestFoodList) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "List the requests for a food of mine",
+		Result:  []entity.Request{},
+		Code:    consts.CodeSuccess,
 	}
 }
 
@@ -32,15 +48,15 @@ func (u *requestFoodList) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "list_requests_food")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
-	// idUser := data.Request.Header.Get("idUser")
-	// uuidUser, errFood := uuid.Parse(idUser)
-	// if errFood != nil {
-	// 	logger.Error(logger.MessageFormat("[request-get] parsing id error: %v", errFood))
-	// 	err := errorEvent.WithMessage(consts.CreateFoodErrorMessage).WithCode(consts.CodeUnprocessableEntity).WrapError(errFood)
-	// 	return *response.Failed(ctx, &transactionID, err)
-	// }
+	idUser := data.Request.Header.Get("idUser")
+	uuidUser, errUser := uuid.Parse(idUser)
+	if errUser != nil {
+		logger.Error(logger.MessageFormat("[list_requests_food] parsing id error: %v", errUser))
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeUnprocessableEntity).WrapError(errUser)
+		return *response.Failed(ctx, &transactionID, err)
+	}
 
 	params := mux.Vars(data.Request)
 	rawID := params["id"]
@@ -52,6 +68,25 @@ func (u *requestFoodList) Serve(data *appctx.Data) appctx.Response {
 		return *response.Failed(ctx, &transactionID, err)
 	}
 
+	food, errFood := u.foodRepository.GetDetailByID(ctx, idFood)
+	if errFood != nil {
+		logger.Error(logger.MessageFormat("[list_requests_food] food not found: %v", errFood))
+		err := errorEvent.WithMessage(consts.FoodNotFoundMessage).WithCode(consts.CodeNotFound).WrapError(errFood)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	allowed, errAccess := ucase.CanManageFood(ctx, u.organizationRepository, uuidUser, food.IDUser, food.IDOrganization)
+	if errAccess != nil {
+		logger.Error(logger.MessageFormat("[list_requests_food] %v", errAccess))
+		err := errorEvent.WrapError(errAccess)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !allowed {
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.StatusForbidden))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
 	requests, err := u.requestRepository.ListbyFood(ctx, idFood)
 	if err != nil {
 		logger.Error("Error get list of request")
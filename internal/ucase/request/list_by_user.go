@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"sharefood/internal/appctx"
 	"sharefood/internal/consts"
+	"sharefood/internal/entity"
 	"sharefood/internal/repositories"
 	"sharefood/internal/response"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/tracer"
 
 	"github.com/google/uuid"
@@ -23,6 +25,15 @@ func NewRequestUserList(requestRepository repositories.Request) contract.UseCase
 	}
 }
 
+// Doc implements contract.Documented
+func (u *requestUserList) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "List my requests",
+		Result:  []entity.Request{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
 // Serve implements contract.UseCase
 func (u *requestUserList) Serve(data *appctx.Data) appctx.Response {
 	request := data.Request
@@ -31,7 +42,7 @@ func (u *requestUserList) Serve(data *appctx.Data) appctx.Response {
 	ctx := tracer.SpanStart(request.Context(), "list_requests_user")
 	defer tracer.SpanFinish(ctx)
 
-	transactionID := uuid.New()
+	transactionID := requestid.Get(ctx)
 
 	idUser := data.Request.Header.Get("idUser")
 	fmt.Println(idUser)
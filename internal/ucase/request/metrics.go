@@ -0,0 +1,20 @@
+package request
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	requestsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "sharefood",
+		Name:      "food_requests_created_total",
+		Help:      "Number of food requests created.",
+	})
+
+	requestsActioned = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sharefood",
+		Name:      "food_requests_actioned_total",
+		Help:      "Number of food requests accepted or rejected, by action.",
+	}, []string{"action"})
+)
@@ -0,0 +1,86 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/mailer"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+)
+
+type emailVerificationResend struct {
+	userRepository                 repositories.User
+	verificationThrottleRepository repositories.VerificationThrottle
+	mailer                         mailer.Mailer
+	keys                           *jwtx.KeySet
+}
+
+func NewEmailVerificationResend(userRepository repositories.User, verificationThrottleRepository repositories.VerificationThrottle, mailer mailer.Mailer, keys *jwtx.KeySet) contract.UseCase {
+	return &emailVerificationResend{
+		userRepository:                 userRepository,
+		verificationThrottleRepository: verificationThrottleRepository,
+		mailer:                         mailer,
+		keys:                           keys,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *emailVerificationResend) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Resend the email verification link",
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *emailVerificationResend) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("resend_email_verification", request)
+	errorEvent := consts.ErrorEvent("resend_email_verification")
+	ctx := tracer.SpanStart(request.Context(), "resend_email_verification")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	idUser := data.Request.Header.Get("idUser")
+	uuidUser, err := uuid.Parse(idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[resend-email-verification] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[resend-email-verification] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if user.EmailVerifiedAt != nil {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.AlreadyVerified))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = newVerificationGuard(data.Config, u.verificationThrottleRepository).send(ctx, consts.VerificationChannelEmail, user.ID, user.Email)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeReachMaxLimit).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = sendEmailVerification(ctx, data.Config, u.keys, u.mailer, user)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[resend-email-verification] send mail error: %v", err))
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
@@ -0,0 +1,70 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+)
+
+type emailVerify struct {
+	userRepository repositories.User
+	keys           *jwtx.KeySet
+}
+
+func NewEmailVerify(userRepository repositories.User, keys *jwtx.KeySet) contract.UseCase {
+	return &emailVerify{
+		userRepository: userRepository,
+		keys:           keys,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *emailVerify) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Verify the email address",
+		Payload: entity.EmailVerificationInput{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *emailVerify) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("verify_email", request)
+	errorEvent := consts.ErrorEvent("verify_email")
+	ctx := tracer.SpanStart(request.Context(), "verify_email")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.EmailVerificationInput{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[verify-email] parsing request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	claims, err := parseEmailVerification(u.keys, payload.Token)
+	if err != nil {
+		logger.Warn(logger.MessageFormat("[verify-email] %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.userRepository.MarkEmailVerified(ctx, claims.ID, claims.Email)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[verify-email] %v", err))
+		err := errorEvent.WithMessage(consts.VerifyAccountErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
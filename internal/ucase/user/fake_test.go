@@ -0,0 +1,229 @@
+// Package user
+package user
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/mailer"
+	"sharefood/pkg/postgres"
+
+	"github.com/google/uuid"
+)
+
+// fakeUserRepository serves the users it holds, the methods a test does not stub panic
+type fakeUserRepository struct {
+	repositories.User
+	users map[uuid.UUID]entity.User
+}
+
+func (r *fakeUserRepository) GetByID(_ context.Context, id uuid.UUID) (entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return entity.User{}, fmt.Errorf("scanning user %w", sql.ErrNoRows)
+	}
+
+	return user, nil
+}
+
+func (r *fakeUserRepository) GetByEmail(_ context.Context, email string) (entity.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return entity.User{}, fmt.Errorf("scanning user %w", sql.ErrNoRows)
+}
+
+func (r *fakeUserRepository) IsRegistered(ctx context.Context, email string) bool {
+	_, err := r.GetByEmail(ctx, email)
+	return err == nil
+}
+
+func (r *fakeUserRepository) SetSuspended(_ context.Context, id uuid.UUID, suspendedAt *time.Time) error {
+	user := r.users[id]
+	user.SuspendedAt = suspendedAt
+	r.users[id] = user
+
+	return nil
+}
+
+func (r *fakeUserRepository) Delete(_ context.Context, id uuid.UUID, _ time.Time) error {
+	delete(r.users, id)
+	return nil
+}
+
+// fakeOrganizationRepository the user is a member of no organization
+type fakeOrganizationRepository struct {
+	repositories.Organization
+}
+
+func (r *fakeOrganizationRepository) ListByUser(context.Context, uuid.UUID) ([]entity.Organization, error) {
+	return nil, nil
+}
+
+// fakePasswordResetRepository records the resets created
+type fakePasswordResetRepository struct {
+	repositories.PasswordReset
+	created []entity.PasswordReset
+}
+
+func (r *fakePasswordResetRepository) Create(_ context.Context, reset *entity.PasswordReset) error {
+	r.created = append(r.created, *reset)
+	return nil
+}
+
+// fakeOIDCSessionRepository keeps the sessions by state
+type fakeOIDCSessionRepository struct {
+	sessions map[string]entity.OIDCSession
+}
+
+func (r *fakeOIDCSessionRepository) Save(_ context.Context, state string, session entity.OIDCSession, _ time.Duration) error {
+	r.sessions[state] = session
+	return nil
+}
+
+func (r *fakeOIDCSessionRepository) Take(_ context.Context, state string) (entity.OIDCSession, error) {
+	session, ok := r.sessions[state]
+	if !ok {
+		return session, consts.Error(consts.OIDCStateNotValid)
+	}
+	delete(r.sessions, state)
+
+	return session, nil
+}
+
+// fakeUserIdentityRepository keeps the linked identities
+type fakeUserIdentityRepository struct {
+	identities []entity.UserIdentity
+}
+
+func (r *fakeUserIdentityRepository) GetUserID(_ context.Context, provider string, subject string) (uuid.UUID, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity.IDUser, nil
+		}
+	}
+
+	return uuid.Nil, nil
+}
+
+func (r *fakeUserIdentityRepository) Create(_ context.Context, identity entity.UserIdentity) error {
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+// fakeTwoFactorRepository keeps the secrets, codes and challenges of the accounts in memory
+type fakeTwoFactorRepository struct {
+	repositories.TwoFactor
+	secrets       map[uuid.UUID]string
+	recoveryCodes map[string]bool
+	usedCodes     map[string]bool
+	attempts      map[string]int64
+	completed     map[string]bool
+	disabled      []uuid.UUID
+}
+
+func newFakeTwoFactorRepository() *fakeTwoFactorRepository {
+	return &fakeTwoFactorRepository{
+		secrets:       map[uuid.UUID]string{},
+		recoveryCodes: map[string]bool{},
+		usedCodes:     map[string]bool{},
+		attempts:      map[string]int64{},
+		completed:     map[string]bool{},
+	}
+}
+
+func (r *fakeTwoFactorRepository) GetSecret(_ context.Context, idUser uuid.UUID) (string, error) {
+	return r.secrets[idUser], nil
+}
+
+func (r *fakeTwoFactorRepository) Disable(_ context.Context, idUser uuid.UUID) error {
+	r.disabled = append(r.disabled, idUser)
+	return nil
+}
+
+func (r *fakeTwoFactorRepository) UseRecoveryCode(_ context.Context, _ uuid.UUID, codeHash string, _ time.Time) (bool, error) {
+	ok := r.recoveryCodes[codeHash]
+	delete(r.recoveryCodes, codeHash)
+
+	return ok, nil
+}
+
+func (r *fakeTwoFactorRepository) UseCode(_ context.Context, idUser uuid.UUID, step int64, _ time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s:%d", idUser, step)
+	if r.usedCodes[key] {
+		return false, nil
+	}
+	r.usedCodes[key] = true
+
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepository) Attempt(_ context.Context, challengeID string, _ time.Duration) (int64, error) {
+	r.attempts[challengeID]++
+	return r.attempts[challengeID], nil
+}
+
+func (r *fakeTwoFactorRepository) Complete(_ context.Context, challengeID string, _ time.Duration) (bool, error) {
+	if r.completed[challengeID] {
+		return false, nil
+	}
+	r.completed[challengeID] = true
+
+	return true, nil
+}
+
+// fakeMailer records the messages sent, or fails with err
+type fakeMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (m *fakeMailer) Send(_ context.Context, msg mailer.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// fakeConn records the statements executed
+type fakeConn struct {
+	postgres.Adapter
+	execs [][]interface{}
+}
+
+func (c *fakeConn) Exec(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+	c.execs = append(c.execs, args)
+	return driver.RowsAffected(1), nil
+}
+
+// fakePhoneVerificationRepository records the codes created
+type fakePhoneVerificationRepository struct {
+	repositories.PhoneVerification
+	created []entity.PhoneVerification
+}
+
+func (r *fakePhoneVerificationRepository) Create(_ context.Context, verification *entity.PhoneVerification) error {
+	r.created = append(r.created, *verification)
+	return nil
+}
+
+// fakeSender records the text messages sent
+type fakeSender struct {
+	sent []string
+}
+
+func (s *fakeSender) Send(_ context.Context, phoneNumber, _ string) error {
+	s.sent = append(s.sent, phoneNumber)
+	return nil
+}
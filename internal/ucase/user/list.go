@@ -2,10 +2,16 @@ package user
 
 import (
 	"sharefood/internal/appctx"
+	"sharefood/internal/common"
 	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/presentations"
 	"sharefood/internal/repositories"
+	"sharefood/internal/response"
 	"sharefood/internal/ucase/contract"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
 )
 
 type userList struct {
@@ -18,14 +24,50 @@ func NewUserList(userRepository repositories.User) contract.UseCase {
 	}
 }
 
+// Doc implements contract.Documented
+func (u *userList) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "List the users",
+		Payload: entity.UserFilter{},
+		Result:  []presentations.AdminUser{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
 // Serve implements contract.UseCase
 func (u *userList) Serve(data *appctx.Data) appctx.Response {
-	users, err := u.userRepository.List(data.Request.Context())
+	request := data.Request
+	response := response.NewResponse("list_users", request)
+	errorEvent := consts.ErrorEvent("list_users")
+	ctx := tracer.SpanStart(request.Context(), "list_users")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	filter := entity.UserFilter{}
+	err := data.Cast(&filter)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[user-list] parsing query string error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	filter.Limit = common.LimitDefaultValue(filter.Limit)
+	filter.Page = common.PageDefaultValue(filter.Page)
+
+	users, total, err := u.userRepository.List(ctx, filter)
 	if err != nil {
-		logger.Error("Error get list of users")
-		logger.Error(err)
-		return *appctx.NewResponse().WithMessage("Ini messagenya").WithCode(consts.CodeInternalServerError)
+		logger.Error(logger.MessageFormat("[user-list] %v", err))
+		err := errorEvent.WithMessage(consts.ListUserErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
 	}
 
-	return *appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(users)
+	return *response.SuccessWithMetadata(ctx, consts.CodeSuccess, &entity.Metadata{
+		TransactionID: &transactionID,
+		PerPage:       int(filter.Limit),
+		Page:          int(filter.Page),
+		Total:         int(total),
+		OrderBy:       "name",
+		OrderType:     "asc",
+	}, presentations.NewAdminUsers(users))
 }
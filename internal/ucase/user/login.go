@@ -7,18 +7,37 @@ import (
 	"sharefood/internal/repositories"
 	"sharefood/internal/ucase"
 	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/jwtx"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/util"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type userLogin struct {
-	userRepository repositories.User
+	userRepository         repositories.User
+	loginAttemptRepository repositories.LoginAttempt
+	keys                   *jwtx.KeySet
 }
 
-func NewUserLogin(userRepository repositories.User) contract.UseCase {
+func NewUserLogin(userRepository repositories.User, loginAttemptRepository repositories.LoginAttempt, keys *jwtx.KeySet) contract.UseCase {
 	return &userLogin{
-		userRepository: userRepository,
+		userRepository:         userRepository,
+		loginAttemptRepository: loginAttemptRepository,
+		keys:                   keys,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *userLogin) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Sign in, answers a two-factor challenge instead when the account has two-factor enabled",
+		Payload: entity.UserLogin{},
+		Result:  entity.TokenResponse{},
+		// the data of the accounts with two-factor enabled
+		Alternatives: []interface{}{entity.TwoFactorChallenge{}},
+		Code:         consts.CodeSuccess,
 	}
 }
 
@@ -32,25 +51,53 @@ func (u *userLogin) Serve(data *appctx.Data) appctx.Response {
 		return *appctx.NewResponse().WithCode(consts.CodeBadRequest).WithError(err.Error()).WithStatus(consts.StatusFailed).WithEntity("login").WithState("loginFailed")
 	}
 
-	// Check is account available
-	userAccount, err := u.userRepository.GetByEmail(data.Request.Context(), payload.Email)
+	ctx := data.Request.Context()
+	guard := newLoginGuard(data.Config, u.loginAttemptRepository)
+	email := strings.ToLower(strings.TrimSpace(payload.Email))
+	ip := util.ClientIP(data.Request)
+
+	if guard.locked(ctx, email, ip) {
+		return *appctx.NewResponse().WithCode(consts.CodeReachMaxLimit).WithMessage("Failed Login User").WithError(consts.LoginLocked).WithStatus(consts.StatusFailed).WithEntity("login").WithState("loginFailed")
+	}
+
+	// unknown emails and wrong passwords fail the same way and take as long,
+	// so the response does not tell whether an account exists
+	userAccount, err := u.userRepository.GetByEmail(ctx, payload.Email)
 	if err != nil {
-		return *appctx.NewResponse().WithCode(consts.CodeNotFound).WithMessage("Failed Login User").WithError(err.Error()).WithStatus(consts.StatusFailed).WithEntity("login").WithState("loginFailed")
+		bcrypt.CompareHashAndPassword([]byte(consts.LoginDummyPasswordHash), []byte(payload.Password))
+		guard.fail(ctx, email, ip, nil)
+		return *appctx.NewResponse().WithCode(consts.CodeAuthenticationFailure).WithMessage("Failed Login User").WithError(consts.LoginFailed).WithStatus(consts.StatusFailed).WithEntity("login").WithState("loginFailed")
 	}
 
 	pass := []byte(payload.Password)
 	err = bcrypt.CompareHashAndPassword([]byte(userAccount.Password), pass)
 	if err != nil {
-		return *appctx.NewResponse().WithCode(consts.CodeAuthenticationFailure).WithMessage("Failed Login User").WithError(err.Error()).WithStatus(consts.StatusFailed).WithEntity("login").WithState("loginFailed")
+		guard.fail(ctx, email, ip, &userAccount.ID)
+		return *appctx.NewResponse().WithCode(consts.CodeAuthenticationFailure).WithMessage("Failed Login User").WithError(consts.LoginFailed).WithStatus(consts.StatusFailed).WithEntity("login").WithState("loginFailed")
+	}
+
+	guard.succeed(ctx, email)
+
+	if userAccount.IsSuspended() {
+		return *appctx.NewResponse().WithCode(consts.CodeForbidden).WithMessage("Failed Login User").WithError(consts.AccountSuspended).WithStatus(consts.StatusFailed).WithEntity("login").WithState("loginFailed")
+	}
+
+	// the access token is only handed out once the code step is passed
+	if userAccount.HasTwoFactor() {
+		challenge, err := newTwoFactorChallenge(userAccount, u.keys)
+		if err != nil {
+			return *appctx.NewResponse().WithCode(consts.CodeInternalServerError).WithMessage("Failed Login User").WithError(err.Error()).WithStatus(consts.StatusFailed).WithEntity("login").WithState("loginFailed")
+		}
+
+		return *appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(challenge).WithSecret().WithMessage("Two Factor Required").WithStatus(consts.StatusSuccess).WithEntity("login").WithState("loginTwoFactorRequired")
 	}
 
-	secret := []byte(data.Config.App.JWTSecret)
-	token, err := ucase.GenerateJWT(userAccount, secret)
+	token, err := ucase.GenerateJWT(userAccount, u.keys)
 	if err != nil {
 		return *appctx.NewResponse().WithCode(consts.CodeAuthenticationFailure).WithMessage("Failed Login User").WithError(err.Error()).WithStatus(consts.StatusFailed).WithEntity("login").WithState("loginFailed")
 	}
 
-	return *appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(token).WithMessage("Success Login User").WithStatus(consts.StatusSuccess).WithEntity("login").WithState("loginSuccess")
+	return *appctx.NewResponse().WithCode(consts.CodeSuccess).WithData(token).WithSecret().WithMessage("Success Login User").WithStatus(consts.StatusSuccess).WithEntity("login").WithState("loginSuccess")
 }
 
 // func generateJWT(user entity.User, secret []byte) (entity.TokenResponse, error) {
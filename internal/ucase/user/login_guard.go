@@ -0,0 +1,186 @@
+package user
+
+import (
+	"context"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/hash"
+	"sharefood/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// loginGuard throttles password guessing per email and per client address,
+// redis errors let the login through rather than locking everybody out
+type loginGuard struct {
+	loginAttemptRepository repositories.LoginAttempt
+	maxAttemptAccount      int64
+	maxAttemptIP           int64
+	window                 time.Duration
+	lockout                time.Duration
+}
+
+func newLoginGuard(cfg *appctx.Config, loginAttemptRepository repositories.LoginAttempt) loginGuard {
+	guard := loginGuard{
+		loginAttemptRepository: loginAttemptRepository,
+		maxAttemptAccount:      int64(cfg.Auth.LoginMaxAttemptAccount),
+		maxAttemptIP:           int64(cfg.Auth.LoginMaxAttemptIP),
+		window:                 time.Duration(cfg.Auth.LoginAttemptWindowMinute) * time.Minute,
+		lockout:                time.Duration(cfg.Auth.LoginLockoutMinute) * time.Minute,
+	}
+
+	if guard.maxAttemptAccount < 1 {
+		guard.maxAttemptAccount = consts.LoginMaxAttemptAccountDefault
+	}
+	if guard.maxAttemptIP < 1 {
+		guard.maxAttemptIP = consts.LoginMaxAttemptIPDefault
+	}
+	if guard.window < time.Minute {
+		guard.window = consts.LoginAttemptWindowMinuteDefault * time.Minute
+	}
+	if guard.lockout < time.Minute {
+		guard.lockout = consts.LoginLockoutMinuteDefault * time.Minute
+	}
+
+	return guard
+}
+
+// the email is hashed so the redis keys do not hold addresses in the clear
+func loginAccountKey(email string) string {
+	return consts.LoginLockoutReasonAccount + ":" + hash.SHA256(email)
+}
+
+func loginIPKey(ip string) string {
+	return consts.LoginLockoutReasonIP + ":" + ip
+}
+
+func loginTwoFactorKey(idUser uuid.UUID) string {
+	return consts.LoginLockoutReasonTwoFactor + ":" + idUser.String()
+}
+
+// locked tells whether the email or the address is locked out
+func (g loginGuard) locked(ctx context.Context, email string, ip string) bool {
+	return g.lockedAny(ctx, loginAccountKey(email), loginIPKey(ip))
+}
+
+// twoFactorLocked tells whether the account is locked out of the code step
+func (g loginGuard) twoFactorLocked(ctx context.Context, idUser uuid.UUID) bool {
+	return g.lockedAny(ctx, loginTwoFactorKey(idUser))
+}
+
+func (g loginGuard) lockedAny(ctx context.Context, keys ...string) bool {
+	for _, key := range keys {
+		ttl, err := g.loginAttemptRepository.LockedFor(ctx, key)
+		if err != nil {
+			logger.Error(logger.MessageFormat("[user-login] get lock error: %v", err))
+			continue
+		}
+
+		if ttl > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fail counts a failed attempt, locks out the email or the address once they reach their limit,
+// and waits longer the more the email has failed
+func (g loginGuard) fail(ctx context.Context, email string, ip string, idUser *uuid.UUID) {
+	accountFailures := g.count(ctx, loginAccountKey(email), g.maxAttemptAccount, entity.LoginLockout{
+		IDUser:    idUser,
+		Email:     email,
+		IPAddress: ip,
+		Reason:    consts.LoginLockoutReasonAccount,
+	})
+
+	g.count(ctx, loginIPKey(ip), g.maxAttemptIP, entity.LoginLockout{
+		IPAddress: ip,
+		Reason:    consts.LoginLockoutReasonIP,
+	})
+
+	select {
+	case <-time.After(loginDelay(accountFailures)):
+	case <-ctx.Done():
+	}
+}
+
+func (g loginGuard) count(ctx context.Context, key string, maxAttempt int64, lockout entity.LoginLockout) int64 {
+	failures, err := g.loginAttemptRepository.Fail(ctx, key, g.window)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[user-login] count failure error: %v", err))
+		return 0
+	}
+
+	if failures < maxAttempt {
+		return failures
+	}
+
+	if err := g.loginAttemptRepository.Lock(ctx, key, g.lockout); err != nil {
+		logger.Error(logger.MessageFormat("[user-login] lock error: %v", err))
+		return failures
+	}
+
+	lockout.ID = uuid.New()
+	lockout.Attempts = failures
+	lockout.LockedUntil = time.Now().Add(g.lockout)
+
+	logger.Warn(logger.MessageFormat("[user-login] locked out %s %s after %d failed attempts until %s", lockout.Reason, key, failures, lockout.LockedUntil.Format(time.RFC3339)))
+	if err := g.loginAttemptRepository.RecordLockout(ctx, lockout); err != nil {
+		logger.Error(logger.MessageFormat("[user-login] record lockout error: %v", err))
+	}
+
+	return failures
+}
+
+// twoFactorFail counts a wrong code against the account whatever challenge it came with, a new
+// challenge per password login does not give a fresh set of guesses
+func (g loginGuard) twoFactorFail(ctx context.Context, user entity.User, ip string) {
+	failures := g.count(ctx, loginTwoFactorKey(user.ID), g.maxAttemptAccount, entity.LoginLockout{
+		IDUser:    &user.ID,
+		Email:     user.Email,
+		IPAddress: ip,
+		Reason:    consts.LoginLockoutReasonTwoFactor,
+	})
+
+	select {
+	case <-time.After(loginDelay(failures)):
+	case <-ctx.Done():
+	}
+}
+
+// twoFactorSucceed clears the wrong codes of the account
+func (g loginGuard) twoFactorSucceed(ctx context.Context, idUser uuid.UUID) {
+	if err := g.loginAttemptRepository.Reset(ctx, loginTwoFactorKey(idUser)); err != nil {
+		logger.Error(logger.MessageFormat("[user-login] reset failures error: %v", err))
+	}
+}
+
+// succeed clears the failed attempts of the email, those of the address are kept
+// so one valid account cannot be used to keep guessing others
+func (g loginGuard) succeed(ctx context.Context, email string) {
+	if err := g.loginAttemptRepository.Reset(ctx, loginAccountKey(email)); err != nil {
+		logger.Error(logger.MessageFormat("[user-login] reset failures error: %v", err))
+	}
+}
+
+// loginDelay doubles with every failed attempt, up to the maximum
+func loginDelay(failures int64) time.Duration {
+	if failures < 1 {
+		return 0
+	}
+
+	delay := time.Duration(consts.LoginDelayBaseMS) * time.Millisecond
+	for i := int64(1); i < failures && delay < consts.LoginDelayMaxMS*time.Millisecond; i++ {
+		delay *= 2
+	}
+
+	if delay > consts.LoginDelayMaxMS*time.Millisecond {
+		delay = consts.LoginDelayMaxMS * time.Millisecond
+	}
+
+	return delay
+}
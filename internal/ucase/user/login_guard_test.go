@@ -0,0 +1,95 @@
+// Package user
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/cache"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLoginGuard(t *testing.T) (loginGuard, *miniredis.Miniredis, *fakeConn) {
+	srv := miniredis.RunT(t)
+	conn := &fakeConn{}
+	repository := repositories.NewLoginAttemptRepository(cache.NewCache(redis.NewClient(&redis.Options{Addr: srv.Addr()})), conn)
+
+	cfg := &appctx.Config{}
+	cfg.Auth.LoginMaxAttemptAccount = 3
+	cfg.Auth.LoginMaxAttemptIP = 5
+
+	return newLoginGuard(cfg, repository), srv, conn
+}
+
+func TestLoginGuard(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("test lock out the account", func(t *testing.T) {
+		guard, srv, conn := newTestLoginGuard(t)
+		lockout := entity.LoginLockout{Email: "budi@example.com", Reason: consts.LoginLockoutReasonAccount}
+
+		for i := 0; i < 2; i++ {
+			guard.count(ctx, loginAccountKey(lockout.Email), guard.maxAttemptAccount, lockout)
+		}
+		assert.False(t, guard.locked(ctx, lockout.Email, "10.0.0.1"))
+
+		guard.count(ctx, loginAccountKey(lockout.Email), guard.maxAttemptAccount, lockout)
+		assert.True(t, guard.locked(ctx, lockout.Email, "10.0.0.1"))
+		assert.True(t, guard.locked(ctx, lockout.Email, "10.0.0.2"))
+		assert.False(t, guard.locked(ctx, "ani@example.com", "10.0.0.1"))
+		assert.Len(t, conn.execs, 1)
+
+		// the lock ends with the lockout, the failures start over
+		srv.FastForward(guard.lockout)
+		assert.False(t, guard.locked(ctx, lockout.Email, "10.0.0.1"))
+		assert.Equal(t, int64(1), guard.count(ctx, loginAccountKey(lockout.Email), guard.maxAttemptAccount, lockout))
+	})
+
+	t.Run("test lock out the address", func(t *testing.T) {
+		guard, _, _ := newTestLoginGuard(t)
+		lockout := entity.LoginLockout{IPAddress: "10.0.0.1", Reason: consts.LoginLockoutReasonIP}
+
+		for i := 0; i < 5; i++ {
+			guard.count(ctx, loginIPKey(lockout.IPAddress), guard.maxAttemptIP, lockout)
+		}
+
+		assert.True(t, guard.locked(ctx, "ani@example.com", "10.0.0.1"))
+		assert.False(t, guard.locked(ctx, "ani@example.com", "10.0.0.2"))
+	})
+
+	t.Run("test success resets the account failures only", func(t *testing.T) {
+		guard, _, _ := newTestLoginGuard(t)
+		account := entity.LoginLockout{Email: "budi@example.com", Reason: consts.LoginLockoutReasonAccount}
+		address := entity.LoginLockout{IPAddress: "10.0.0.1", Reason: consts.LoginLockoutReasonIP}
+
+		guard.count(ctx, loginAccountKey(account.Email), guard.maxAttemptAccount, account)
+		guard.count(ctx, loginIPKey(address.IPAddress), guard.maxAttemptIP, address)
+		guard.succeed(ctx, account.Email)
+
+		assert.Equal(t, int64(1), guard.count(ctx, loginAccountKey(account.Email), guard.maxAttemptAccount, account))
+		assert.Equal(t, int64(2), guard.count(ctx, loginIPKey(address.IPAddress), guard.maxAttemptIP, address))
+	})
+
+	t.Run("test redis down lets the login through", func(t *testing.T) {
+		guard, srv, _ := newTestLoginGuard(t)
+		srv.Close()
+
+		assert.Equal(t, int64(0), guard.count(ctx, loginAccountKey("budi@example.com"), guard.maxAttemptAccount, entity.LoginLockout{}))
+		assert.False(t, guard.locked(ctx, "budi@example.com", "10.0.0.1"))
+	})
+}
+
+func TestLoginDelay(t *testing.T) {
+	assert.Equal(t, time.Duration(0), loginDelay(0))
+	assert.Equal(t, consts.LoginDelayBaseMS*time.Millisecond, loginDelay(1))
+	assert.Equal(t, 2*consts.LoginDelayBaseMS*time.Millisecond, loginDelay(2))
+	assert.Equal(t, consts.LoginDelayMaxMS*time.Millisecond, loginDelay(100))
+}
@@ -0,0 +1,145 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"time"
+)
+
+type userLoginTwoFactor struct {
+	userRepository         repositories.User
+	twoFactorRepository    repositories.TwoFactor
+	loginAttemptRepository repositories.LoginAttempt
+	keys                   *jwtx.KeySet
+}
+
+// NewUserLoginTwoFactor second login step, exchanges the challenge of the password step and a totp
+// or recovery code for the access token
+func NewUserLoginTwoFactor(userRepository repositories.User, twoFactorRepository repositories.TwoFactor, loginAttemptRepository repositories.LoginAttempt, keys *jwtx.KeySet) contract.UseCase {
+	return &userLoginTwoFactor{
+		userRepository:         userRepository,
+		twoFactorRepository:    twoFactorRepository,
+		loginAttemptRepository: loginAttemptRepository,
+		keys:                   keys,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *userLoginTwoFactor) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Complete a two-factor sign in",
+		Payload: entity.TwoFactorLogin{},
+		Result:  entity.TokenResponse{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *userLoginTwoFactor) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("login_two_factor", request)
+	errorEvent := consts.ErrorEvent("login_two_factor")
+	ctx := tracer.SpanStart(request.Context(), "login_two_factor")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.TwoFactorLogin{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[login-two-factor] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	claims, err := parseTwoFactorChallenge(payload.ChallengeToken, u.keys)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	attempts, err := u.twoFactorRepository.Attempt(ctx, claims.StandardClaims.ID, consts.TwoFactorChallengeTTLMinute*time.Minute)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[login-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if attempts > consts.TwoFactorMaxAttempt {
+		err := errorEvent.WithCode(consts.CodeReachMaxLimit).WrapError(consts.Error(consts.TwoFactorAttemptsExhausted))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	guard := newLoginGuard(data.Config, u.loginAttemptRepository)
+	if guard.twoFactorLocked(ctx, claims.ID) {
+		err := errorEvent.WithCode(consts.CodeReachMaxLimit).WrapError(consts.Error(consts.LoginLocked))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, claims.ID)
+	if err != nil || !user.HasTwoFactor() {
+		err := errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(consts.Error(consts.TwoFactorChallengeNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if user.IsSuspended() {
+		err := errorEvent.WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.AccountSuspended))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	valid := false
+	if payload.RecoveryCode != "" {
+		valid, err = u.twoFactorRepository.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(payload.RecoveryCode), time.Now())
+	} else {
+		var secret string
+		secret, err = u.twoFactorRepository.GetSecret(ctx, user.ID)
+		if err == nil {
+			valid, err = verifyTwoFactorCode(ctx, u.twoFactorRepository, user.ID, secret, payload.Code)
+		}
+	}
+	if err != nil {
+		logger.Error(logger.MessageFormat("[login-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !valid {
+		guard.twoFactorFail(ctx, user, util.ClientIP(request))
+		err := errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(consts.Error(consts.TwoFactorCodeNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// a challenge is exchanged once, whoever else holds it can not sign in with it until it expires
+	completed, err := u.twoFactorRepository.Complete(ctx, claims.StandardClaims.ID, consts.TwoFactorChallengeTTLMinute*time.Minute)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[login-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !completed {
+		err := errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(consts.Error(consts.TwoFactorChallengeNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	guard.twoFactorSucceed(ctx, user.ID)
+
+	token, err := ucase.GenerateJWT(user, u.keys)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[login-two-factor] %v", err))
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, token).WithSecret()
+}
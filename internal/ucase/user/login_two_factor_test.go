@@ -0,0 +1,106 @@
+// Package user
+package user
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/cache"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/totp"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserLoginTwoFactor_Serve(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	signing, _ := jwtx.NewSigningKey("test", private)
+	keys, _ := jwtx.NewKeySet(signing)
+
+	secret, _ := totp.GenerateSecret()
+	enabledAt := time.Now()
+	account := entity.User{ID: uuid.New(), Email: "budi@example.com", TwoFactorEnabledAt: &enabledAt}
+
+	cfg := &appctx.Config{}
+	cfg.Auth.LoginMaxAttemptAccount = 2
+
+	setup := func(t *testing.T) (*fakeTwoFactorRepository, func(challenge entity.TwoFactorChallenge, body string) appctx.Response) {
+		srv := miniredis.RunT(t)
+		loginAttempts := repositories.NewLoginAttemptRepository(cache.NewCache(redis.NewClient(&redis.Options{Addr: srv.Addr()})), &fakeConn{})
+
+		twoFactor := newFakeTwoFactorRepository()
+		twoFactor.secrets[account.ID] = secret
+		twoFactor.recoveryCodes[hashRecoveryCode("abcde-12345")] = true
+
+		users := &fakeUserRepository{users: map[uuid.UUID]entity.User{account.ID: account}}
+		svc := NewUserLoginTwoFactor(users, twoFactor, loginAttempts, keys)
+
+		return twoFactor, func(challenge entity.TwoFactorChallenge, body string) appctx.Response {
+			body = strings.Replace(body, "{", `{"challenge_token":"`+challenge.ChallengeToken+`",`, 1)
+			req := httptest.NewRequest("POST", "/login/2fa", strings.NewReader(body))
+			req.Header.Set(consts.HeaderContentTypeKey, consts.HeaderContentTypeJSON)
+
+			return svc.Serve(&appctx.Data{Request: req, Config: cfg, ServiceType: consts.ServiceTypeHTTP})
+		}
+	}
+
+	challenge := func(t *testing.T) entity.TwoFactorChallenge {
+		c, err := newTwoFactorChallenge(account, keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return c
+	}
+
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+
+	t.Run("test valid code", func(t *testing.T) {
+		_, serve := setup(t)
+
+		result := serve(challenge(t), `{"code":"`+code+`"}`)
+
+		assert.Equal(t, consts.CodeSuccess, result.Code)
+		assert.IsType(t, entity.TokenResponse{}, result.Data)
+		assert.True(t, result.Secret)
+	})
+
+	t.Run("test challenge is exchanged once", func(t *testing.T) {
+		_, serve := setup(t)
+		c := challenge(t)
+
+		assert.Equal(t, consts.CodeSuccess, serve(c, `{"code":"`+code+`"}`).Code)
+		assert.Equal(t, consts.CodeAuthenticationFailure, serve(c, `{"recovery_code":"abcde-12345"}`).Code)
+	})
+
+	t.Run("test wrong codes lock out the account across challenges", func(t *testing.T) {
+		_, serve := setup(t)
+
+		assert.Equal(t, consts.CodeAuthenticationFailure, serve(challenge(t), `{"code":"000000"}`).Code)
+		assert.Equal(t, consts.CodeAuthenticationFailure, serve(challenge(t), `{"code":"000000"}`).Code)
+
+		result := serve(challenge(t), `{"code":"`+code+`"}`)
+		assert.Equal(t, consts.CodeReachMaxLimit, result.Code)
+	})
+
+	t.Run("test wrong codes of a challenge", func(t *testing.T) {
+		twoFactor, serve := setup(t)
+		c := challenge(t)
+
+		claims, _ := parseTwoFactorChallenge(c.ChallengeToken, keys)
+		twoFactor.attempts[claims.StandardClaims.ID] = consts.TwoFactorMaxAttempt
+
+		assert.Equal(t, consts.CodeReachMaxLimit, serve(c, `{"code":"`+code+`"}`).Code)
+	})
+}
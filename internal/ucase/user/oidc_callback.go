@@ -0,0 +1,204 @@
+package user
+
+import (
+	"context"
+	"crypto/subtle"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/oidc"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type oidcCallback struct {
+	providers              map[string]*oidc.Provider
+	oidcSessionRepository  repositories.OIDCSession
+	userRepository         repositories.User
+	userIdentityRepository repositories.UserIdentity
+	keys                   *jwtx.KeySet
+}
+
+func NewOIDCCallback(providers map[string]*oidc.Provider, oidcSessionRepository repositories.OIDCSession, userRepository repositories.User, userIdentityRepository repositories.UserIdentity, keys *jwtx.KeySet) contract.UseCase {
+	return &oidcCallback{
+		providers:              providers,
+		oidcSessionRepository:  oidcSessionRepository,
+		userRepository:         userRepository,
+		userIdentityRepository: userIdentityRepository,
+		keys:                   keys,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *oidcCallback) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Complete an OpenID Connect sign in, answers a two-factor challenge instead when the account has two-factor enabled",
+		Payload: entity.OIDCCallback{},
+		Result:  entity.TokenResponse{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *oidcCallback) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("oidc_callback", request)
+	errorEvent := consts.ErrorEvent("oidc_callback")
+	ctx := tracer.SpanStart(request.Context(), "oidc_callback")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	name := mux.Vars(data.Request)["provider"]
+	provider, ok := u.providers[name]
+	if !ok {
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.OIDCProviderNotFound))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	payload := entity.OIDCCallback{}
+	err := data.Cast(&payload)
+	if err != nil || payload.Code == "" || payload.State == "" {
+		logger.Warn(logger.MessageFormat("[oidc-callback] parsing request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.OIDCStateNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	cookie, err := request.Cookie(consts.OIDCStateCookieName)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(payload.State)) != 1 {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.OIDCStateNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	session, err := u.oidcSessionRepository.Take(ctx, payload.State)
+	if err != nil {
+		logger.Warn(logger.MessageFormat("[oidc-callback] %v", err))
+		err := errorEvent.WithMessage(consts.OIDCStateNotValid).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if session.Provider != name {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.OIDCStateNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	claims, err := provider.Exchange(ctx, payload.Code, session.Verifier, session.Nonce)
+	if err != nil {
+		logger.Warn(logger.MessageFormat("[oidc-callback] %s: %v", name, err))
+		err := errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(consts.Error(consts.OIDCLoginFailed))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.account(ctx, name, claims)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[oidc-callback] %v", err))
+		err := errorEvent.WithMessage(consts.OIDCLoginFailed).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if user.IsSuspended() {
+		err := errorEvent.WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.AccountSuspended))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if user.HasTwoFactor() {
+		challenge, err := newTwoFactorChallenge(user, u.keys)
+		if err != nil {
+			logger.Error(logger.MessageFormat("[oidc-callback] %v", err))
+			err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+			return *response.Failed(ctx, &transactionID, err)
+		}
+
+		return *response.Success(ctx, consts.CodeSuccess, &transactionID, challenge).WithSecret().WithCookie(oidcStateCookie("", -1))
+	}
+
+	token, err := ucase.GenerateJWT(user, u.keys)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[oidc-callback] %v", err))
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, token).WithSecret().WithCookie(oidcStateCookie("", -1))
+}
+
+// account linked to the identity; an unlinked identity is linked to the account with the same email,
+// or gets a new account, but only when the provider has verified the email
+func (u *oidcCallback) account(ctx context.Context, provider string, claims oidc.Claims) (entity.User, error) {
+	errorEvent := consts.ErrorEvent("oidc_account")
+
+	idUser, err := u.userIdentityRepository.GetUserID(ctx, provider, claims.Subject)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	if idUser != uuid.Nil {
+		user, err := u.userRepository.GetByID(ctx, idUser)
+		if err != nil {
+			return entity.User{}, errorEvent.WithCode(consts.CodeAuthenticationFailure).WrapError(consts.Error(consts.OIDCLoginFailed))
+		}
+
+		return user, nil
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return entity.User{}, errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.OIDCEmailNotVerified))
+	}
+
+	var user entity.User
+	if u.userRepository.IsRegistered(ctx, claims.Email) {
+		user, err = u.userRepository.GetByEmail(ctx, claims.Email)
+		if err != nil {
+			return entity.User{}, errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		}
+
+		// whoever registered the unverified account may not be the owner of the email, the account is
+		// only linked once its owner proved the email by verifying it
+		if user.EmailVerifiedAt == nil {
+			return entity.User{}, errorEvent.WithCode(consts.CodeDuplicateEntry).WrapError(consts.Error(consts.OIDCAccountNotVerified))
+		}
+	} else {
+		user = entity.User{
+			ID:    uuid.New(),
+			Name:  claims.Name,
+			Email: claims.Email,
+			Roles: consts.DefaultRoles,
+		}
+		if user.Name == "" {
+			user.Name = strings.Split(claims.Email, "@")[0]
+		}
+
+		if err := u.userRepository.Create(ctx, &user); err != nil {
+			return entity.User{}, err
+		}
+	}
+
+	if user.EmailVerifiedAt == nil {
+		if err := u.userRepository.MarkEmailVerified(ctx, user.ID, user.Email); err != nil {
+			return entity.User{}, err
+		}
+	}
+
+	err = u.userIdentityRepository.Create(ctx, entity.UserIdentity{
+		Provider: provider,
+		Subject:  claims.Subject,
+		IDUser:   user.ID,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	// read back so the token carries the stored roles and verification
+	return u.userRepository.GetByID(ctx, user.ID)
+}
@@ -0,0 +1,141 @@
+// Package user
+package user
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/oidc"
+	"sharefood/pkg/oidc/oidctest"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDCCallback_Serve(t *testing.T) {
+	mock, err := oidctest.NewMockProvider("sharefood", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(mock)
+	t.Cleanup(srv.Close)
+	mock.Issuer = srv.URL
+
+	providers := map[string]*oidc.Provider{
+		"mock": oidc.NewProvider(oidc.Config{
+			Issuer:       srv.URL,
+			ClientID:     "sharefood",
+			ClientSecret: "secret",
+			RedirectURL:  "http://localhost:3000/auth/oidc/mock/callback",
+		}, srv.Client()),
+	}
+
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	signing, _ := jwtx.NewSigningKey("test", private)
+	keys, _ := jwtx.NewKeySet(signing)
+
+	verifiedAt := time.Now()
+
+	// login starts a sign in and follows the provider back to the callback url, the cookie is
+	// the one the login set
+	login := func(sessions *fakeOIDCSessionRepository) (url.Values, *http.Cookie) {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/auth/oidc/mock/login", nil), map[string]string{"provider": "mock"})
+		result := NewOIDCLogin(providers, sessions).Serve(&appctx.Data{Request: req, Config: &appctx.Config{}, ServiceType: consts.ServiceTypeHTTP})
+		if !assert.Equal(t, consts.CodeSuccess, result.Code) || !assert.Len(t, result.Cookies, 1) {
+			t.FailNow()
+		}
+
+		client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}}
+		resp, err := client.Get(result.Data.(entity.OIDCAuthorization).AuthorizationURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		location, err := url.Parse(resp.Header.Get("Location"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return location.Query(), result.Cookies[0]
+	}
+
+	serve := func(account *entity.User, cookie *http.Cookie) (appctx.Response, *fakeUserIdentityRepository) {
+		users := &fakeUserRepository{users: map[uuid.UUID]entity.User{}}
+		if account != nil {
+			users.users[account.ID] = *account
+		}
+		sessions := &fakeOIDCSessionRepository{sessions: map[string]entity.OIDCSession{}}
+		identities := &fakeUserIdentityRepository{}
+
+		query, stateCookie := login(sessions)
+		if cookie == nil {
+			cookie = stateCookie
+		}
+
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/auth/oidc/mock/callback?"+query.Encode(), nil), map[string]string{"provider": "mock"})
+		if cookie.Name != "" {
+			req.AddCookie(cookie)
+		}
+
+		svc := NewOIDCCallback(providers, sessions, users, identities, keys)
+
+		return svc.Serve(&appctx.Data{Request: req, Config: &appctx.Config{}, ServiceType: consts.ServiceTypeHTTP}), identities
+	}
+
+	t.Run("test login sets the state cookie", func(t *testing.T) {
+		query, cookie := login(&fakeOIDCSessionRepository{sessions: map[string]entity.OIDCSession{}})
+
+		assert.Equal(t, consts.OIDCStateCookieName, cookie.Name)
+		assert.Equal(t, query.Get("state"), cookie.Value)
+		assert.True(t, cookie.HttpOnly)
+	})
+
+	t.Run("test verified account is linked", func(t *testing.T) {
+		account := entity.User{ID: uuid.New(), Email: mock.Identity.Email, EmailVerifiedAt: &verifiedAt}
+		result, identities := serve(&account, nil)
+
+		assert.Equal(t, consts.CodeSuccess, result.Code)
+		if assert.Len(t, identities.identities, 1) {
+			assert.Equal(t, account.ID, identities.identities[0].IDUser)
+		}
+		if assert.Len(t, result.Cookies, 1) {
+			assert.Less(t, result.Cookies[0].MaxAge, 0)
+		}
+	})
+
+	t.Run("test without the state cookie", func(t *testing.T) {
+		result, identities := serve(nil, &http.Cookie{})
+
+		assert.Equal(t, consts.CodeBadRequest, result.Code)
+		assert.Empty(t, identities.identities)
+	})
+
+	t.Run("test state cookie of another login", func(t *testing.T) {
+		result, identities := serve(nil, &http.Cookie{Name: consts.OIDCStateCookieName, Value: "someone-else"})
+
+		assert.Equal(t, consts.CodeBadRequest, result.Code)
+		assert.Empty(t, identities.identities)
+	})
+
+	t.Run("test unverified account is not linked", func(t *testing.T) {
+		account := entity.User{ID: uuid.New(), Email: mock.Identity.Email, Password: "hashed"}
+		result, identities := serve(&account, nil)
+
+		assert.Equal(t, consts.CodeDuplicateEntry, result.Code)
+		assert.Empty(t, identities.identities)
+	})
+}
@@ -0,0 +1,114 @@
+package user
+
+import (
+	"net/http"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/oidc"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type oidcLogin struct {
+	providers             map[string]*oidc.Provider
+	oidcSessionRepository repositories.OIDCSession
+}
+
+func NewOIDCLogin(providers map[string]*oidc.Provider, oidcSessionRepository repositories.OIDCSession) contract.UseCase {
+	return &oidcLogin{
+		providers:             providers,
+		oidcSessionRepository: oidcSessionRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *oidcLogin) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Start an OpenID Connect sign in",
+		Result:  entity.OIDCAuthorization{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *oidcLogin) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("oidc_login", request)
+	errorEvent := consts.ErrorEvent("oidc_login")
+	ctx := tracer.SpanStart(request.Context(), "oidc_login")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	name := mux.Vars(data.Request)["provider"]
+	provider, ok := u.providers[name]
+	if !ok {
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(consts.Error(consts.OIDCProviderNotFound))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	nonce, err := oidc.RandomString(32)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, oidc.S256Challenge(verifier))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[oidc-login] %s: %v", name, err))
+		err := errorEvent.WithMessage(consts.OIDCLoginFailed).WithCode(consts.CodeServerBusy).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.oidcSessionRepository.Save(ctx, state, entity.OIDCSession{
+		Provider: name,
+		Nonce:    nonce,
+		Verifier: verifier,
+	}, consts.OIDCSessionTTLMinute*time.Minute)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[oidc-login] %v", err))
+		err := errorEvent.WithMessage(consts.OIDCLoginFailed).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, entity.OIDCAuthorization{
+		AuthorizationURL: authURL,
+		State:            state,
+	}).WithCookie(oidcStateCookie(state, consts.OIDCSessionTTLMinute*60))
+}
+
+// oidcStateCookie the state of the login kept by the browser that started it, the callback only accepts
+// the state the browser brings back so a login started by someone else can not be completed in it.
+// A negative max age removes the cookie
+func oidcStateCookie(state string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     consts.OIDCStateCookieName,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: true,
+		// sent on the top level redirect back from the identity provider
+		SameSite: http.SameSiteLaxMode,
+	}
+}
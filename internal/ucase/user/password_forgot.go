@@ -0,0 +1,130 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/hash"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/mailer"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thedevsaddam/govalidator"
+)
+
+type passwordForgot struct {
+	userRepository          repositories.User
+	passwordResetRepository repositories.PasswordReset
+	mailer                  mailer.Mailer
+}
+
+func NewPasswordForgot(userRepository repositories.User, passwordResetRepository repositories.PasswordReset, mailer mailer.Mailer) contract.UseCase {
+	return &passwordForgot{
+		userRepository:          userRepository,
+		passwordResetRepository: passwordResetRepository,
+		mailer:                  mailer,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *passwordForgot) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Send a password reset link",
+		Payload: entity.PasswordForgot{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *passwordForgot) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("forgot_password", request)
+	errorEvent := consts.ErrorEvent("forgot_password")
+	ctx := tracer.SpanStart(request.Context(), "forgot_password")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.PasswordForgot{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[forgot-password] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data: &payload,
+		Rules: govalidator.MapData{
+			"email": []string{"required", "email"},
+		},
+	})
+	if ev := v.ValidateStruct(); len(ev) != 0 {
+		logger.Warn(logger.MessageFormat("[forgot-password] validate request param err: %s", util.DumpToString(ev)))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(util.DumpToString(ev)))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// the response is the same whether the email is registered or not, and whether the link could be
+	// sent or not
+	userAccount, err := u.userRepository.GetByEmail(ctx, payload.Email)
+	if err != nil {
+		logger.Warn(logger.MessageFormat("[forgot-password] account not found: %v", err))
+		return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+	}
+
+	if err := u.sendResetLink(ctx, data.Config, userAccount); err != nil {
+		logger.Error(logger.MessageFormat("[forgot-password] %v", err))
+		tracer.SpanError(ctx, errorEvent.WrapError(err))
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
+
+// sendResetLink issues a reset token to the user and mails its link
+func (u *passwordForgot) sendResetLink(ctx context.Context, cfg *appctx.Config, userAccount entity.User) error {
+	token, err := util.GenerateSecureToken(consts.PasswordResetTokenBytes)
+	if err != nil {
+		return fmt.Errorf("generate token error: %w", err)
+	}
+
+	ttl := cfg.Auth.PasswordResetTTLMinute
+	if ttl < 1 {
+		ttl = consts.PasswordResetTTLMinuteDefault
+	}
+
+	reset := entity.PasswordReset{
+		ID:        uuid.New(),
+		IDUser:    userAccount.ID,
+		TokenHash: hash.SHA256(token),
+		ExpiredAt: time.Now().Local().Add(time.Duration(ttl) * time.Minute),
+	}
+
+	err = u.passwordResetRepository.Create(ctx, &reset)
+	if err != nil {
+		return err
+	}
+
+	err = u.mailer.Send(ctx, mailer.Message{
+		To:      []string{userAccount.Email},
+		Subject: "Reset your Sharefood password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to choose a new password. The link expires in %d minutes and can only be used once.\n\n%s?token=%s\n\nIf you did not ask for a password reset, you can ignore this email.\n",
+			userAccount.Name, ttl, cfg.Auth.PasswordResetURL, token,
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("send mail error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+// Package user
+package user
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordForgot_Serve(t *testing.T) {
+	registered := entity.User{ID: uuid.New(), Name: "Budi", Email: "budi@example.com"}
+
+	serve := func(mail *fakeMailer, resets *fakePasswordResetRepository, email string) appctx.Response {
+		svc := NewPasswordForgot(&fakeUserRepository{users: map[uuid.UUID]entity.User{registered.ID: registered}}, resets, mail)
+
+		req := httptest.NewRequest("POST", "/user/password/forgot", strings.NewReader(`{"email":"`+email+`"}`))
+		req.Header.Set(consts.HeaderContentTypeKey, consts.HeaderContentTypeJSON)
+
+		return svc.Serve(&appctx.Data{Request: req, Config: &appctx.Config{}, ServiceType: consts.ServiceTypeHTTP})
+	}
+
+	t.Run("test registered email", func(t *testing.T) {
+		mail, resets := &fakeMailer{}, &fakePasswordResetRepository{}
+
+		result := serve(mail, resets, registered.Email)
+
+		assert.Equal(t, consts.CodeSuccess, result.Code)
+		assert.Len(t, resets.created, 1)
+		assert.Len(t, mail.sent, 1)
+	})
+
+	t.Run("test unknown email", func(t *testing.T) {
+		mail, resets := &fakeMailer{}, &fakePasswordResetRepository{}
+
+		result := serve(mail, resets, "nobody@example.com")
+
+		assert.Equal(t, consts.CodeSuccess, result.Code)
+		assert.Empty(t, resets.created)
+		assert.Empty(t, mail.sent)
+	})
+
+	t.Run("test mail not sent", func(t *testing.T) {
+		mail, resets := &fakeMailer{err: errors.New("connection refused")}, &fakePasswordResetRepository{}
+
+		result := serve(mail, resets, registered.Email)
+
+		assert.Equal(t, consts.CodeSuccess, result.Code)
+	})
+}
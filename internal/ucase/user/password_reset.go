@@ -0,0 +1,98 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/hash"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"time"
+
+	"github.com/thedevsaddam/govalidator"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type passwordReset struct {
+	passwordResetRepository repositories.PasswordReset
+}
+
+func NewPasswordReset(passwordResetRepository repositories.PasswordReset) contract.UseCase {
+	return &passwordReset{
+		passwordResetRepository: passwordResetRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *passwordReset) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Reset the password",
+		Payload: entity.PasswordResetInput{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *passwordReset) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("reset_password", request)
+	errorEvent := consts.ErrorEvent("reset_password")
+	ctx := tracer.SpanStart(request.Context(), "reset_password")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.PasswordResetInput{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[reset-password] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data: &payload,
+		Rules: govalidator.MapData{
+			"token":    []string{"required"},
+			"password": []string{"required", "min:4"},
+		},
+	})
+	if ev := v.ValidateStruct(); len(ev) != 0 {
+		logger.Warn(logger.MessageFormat("[reset-password] validate request param err: %s", util.DumpToString(ev)))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(util.DumpToString(ev)))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	reset, err := u.passwordResetRepository.GetByTokenHash(ctx, hash.SHA256(payload.Token))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[reset-password] %v", err))
+		err := errorEvent.WithMessage(consts.ResetPasswordErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if reset.UsedAt != nil || time.Now().After(reset.ExpiredAt) {
+		logger.Warn(logger.MessageFormat("[reset-password] token already used or expired"))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.ResetTokenNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(payload.Password), bcrypt.DefaultCost)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.passwordResetRepository.Redeem(ctx, reset, string(hashedPassword))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[reset-password] %v", err))
+		err := errorEvent.WithMessage(consts.ResetPasswordErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
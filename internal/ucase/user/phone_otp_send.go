@@ -0,0 +1,86 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/sms"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+)
+
+type phoneOTPSend struct {
+	userRepository                 repositories.User
+	phoneVerificationRepository    repositories.PhoneVerification
+	verificationThrottleRepository repositories.VerificationThrottle
+	sender                         sms.Sender
+}
+
+func NewPhoneOTPSend(userRepository repositories.User, phoneVerificationRepository repositories.PhoneVerification, verificationThrottleRepository repositories.VerificationThrottle, sender sms.Sender) contract.UseCase {
+	return &phoneOTPSend{
+		userRepository:                 userRepository,
+		phoneVerificationRepository:    phoneVerificationRepository,
+		verificationThrottleRepository: verificationThrottleRepository,
+		sender:                         sender,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *phoneOTPSend) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Send the phone verification code",
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *phoneOTPSend) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("send_phone_otp", request)
+	errorEvent := consts.ErrorEvent("send_phone_otp")
+	ctx := tracer.SpanStart(request.Context(), "send_phone_otp")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	idUser := data.Request.Header.Get("idUser")
+	uuidUser, err := uuid.Parse(idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[send-phone-otp] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[send-phone-otp] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if user.PhoneVerifiedAt != nil {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.AlreadyVerified))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// a new code comes with new attempts, so they are paced per user and per phone number
+	err = newVerificationGuard(data.Config, u.verificationThrottleRepository).send(ctx, consts.VerificationChannelPhone, user.ID, user.PhoneNumber)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeReachMaxLimit).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = sendPhoneOTP(ctx, data.Config, u.phoneVerificationRepository, u.sender, user)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[send-phone-otp] %v", err))
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
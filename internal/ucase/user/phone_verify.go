@@ -0,0 +1,123 @@
+package user
+
+import (
+	"crypto/subtle"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type phoneVerify struct {
+	userRepository                 repositories.User
+	phoneVerificationRepository    repositories.PhoneVerification
+	verificationThrottleRepository repositories.VerificationThrottle
+}
+
+func NewPhoneVerify(userRepository repositories.User, phoneVerificationRepository repositories.PhoneVerification, verificationThrottleRepository repositories.VerificationThrottle) contract.UseCase {
+	return &phoneVerify{
+		userRepository:                 userRepository,
+		phoneVerificationRepository:    phoneVerificationRepository,
+		verificationThrottleRepository: verificationThrottleRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *phoneVerify) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Verify the phone number",
+		Payload: entity.PhoneVerificationInput{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *phoneVerify) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("verify_phone", request)
+	errorEvent := consts.ErrorEvent("verify_phone")
+	ctx := tracer.SpanStart(request.Context(), "verify_phone")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.PhoneVerificationInput{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[verify-phone] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idUser := data.Request.Header.Get("idUser")
+	uuidUser, err := uuid.Parse(idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[verify-phone] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[verify-phone] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if user.PhoneVerifiedAt != nil {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.AlreadyVerified))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	verification, err := u.phoneVerificationRepository.GetLatestByUser(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[verify-phone] %v", err))
+		err := errorEvent.WithMessage(consts.VerifyAccountErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if verification.VerifiedAt != nil || time.Now().After(verification.ExpiredAt) || verification.PhoneNumber != user.PhoneNumber {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.VerificationCodeNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	maxAttempt := data.Config.Auth.PhoneOTPMaxAttempt
+	if maxAttempt < 1 {
+		maxAttempt = consts.PhoneOTPMaxAttemptDefault
+	}
+
+	// every guess uses up an attempt of the code and of the daily budget of the user, the right one
+	// included, so requesting new codes does not buy more guesses
+	if !newVerificationGuard(data.Config, u.verificationThrottleRepository).attempt(ctx, uuidUser) {
+		err := errorEvent.WithCode(consts.CodeReachMaxLimit).WrapError(consts.Error(consts.VerificationTriesExceeded))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if err := u.phoneVerificationRepository.ConsumeAttempt(ctx, verification.ID, maxAttempt); err != nil {
+		logger.Error(logger.MessageFormat("[verify-phone] %v", err))
+		err := errorEvent.WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(phoneOTPHash(uuidUser, payload.Code)), []byte(verification.CodeHash)) != 1 {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.VerificationCodeNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.phoneVerificationRepository.Verify(ctx, verification)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[verify-phone] %v", err))
+		err := errorEvent.WithMessage(consts.VerifyAccountErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
@@ -0,0 +1,139 @@
+package user
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/presentations"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/storage"
+	"sharefood/pkg/tracer"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// avatarExtensions allowed avatar content types, detected from the file content
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+type profileAvatarUpload struct {
+	userRepository repositories.User
+	storage        storage.Storage
+}
+
+func NewProfileAvatarUpload(userRepository repositories.User, storage storage.Storage) contract.UseCase {
+	return &profileAvatarUpload{
+		userRepository: userRepository,
+		storage:        storage,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *profileAvatarUpload) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Upload my avatar",
+		Upload:  consts.AvatarFormField,
+		Result:  presentations.UserProfile{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *profileAvatarUpload) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("upload_avatar", request)
+	errorEvent := consts.ErrorEvent("upload_avatar")
+	ctx := tracer.SpanStart(request.Context(), "upload_avatar")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+	cfg := data.Config.Storage
+
+	maxSize := int64(cfg.AvatarMaxSizeKB) << 10
+	if maxSize < 1 {
+		maxSize = consts.AvatarMaxSizeKBDefault << 10
+	}
+
+	idUser := data.Request.Header.Get("idUser")
+	uuidUser, err := uuid.Parse(idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[upload-avatar] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// leave room for the multipart envelope around the file
+	request.Body = http.MaxBytesReader(nil, request.Body, maxSize+(1<<20))
+	file, _, err := request.FormFile(consts.AvatarFormField)
+	if err != nil {
+		logger.Warn(logger.MessageFormat("[upload-avatar] reading form file error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+	defer file.Close()
+
+	contents, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[upload-avatar] reading file error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if int64(len(contents)) > maxSize {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.AvatarTooLarge))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	contentType := http.DetectContentType(contents)
+	ext, ok := avatarExtensions[contentType]
+	if !ok {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.AvatarNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[upload-avatar] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// a fresh name on every upload so cached copies of the previous avatar never linger
+	name := fmt.Sprintf("avatar_%s_%d%s", uuidUser, time.Now().UnixNano(), ext)
+	err = u.storage.Put(ctx, cfg.Bucket, name, contents, true, contentType)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[upload-avatar] %v", err))
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	publicURL := strings.TrimSuffix(cfg.PublicURL, "/")
+	err = u.userRepository.UpdateImageURL(ctx, uuidUser, publicURL+"/"+name)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[upload-avatar] %v", err))
+		err := errorEvent.WithMessage(consts.UpdateProfileErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// only objects this service stored are removed, an external image url is left alone
+	if oldName := strings.TrimPrefix(user.ImageUrl, publicURL+"/"); oldName != user.ImageUrl && oldName != "" {
+		if err := u.storage.Delete(ctx, cfg.Bucket, oldName); err != nil {
+			logger.Warn(logger.MessageFormat("[upload-avatar] delete previous avatar error: %v", err))
+		}
+	}
+
+	user.ImageUrl = publicURL + "/" + name
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, presentations.NewUserProfile(user))
+}
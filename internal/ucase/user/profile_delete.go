@@ -0,0 +1,151 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/storage"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thedevsaddam/govalidator"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type profileDelete struct {
+	userRepository         repositories.User
+	organizationRepository repositories.Organization
+	storage                storage.Storage
+}
+
+func NewProfileDelete(userRepository repositories.User, organizationRepository repositories.Organization, storage storage.Storage) contract.UseCase {
+	return &profileDelete{
+		userRepository:         userRepository,
+		organizationRepository: organizationRepository,
+		storage:                storage,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *profileDelete) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Delete my account",
+		Payload: entity.AccountDelete{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *profileDelete) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("delete_account", request)
+	errorEvent := consts.ErrorEvent("delete_account")
+	ctx := tracer.SpanStart(request.Context(), "delete_account")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.AccountDelete{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[delete-account] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data: &payload,
+		Rules: govalidator.MapData{
+			"password": []string{"required"},
+		},
+	})
+	if ev := v.ValidateStruct(); len(ev) != 0 {
+		logger.Warn(logger.MessageFormat("[delete-account] validate request param err: %s", util.DumpToString(ev)))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(util.DumpToString(ev)))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idUser := data.Request.Header.Get("idUser")
+	uuidUser, err := uuid.Parse(idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[delete-account] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[delete-account] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(payload.Password))
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.CurrentPasswordNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// an organization left without an owner could never be managed again
+	organizations, err := u.organizationRepository.ListByUser(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[delete-account] %v", err))
+		err := errorEvent.WithMessage(consts.DeleteAccountErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	for _, organization := range organizations {
+		if organization.MemberRole != consts.OrganizationMemberOwner {
+			continue
+		}
+
+		members, err := u.organizationRepository.ListMembers(ctx, organization.ID)
+		if err != nil {
+			logger.Error(logger.MessageFormat("[delete-account] %v", err))
+			err := errorEvent.WithMessage(consts.DeleteAccountErrorMessage).WrapError(err)
+			return *response.Failed(ctx, &transactionID, err)
+		}
+
+		if !hasOtherOwner(members, uuidUser) {
+			err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.AccountSoleOwner))
+			return *response.Failed(ctx, &transactionID, err)
+		}
+	}
+
+	// the jwt issued at claim is kept to the microsecond, so is the revocation time
+	err = u.userRepository.Delete(ctx, uuidUser, time.Now())
+	if err != nil {
+		logger.Error(logger.MessageFormat("[delete-account] %v", err))
+		err := errorEvent.WithMessage(consts.DeleteAccountErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// only objects this service stored are removed, an external image url is left alone
+	cfg := data.Config.Storage
+	publicURL := strings.TrimSuffix(cfg.PublicURL, "/")
+	if name := strings.TrimPrefix(user.ImageUrl, publicURL+"/"); name != user.ImageUrl && name != "" {
+		if err := u.storage.Delete(ctx, cfg.Bucket, name); err != nil {
+			logger.Warn(logger.MessageFormat("[delete-account] delete avatar error: %v", err))
+		}
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
+
+func hasOtherOwner(members []entity.OrganizationMember, idUser uuid.UUID) bool {
+	for _, member := range members {
+		if member.IDUser != idUser && member.Role == consts.OrganizationMemberOwner {
+			return true
+		}
+	}
+
+	return false
+}
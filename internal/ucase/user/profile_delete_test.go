@@ -0,0 +1,55 @@
+// Package user
+package user
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestProfileDelete_Serve(t *testing.T) {
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("rahasia123"), bcrypt.MinCost)
+	account := entity.User{ID: uuid.New(), Email: "budi@example.com", Password: string(hashed)}
+
+	serve := func(target string, body string) (appctx.Response, *fakeUserRepository) {
+		users := &fakeUserRepository{users: map[uuid.UUID]entity.User{account.ID: account}}
+		svc := NewProfileDelete(users, &fakeOrganizationRepository{}, nil)
+
+		req := httptest.NewRequest("DELETE", target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(consts.HeaderContentTypeKey, consts.HeaderContentTypeJSON)
+		}
+		req.Header.Set("idUser", account.ID.String())
+
+		return svc.Serve(&appctx.Data{Request: req, Config: &appctx.Config{}, ServiceType: consts.ServiceTypeHTTP}), users
+	}
+
+	t.Run("test password in the body", func(t *testing.T) {
+		result, users := serve("/me", `{"password":"rahasia123"}`)
+
+		assert.Equal(t, consts.CodeSuccess, result.Code)
+		assert.NotContains(t, users.users, account.ID)
+	})
+
+	t.Run("test wrong password", func(t *testing.T) {
+		result, users := serve("/me", `{"password":"salah"}`)
+
+		assert.Equal(t, consts.CodeUnprocessableEntity, result.Code)
+		assert.Contains(t, users.users, account.ID)
+	})
+
+	t.Run("test password in the query string", func(t *testing.T) {
+		result, users := serve("/me?password=rahasia123", "")
+
+		assert.Equal(t, consts.CodeBadRequest, result.Code)
+		assert.Contains(t, users.users, account.ID)
+	})
+}
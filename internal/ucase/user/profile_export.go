@@ -0,0 +1,111 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/presentations"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type profileExport struct {
+	userRepository         repositories.User
+	foodRepository         repositories.Food
+	requestRepository      repositories.Request
+	organizationRepository repositories.Organization
+}
+
+func NewProfileExport(userRepository repositories.User, foodRepository repositories.Food, requestRepository repositories.Request, organizationRepository repositories.Organization) contract.UseCase {
+	return &profileExport{
+		userRepository:         userRepository,
+		foodRepository:         foodRepository,
+		requestRepository:      requestRepository,
+		organizationRepository: organizationRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *profileExport) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Export my data",
+		Result:  presentations.UserExport{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *profileExport) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("export_account", request)
+	errorEvent := consts.ErrorEvent("export_account")
+	ctx := tracer.SpanStart(request.Context(), "export_account")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	idUser := data.Request.Header.Get("idUser")
+	uuidUser, err := uuid.Parse(idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[export-account] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[export-account] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	organizations, err := u.organizationRepository.ListByUser(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[export-account] %v", err))
+		err := errorEvent.WithMessage(consts.ExportAccountErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	foods, err := u.foodRepository.ListByUser(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[export-account] %v", err))
+		err := errorEvent.WithMessage(consts.ExportAccountErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	requests, err := u.requestRepository.ListbyUser(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[export-account] %v", err))
+		err := errorEvent.WithMessage(consts.ExportAccountErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	export := presentations.UserExport{
+		Profile:       presentations.NewUserProfile(user),
+		Roles:         user.Roles,
+		Organizations: organizations,
+		Foods:         foods,
+		Requests:      requests,
+		ExportedAt:    time.Now(),
+	}
+
+	// empty lists rather than null, the archive reads the same for every account
+	if export.Organizations == nil {
+		export.Organizations = []entity.Organization{}
+	}
+	if export.Foods == nil {
+		export.Foods = []entity.Food{}
+	}
+	if export.Requests == nil {
+		export.Requests = []entity.Request{}
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, export)
+}
@@ -0,0 +1,62 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/presentations"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+)
+
+type profileGet struct {
+	userRepository repositories.User
+}
+
+func NewProfileGet(userRepository repositories.User) contract.UseCase {
+	return &profileGet{
+		userRepository: userRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *profileGet) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "My profile",
+		Result:  presentations.UserProfile{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *profileGet) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("get_profile", request)
+	errorEvent := consts.ErrorEvent("get_profile")
+	ctx := tracer.SpanStart(request.Context(), "get_profile")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	idUser := data.Request.Header.Get("idUser")
+	uuidUser, err := uuid.Parse(idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[get-profile] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[get-profile] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, presentations.NewUserProfile(user))
+}
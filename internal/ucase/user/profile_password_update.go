@@ -0,0 +1,120 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thedevsaddam/govalidator"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type profilePasswordUpdate struct {
+	userRepository repositories.User
+	keys           *jwtx.KeySet
+}
+
+func NewProfilePasswordUpdate(userRepository repositories.User, keys *jwtx.KeySet) contract.UseCase {
+	return &profilePasswordUpdate{
+		userRepository: userRepository,
+		keys:           keys,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *profilePasswordUpdate) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Change my password",
+		Payload: entity.PasswordChange{},
+		Result:  entity.TokenResponse{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *profilePasswordUpdate) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("update_password", request)
+	errorEvent := consts.ErrorEvent("update_password")
+	ctx := tracer.SpanStart(request.Context(), "update_password")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.PasswordChange{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[update-password] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data: &payload,
+		Rules: govalidator.MapData{
+			"current_password": []string{"required"},
+			"new_password":     []string{"required", "min:4"},
+		},
+	})
+	if ev := v.ValidateStruct(); len(ev) != 0 {
+		logger.Warn(logger.MessageFormat("[update-password] validate request param err: %s", util.DumpToString(ev)))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(util.DumpToString(ev)))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idUser := data.Request.Header.Get("idUser")
+	uuidUser, err := uuid.Parse(idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[update-password] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[update-password] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(payload.CurrentPassword))
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.CurrentPasswordNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(payload.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// other sessions are signed out, the caller continues with the returned token
+	// the jwt issued at claim is kept to the microsecond, so is the revocation time
+	err = u.userRepository.UpdatePassword(ctx, uuidUser, string(hashedPassword), time.Now())
+	if err != nil {
+		logger.Error(logger.MessageFormat("[update-password] %v", err))
+		err := errorEvent.WithMessage(consts.UpdateProfileErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	token, err := ucase.GenerateJWT(user, u.keys)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[update-password] %v", err))
+		err := errorEvent.WithCode(consts.CodeInternalServerError).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, token).WithSecret()
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/presentations"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
+
+	"github.com/google/uuid"
+	"github.com/thedevsaddam/govalidator"
+)
+
+type profileUpdate struct {
+	userRepository repositories.User
+}
+
+func NewProfileUpdate(userRepository repositories.User) contract.UseCase {
+	return &profileUpdate{
+		userRepository: userRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *profileUpdate) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Update my profile",
+		Payload: entity.ProfileUpdate{},
+		Result:  presentations.UserProfile{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *profileUpdate) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("update_profile", request)
+	errorEvent := consts.ErrorEvent("update_profile")
+	ctx := tracer.SpanStart(request.Context(), "update_profile")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.ProfileUpdate{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[update-profile] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data: &payload,
+		Rules: govalidator.MapData{
+			"name":             []string{"required", "between:3,50"},
+			"phone_number":     []string{"required", "digits_between:6,14"},
+			"bio":              []string{"max:500"},
+			"pickup_address":   []string{"max:255"},
+			"pickup_latitude":  []string{"lat"},
+			"pickup_longitude": []string{"lon"},
+		},
+	})
+	if ev := v.ValidateStruct(); len(ev) != 0 {
+		logger.Warn(logger.MessageFormat("[update-profile] validate request param err: %s", util.DumpToString(ev)))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(util.DumpToString(ev)))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idUser := data.Request.Header.Get("idUser")
+	uuidUser, err := uuid.Parse(idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[update-profile] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[update-profile] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user.Name = payload.Name
+	user.PhoneNumber = payload.PhoneNumber
+	user.Bio = payload.Bio
+	user.PickupAddress = payload.PickupAddress
+	user.PickupLatitude = payload.PickupLatitude
+	user.PickupLongitude = payload.PickupLongitude
+
+	err = u.userRepository.UpdateProfile(ctx, user)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[update-profile] %v", err))
+		err := errorEvent.WithMessage(consts.UpdateProfileErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err = u.userRepository.GetByID(ctx, uuidUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[update-profile] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, presentations.NewUserProfile(user))
+}
@@ -7,7 +7,10 @@ import (
 	"sharefood/internal/repositories"
 	"sharefood/internal/ucase"
 	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/jwtx"
 	"sharefood/pkg/logger"
+	"sharefood/pkg/mailer"
+	"sharefood/pkg/sms"
 	"sharefood/pkg/util"
 
 	"github.com/google/uuid"
@@ -16,18 +19,38 @@ import (
 )
 
 type userRegister struct {
-	userRepository repositories.User
+	userRepository                 repositories.User
+	phoneVerificationRepository    repositories.PhoneVerification
+	verificationThrottleRepository repositories.VerificationThrottle
+	mailer                         mailer.Mailer
+	sender                         sms.Sender
+	keys                           *jwtx.KeySet
 }
 
-func NewUserRegister(userRepository repositories.User) contract.UseCase {
+func NewUserRegister(userRepository repositories.User, phoneVerificationRepository repositories.PhoneVerification, verificationThrottleRepository repositories.VerificationThrottle, mailer mailer.Mailer, sender sms.Sender, keys *jwtx.KeySet) contract.UseCase {
 	return &userRegister{
-		userRepository: userRepository,
+		userRepository:                 userRepository,
+		phoneVerificationRepository:    phoneVerificationRepository,
+		verificationThrottleRepository: verificationThrottleRepository,
+		mailer:                         mailer,
+		sender:                         sender,
+		keys:                           keys,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *userRegister) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Register an account",
+		Payload: entity.UserRegister{},
+		Result:  entity.TokenResponse{},
+		Code:    consts.CodeCreated,
 	}
 }
 
 // Serve implements contract.UseCase
 func (u *userRegister) Serve(data *appctx.Data) appctx.Response {
-	payload := entity.User{}
+	payload := entity.UserRegister{}
 
 	err := data.Cast(&payload)
 	if err != nil {
@@ -36,7 +59,7 @@ func (u *userRegister) Serve(data *appctx.Data) appctx.Response {
 	}
 
 	fl := []logger.Field{
-		logger.Any("payload", payload),
+		logger.Any("email", payload.Email),
 	}
 
 	rules := govalidator.MapData{
@@ -77,24 +100,41 @@ func (u *userRegister) Serve(data *appctx.Data) appctx.Response {
 		return *appctx.NewResponse().WithStatus(consts.StatusFailed).WithEntity("registerUser").WithState("registerUserFailed").WithCode(consts.CodeBadRequest).WithError(err.Error())
 	}
 
-	//create hashedpass
-	payload.Password = string(hashedPassword)
+	user := entity.User{
+		ID:          uuid.New(),
+		Name:        payload.Name,
+		Email:       payload.Email,
+		PhoneNumber: payload.PhoneNumber,
+		Password:    string(hashedPassword),
+		Roles:       consts.DefaultRoles,
+	}
 
-	// create uuid
-	payload.ID = uuid.New()
-	secret := []byte(data.Config.App.JWTSecret)
-	token, errToken := ucase.GenerateJWT(payload, secret)
+	token, errToken := ucase.GenerateJWT(user, u.keys)
 	if errToken != nil {
 		logger.Error(logger.MessageFormat("[user-create] %v", err))
 		return *appctx.NewResponse().WithStatus(consts.StatusFailed).WithEntity("registerUser").WithState("registerUserFailed").WithCode(consts.CodeInternalServerError).WithError(errToken.Error())
 	}
 
 	// create the account
-	err = u.userRepository.Create(data.Request.Context(), &payload)
+	err = u.userRepository.Create(data.Request.Context(), &user)
 	if err != nil {
 		logger.Error(logger.MessageFormat("[user-create] %v", err))
 		return *appctx.NewResponse().WithStatus(consts.StatusFailed).WithEntity("registerUser").WithState("registerUserFailed").WithCode(consts.CodeInternalServerError).WithError(err.Error())
 	}
 
-	return *appctx.NewResponse().WithStatus(consts.StatusSuccess).WithEntity("registerUser").WithState("registerUserSuccess").WithCode(consts.CodeCreated).WithData(token)
+	// the account is usable right away, a failed or throttled delivery can be retried from the resend endpoints
+	guard := newVerificationGuard(data.Config, u.verificationThrottleRepository)
+	if err := guard.send(data.Request.Context(), consts.VerificationChannelEmail, user.ID, user.Email); err != nil {
+		logger.Warn(logger.MessageFormat("[user-create] email verification not sent: %v", err))
+	} else if err := sendEmailVerification(data.Request.Context(), data.Config, u.keys, u.mailer, user); err != nil {
+		logger.Error(logger.MessageFormat("[user-create] send email verification error: %v", err))
+	}
+
+	if err := guard.send(data.Request.Context(), consts.VerificationChannelPhone, user.ID, user.PhoneNumber); err != nil {
+		logger.Warn(logger.MessageFormat("[user-create] phone otp not sent: %v", err))
+	} else if err := sendPhoneOTP(data.Request.Context(), data.Config, u.phoneVerificationRepository, u.sender, user); err != nil {
+		logger.Error(logger.MessageFormat("[user-create] send phone otp error: %v", err))
+	}
+
+	return *appctx.NewResponse().WithStatus(consts.StatusSuccess).WithEntity("registerUser").WithState("registerUserSuccess").WithCode(consts.CodeCreated).WithData(token).WithSecret()
 }
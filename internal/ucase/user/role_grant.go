@@ -0,0 +1,98 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/presentations"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type roleGrant struct {
+	userRepository repositories.User
+	roleRepository repositories.Role
+}
+
+// NewRoleGrant grants a role to the account in the path, it shows in the token of the next sign in
+func NewRoleGrant(userRepository repositories.User, roleRepository repositories.Role) contract.UseCase {
+	return &roleGrant{
+		userRepository: userRepository,
+		roleRepository: roleRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *roleGrant) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Grant a role to a user",
+		Payload: entity.RoleInput{},
+		Result:  presentations.AdminUser{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *roleGrant) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("grant_role", request)
+	errorEvent := consts.ErrorEvent("grant_role")
+	ctx := tracer.SpanStart(request.Context(), "grant_role")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	idUser, err := uuid.Parse(mux.Vars(data.Request)["id"])
+	if err != nil {
+		logger.Error(logger.MessageFormat("[grant-role] parsing id error: %v", err))
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	payload := entity.RoleInput{}
+	err = data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[grant-role] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if _, ok := consts.RolePermissions[payload.Role]; !ok {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.RoleNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	grantedBy, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[grant-role] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[grant-role] %v", err))
+		err := errorEvent.WithMessage(consts.UserNotFoundMessage).WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.roleRepository.Grant(ctx, idUser, payload.Role, grantedBy)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[grant-role] %v", err))
+		err := errorEvent.WithMessage(consts.UpdateRoleErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !user.HasRole(payload.Role) {
+		user.Roles = append(user.Roles, payload.Role)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, presentations.NewAdminUser(user))
+}
@@ -0,0 +1,95 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/presentations"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type roleRevoke struct {
+	userRepository repositories.User
+	roleRepository repositories.Role
+}
+
+// NewRoleRevoke revokes a role from the account in the path and signs the account out
+func NewRoleRevoke(userRepository repositories.User, roleRepository repositories.Role) contract.UseCase {
+	return &roleRevoke{
+		userRepository: userRepository,
+		roleRepository: roleRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *roleRevoke) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Revoke a role of a user",
+		Result:  presentations.AdminUser{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *roleRevoke) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("revoke_role", request)
+	errorEvent := consts.ErrorEvent("revoke_role")
+	ctx := tracer.SpanStart(request.Context(), "revoke_role")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	params := mux.Vars(data.Request)
+	idUser, err := uuid.Parse(params["id"])
+	if err != nil {
+		logger.Error(logger.MessageFormat("[revoke-role] parsing id error: %v", err))
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	role := params["role"]
+	if _, ok := consts.RolePermissions[role]; !ok {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.RoleNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// an admin taking away their own admin role could lock everyone out of the user management
+	if role == consts.RoleAdmin && data.Request.Header.Get("idUser") == idUser.String() {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.ActionRequestNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[revoke-role] %v", err))
+		err := errorEvent.WithMessage(consts.UserNotFoundMessage).WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// the jwt issued at claim is kept to the microsecond, so is the revocation time
+	err = u.roleRepository.Revoke(ctx, idUser, role, time.Now())
+	if err != nil {
+		logger.Error(logger.MessageFormat("[revoke-role] %v", err))
+		err := errorEvent.WithMessage(consts.UpdateRoleErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	roles := make([]string, 0, len(user.Roles))
+	for _, r := range user.Roles {
+		if r != role {
+			roles = append(roles, r)
+		}
+	}
+	user.Roles = roles
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, presentations.NewAdminUser(user))
+}
@@ -0,0 +1,118 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/presentations"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type userSuspend struct {
+	userRepository repositories.User
+	suspend        bool
+}
+
+// NewUserSuspend suspends the account in the path, a suspended account can no longer sign in
+func NewUserSuspend(userRepository repositories.User) contract.UseCase {
+	return &userSuspend{
+		userRepository: userRepository,
+		suspend:        true,
+	}
+}
+
+// NewUserUnsuspend lifts the suspension of the account in the path
+func NewUserUnsuspend(userRepository repositories.User) contract.UseCase {
+	return &userSuspend{
+		userRepository: userRepository,
+		suspend:        false,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *userSuspend) Doc() contract.Doc {
+	summary := "Lift the suspension of a user"
+	if u.suspend {
+		summary = "Suspend a user"
+	}
+
+	return contract.Doc{
+		Summary: summary,
+		Result:  presentations.AdminUser{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *userSuspend) Serve(data *appctx.Data) appctx.Response {
+	event := "unsuspend_user"
+	if u.suspend {
+		event = "suspend_user"
+	}
+
+	request := data.Request
+	response := response.NewResponse(event, request)
+	errorEvent := consts.ErrorEvent(event)
+	ctx := tracer.SpanStart(request.Context(), event)
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	idUser, err := uuid.Parse(mux.Vars(data.Request)["id"])
+	if err != nil {
+		logger.Error(logger.MessageFormat("[user-suspend] parsing id error: %v", err))
+		err := errorEvent.WithMessage(consts.IdNotValidMessage).WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if u.suspend && data.Request.Header.Get("idUser") == idUser.String() {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.ActionRequestNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	// an account is only managed by one carrying at least its management permissions
+	target, err := u.userRepository.GetByID(ctx, idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[user-suspend] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	claims, _ := ctx.Value(consts.CtxTokenClaims).(entity.TokenClaims)
+	if !claims.Outranks(target.Roles) {
+		logger.Warn(logger.MessageFormat("[user-suspend] %s does not carry the permissions of %s", claims.ID, idUser))
+		err := errorEvent.WithCode(consts.CodeForbidden).WrapError(consts.Error(consts.StatusForbidden))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	var suspendedAt *time.Time
+	if u.suspend {
+		now := time.Now()
+		suspendedAt = &now
+	}
+
+	err = u.userRepository.SetSuspended(ctx, idUser, suspendedAt)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[user-suspend] %v", err))
+		err := errorEvent.WithMessage(consts.UpdateUserErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[user-suspend] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, presentations.NewAdminUser(user))
+}
@@ -0,0 +1,70 @@
+// Package user
+package user
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSuspend_Serve(t *testing.T) {
+	moderator := entity.TokenClaims{ID: uuid.New(), Roles: []string{consts.RoleModerator}}
+
+	serve := func(claims entity.TokenClaims, target entity.User) (appctx.Response, *fakeUserRepository) {
+		users := &fakeUserRepository{users: map[uuid.UUID]entity.User{target.ID: target}}
+
+		req := httptest.NewRequest("POST", "/users/"+target.ID.String()+"/suspend", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": target.ID.String()})
+		req = req.WithContext(context.WithValue(req.Context(), consts.CtxTokenClaims, claims))
+		req.Header.Set("idUser", claims.ID.String())
+
+		return NewUserSuspend(users).Serve(&appctx.Data{Request: req, Config: &appctx.Config{}}), users
+	}
+
+	t.Run("test suspend a user", func(t *testing.T) {
+		target := entity.User{ID: uuid.New(), Roles: []string{consts.RoleReceiver, consts.RoleGiver}}
+
+		result, users := serve(moderator, target)
+
+		assert.Equal(t, consts.CodeSuccess, result.Code)
+		assert.NotNil(t, users.users[target.ID].SuspendedAt)
+	})
+
+	t.Run("test suspend a peer", func(t *testing.T) {
+		target := entity.User{ID: uuid.New(), Roles: []string{consts.RoleModerator}}
+
+		result, _ := serve(moderator, target)
+
+		assert.Equal(t, consts.CodeSuccess, result.Code)
+	})
+
+	t.Run("test suspend an admin", func(t *testing.T) {
+		target := entity.User{ID: uuid.New(), Roles: []string{consts.RoleAdmin}}
+
+		result, users := serve(moderator, target)
+
+		assert.Equal(t, consts.CodeForbidden, result.Code)
+		assert.Nil(t, users.users[target.ID].SuspendedAt)
+	})
+
+	t.Run("test unsuspend an admin", func(t *testing.T) {
+		target := entity.User{ID: uuid.New(), Roles: []string{consts.RoleAdmin}}
+
+		users := &fakeUserRepository{users: map[uuid.UUID]entity.User{target.ID: target}}
+		req := httptest.NewRequest("POST", "/users/"+target.ID.String()+"/unsuspend", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": target.ID.String()})
+		req = req.WithContext(context.WithValue(req.Context(), consts.CtxTokenClaims, moderator))
+
+		result := NewUserUnsuspend(users).Serve(&appctx.Data{Request: req, Config: &appctx.Config{}})
+
+		assert.Equal(t, consts.CodeForbidden, result.Code)
+	})
+}
@@ -0,0 +1,87 @@
+package user
+
+import (
+	"context"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/hash"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/totp"
+	"sharefood/pkg/util"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"github.com/google/uuid"
+)
+
+// newTwoFactorChallenge signs the challenge the code step of the login is made against,
+// its audience keeps it from being accepted as an access token
+func newTwoFactorChallenge(user entity.User, keys *jwtx.KeySet) (entity.TwoFactorChallenge, error) {
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(consts.TwoFactorChallengeTTLMinute * time.Minute)
+
+	token, err := keys.Sign(entity.TwoFactorChallengeClaims{
+		ID: user.ID,
+		StandardClaims: jwt.StandardClaims{
+			ID:        uuid.New().String(),
+			Audience:  jwt.ClaimStrings{consts.TwoFactorChallengeAudience},
+			ExpiresAt: jwt.At(expiredAt),
+			IssuedAt:  jwt.At(issuedAt),
+		},
+	})
+	if err != nil {
+		return entity.TwoFactorChallenge{}, err
+	}
+
+	return entity.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiredAt:         expiredAt,
+	}, nil
+}
+
+func parseTwoFactorChallenge(raw string, keys *jwtx.KeySet) (entity.TwoFactorChallengeClaims, error) {
+	claims := entity.TwoFactorChallengeClaims{}
+	token, err := keys.Parse(raw, &claims, jwt.WithAudience(consts.TwoFactorChallengeAudience))
+	if err != nil || !token.Valid || claims.StandardClaims.ID == "" {
+		return claims, consts.Error(consts.TwoFactorChallengeNotValid)
+	}
+
+	return claims, nil
+}
+
+// verifyTwoFactorCode checks the code against the secret, a code is only accepted once
+func verifyTwoFactorCode(ctx context.Context, twoFactorRepository repositories.TwoFactor, idUser uuid.UUID, secret string, code string) (bool, error) {
+	step, ok := totp.Validate(secret, code, time.Now(), consts.TwoFactorSkewStep)
+	if !ok {
+		return false, nil
+	}
+
+	// long enough for the step to fall out of the accepted window
+	ttl := time.Duration(2*consts.TwoFactorSkewStep+1) * totp.Period
+
+	return twoFactorRepository.UseCode(ctx, idUser, step, ttl)
+}
+
+// newRecoveryCodes generates the recovery codes shown to the user and the hashes stored of them
+func newRecoveryCodes() (codes []string, hashes []string, err error) {
+	for i := 0; i < consts.TwoFactorRecoveryCodeCount; i++ {
+		token, err := util.GenerateSecureToken(consts.TwoFactorRecoveryCodeBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes = append(codes, token[:len(token)/2]+"-"+token[len(token)/2:])
+		hashes = append(hashes, hashRecoveryCode(token))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes the code the way it is stored, with or without its dash and in any case
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hash.SHA256(code)
+}
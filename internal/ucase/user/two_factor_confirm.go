@@ -0,0 +1,120 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type twoFactorConfirm struct {
+	userRepository      repositories.User
+	twoFactorRepository repositories.TwoFactor
+}
+
+// NewTwoFactorConfirm enables two-factor authentication with a code of the enrolled secret
+// and hands out the recovery codes
+func NewTwoFactorConfirm(userRepository repositories.User, twoFactorRepository repositories.TwoFactor) contract.UseCase {
+	return &twoFactorConfirm{
+		userRepository:      userRepository,
+		twoFactorRepository: twoFactorRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *twoFactorConfirm) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Confirm the two-factor enrollment",
+		Payload: entity.TwoFactorConfirm{},
+		Result:  entity.TwoFactorRecoveryCodes{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *twoFactorConfirm) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("confirm_two_factor", request)
+	errorEvent := consts.ErrorEvent("confirm_two_factor")
+	ctx := tracer.SpanStart(request.Context(), "confirm_two_factor")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.TwoFactorConfirm{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[confirm-two-factor] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idUser, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[confirm-two-factor] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[confirm-two-factor] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if user.HasTwoFactor() {
+		err := errorEvent.WithCode(consts.CodeDuplicateEntry).WrapError(consts.Error(consts.TwoFactorAlreadyEnabled))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	secret, err := u.twoFactorRepository.GetSecret(ctx, user.ID)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[confirm-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if secret == "" {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.TwoFactorNotEnrolled))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	valid, err := verifyTwoFactorCode(ctx, u.twoFactorRepository, user.ID, secret, payload.Code)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[confirm-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !valid {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.TwoFactorCodeNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		logger.Error(logger.MessageFormat("[confirm-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.twoFactorRepository.Enable(ctx, user.ID, time.Now(), hashes)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[confirm-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, entity.TwoFactorRecoveryCodes{
+		RecoveryCodes: codes,
+	}).WithSecret()
+}
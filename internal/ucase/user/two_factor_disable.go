@@ -0,0 +1,110 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type twoFactorDisable struct {
+	userRepository      repositories.User
+	twoFactorRepository repositories.TwoFactor
+}
+
+// NewTwoFactorDisable turns two-factor authentication off, it takes the password and a current code
+func NewTwoFactorDisable(userRepository repositories.User, twoFactorRepository repositories.TwoFactor) contract.UseCase {
+	return &twoFactorDisable{
+		userRepository:      userRepository,
+		twoFactorRepository: twoFactorRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *twoFactorDisable) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Disable two-factor authentication",
+		Payload: entity.TwoFactorDisable{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *twoFactorDisable) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("disable_two_factor", request)
+	errorEvent := consts.ErrorEvent("disable_two_factor")
+	ctx := tracer.SpanStart(request.Context(), "disable_two_factor")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	payload := entity.TwoFactorDisable{}
+	err := data.Cast(&payload)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[disable-two-factor] parsing body request error: %v", err))
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	idUser, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[disable-two-factor] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[disable-two-factor] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !user.HasTwoFactor() {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.TwoFactorNotEnabled))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(payload.Password))
+	if err != nil {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.CurrentPasswordNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	secret, err := u.twoFactorRepository.GetSecret(ctx, user.ID)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[disable-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	valid, err := verifyTwoFactorCode(ctx, u.twoFactorRepository, user.ID, secret, payload.Code)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[disable-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if !valid {
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(consts.Error(consts.TwoFactorCodeNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.twoFactorRepository.Disable(ctx, user.ID)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[disable-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
@@ -0,0 +1,63 @@
+// Package user
+package user
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/totp"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestTwoFactorDisable_Serve(t *testing.T) {
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("rahasia123"), bcrypt.MinCost)
+	secret, _ := totp.GenerateSecret()
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	enabledAt := time.Now()
+	account := entity.User{ID: uuid.New(), Email: "budi@example.com", Password: string(hashed), TwoFactorEnabledAt: &enabledAt}
+
+	serve := func(target string, body string) (appctx.Response, *fakeTwoFactorRepository) {
+		twoFactor := newFakeTwoFactorRepository()
+		twoFactor.secrets[account.ID] = secret
+
+		users := &fakeUserRepository{users: map[uuid.UUID]entity.User{account.ID: account}}
+		svc := NewTwoFactorDisable(users, twoFactor)
+
+		req := httptest.NewRequest("DELETE", target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(consts.HeaderContentTypeKey, consts.HeaderContentTypeJSON)
+		}
+		req.Header.Set("idUser", account.ID.String())
+
+		return svc.Serve(&appctx.Data{Request: req, Config: &appctx.Config{}, ServiceType: consts.ServiceTypeHTTP}), twoFactor
+	}
+
+	t.Run("test password and code in the body", func(t *testing.T) {
+		result, twoFactor := serve("/me/2fa", `{"password":"rahasia123","code":"`+code+`"}`)
+
+		assert.Equal(t, consts.CodeSuccess, result.Code)
+		assert.Equal(t, []uuid.UUID{account.ID}, twoFactor.disabled)
+	})
+
+	t.Run("test wrong password", func(t *testing.T) {
+		result, twoFactor := serve("/me/2fa", `{"password":"salah","code":"`+code+`"}`)
+
+		assert.Equal(t, consts.CodeUnprocessableEntity, result.Code)
+		assert.Empty(t, twoFactor.disabled)
+	})
+
+	t.Run("test password in the query string", func(t *testing.T) {
+		result, twoFactor := serve("/me/2fa?password=rahasia123&code="+code, "")
+
+		assert.NotEqual(t, consts.CodeSuccess, result.Code)
+		assert.Empty(t, twoFactor.disabled)
+	})
+}
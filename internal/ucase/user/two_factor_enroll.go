@@ -0,0 +1,87 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/totp"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+)
+
+type twoFactorEnroll struct {
+	userRepository      repositories.User
+	twoFactorRepository repositories.TwoFactor
+}
+
+// NewTwoFactorEnroll starts a totp enrollment, it only takes effect once a code of the new secret is confirmed
+func NewTwoFactorEnroll(userRepository repositories.User, twoFactorRepository repositories.TwoFactor) contract.UseCase {
+	return &twoFactorEnroll{
+		userRepository:      userRepository,
+		twoFactorRepository: twoFactorRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *twoFactorEnroll) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Start the two-factor enrollment",
+		Result:  entity.TwoFactorEnrollment{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *twoFactorEnroll) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("enroll_two_factor", request)
+	errorEvent := consts.ErrorEvent("enroll_two_factor")
+	ctx := tracer.SpanStart(request.Context(), "enroll_two_factor")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	idUser, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[enroll-two-factor] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	user, err := u.userRepository.GetByID(ctx, idUser)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[enroll-two-factor] %v", err))
+		err := errorEvent.WithCode(consts.CodeNotFound).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	if user.HasTwoFactor() {
+		err := errorEvent.WithCode(consts.CodeDuplicateEntry).WrapError(consts.Error(consts.TwoFactorAlreadyEnabled))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logger.Error(logger.MessageFormat("[enroll-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.twoFactorRepository.SaveSecret(ctx, user.ID, secret)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[enroll-two-factor] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, entity.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(data.Config.App.AppName, user.Email, secret),
+	}).WithSecret()
+}
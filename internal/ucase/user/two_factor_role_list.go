@@ -0,0 +1,53 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+)
+
+type twoFactorRoleList struct {
+	roleRepository repositories.Role
+}
+
+// NewTwoFactorRoleList lists the roles requiring two-factor authentication
+func NewTwoFactorRoleList(roleRepository repositories.Role) contract.UseCase {
+	return &twoFactorRoleList{
+		roleRepository: roleRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *twoFactorRoleList) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "List the roles requiring two-factor authentication",
+		Result:  []entity.TwoFactorRole{},
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *twoFactorRoleList) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("list_two_factor_roles", request)
+	errorEvent := consts.ErrorEvent("list_two_factor_roles")
+	ctx := tracer.SpanStart(request.Context(), "list_two_factor_roles")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	roles, err := u.roleRepository.ListTwoFactor(ctx)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[list-two-factor-roles] %v", err))
+		err := errorEvent.WithMessage(consts.TwoFactorErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, roles)
+}
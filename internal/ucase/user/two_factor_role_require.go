@@ -0,0 +1,68 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type twoFactorRoleRequire struct {
+	roleRepository repositories.Role
+}
+
+// NewTwoFactorRoleRequire requires two-factor authentication for the role in the path, holders without it
+// keep their account but lose the permissions of the role until they enable it
+func NewTwoFactorRoleRequire(roleRepository repositories.Role) contract.UseCase {
+	return &twoFactorRoleRequire{
+		roleRepository: roleRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *twoFactorRoleRequire) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Require two-factor authentication for a role",
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *twoFactorRoleRequire) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("require_two_factor_role", request)
+	errorEvent := consts.ErrorEvent("require_two_factor_role")
+	ctx := tracer.SpanStart(request.Context(), "require_two_factor_role")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	role := mux.Vars(data.Request)["role"]
+	if _, ok := consts.RolePermissions[role]; !ok {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.RoleNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	enforcedBy, err := uuid.Parse(data.Request.Header.Get("idUser"))
+	if err != nil {
+		logger.Error(logger.MessageFormat("[require-two-factor-role] parsing id error: %v", err))
+		err := errorEvent.WithCode(consts.CodeUnprocessableEntity).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err = u.roleRepository.RequireTwoFactor(ctx, role, enforcedBy)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[require-two-factor-role] %v", err))
+		err := errorEvent.WithMessage(consts.UpdateRoleErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
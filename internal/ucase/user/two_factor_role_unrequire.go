@@ -0,0 +1,59 @@
+package user
+
+import (
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+	"sharefood/internal/response"
+	"sharefood/internal/ucase/contract"
+	"sharefood/pkg/logger"
+	"sharefood/pkg/requestid"
+	"sharefood/pkg/tracer"
+
+	"github.com/gorilla/mux"
+)
+
+type twoFactorRoleUnrequire struct {
+	roleRepository repositories.Role
+}
+
+// NewTwoFactorRoleUnrequire stops requiring two-factor authentication for the role in the path
+func NewTwoFactorRoleUnrequire(roleRepository repositories.Role) contract.UseCase {
+	return &twoFactorRoleUnrequire{
+		roleRepository: roleRepository,
+	}
+}
+
+// Doc implements contract.Documented
+func (u *twoFactorRoleUnrequire) Doc() contract.Doc {
+	return contract.Doc{
+		Summary: "Stop requiring two-factor authentication for a role",
+		Code:    consts.CodeSuccess,
+	}
+}
+
+// Serve implements contract.UseCase
+func (u *twoFactorRoleUnrequire) Serve(data *appctx.Data) appctx.Response {
+	request := data.Request
+	response := response.NewResponse("unrequire_two_factor_role", request)
+	errorEvent := consts.ErrorEvent("unrequire_two_factor_role")
+	ctx := tracer.SpanStart(request.Context(), "unrequire_two_factor_role")
+	defer tracer.SpanFinish(ctx)
+
+	transactionID := requestid.Get(ctx)
+
+	role := mux.Vars(data.Request)["role"]
+	if _, ok := consts.RolePermissions[role]; !ok {
+		err := errorEvent.WithCode(consts.CodeBadRequest).WrapError(consts.Error(consts.RoleNotValid))
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	err := u.roleRepository.UnrequireTwoFactor(ctx, role)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[unrequire-two-factor-role] %v", err))
+		err := errorEvent.WithMessage(consts.UpdateRoleErrorMessage).WrapError(err)
+		return *response.Failed(ctx, &transactionID, err)
+	}
+
+	return *response.Success(ctx, consts.CodeSuccess, &transactionID, nil)
+}
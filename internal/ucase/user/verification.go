@@ -0,0 +1,93 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/hash"
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/mailer"
+	"sharefood/pkg/sms"
+	"sharefood/pkg/util"
+	"time"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"github.com/google/uuid"
+)
+
+// sendEmailVerification mails a verification link to the user email, signed by the keys of the access tokens
+func sendEmailVerification(ctx context.Context, cfg *appctx.Config, keys *jwtx.KeySet, mail mailer.Mailer, user entity.User) error {
+	ttl := cfg.Auth.EmailVerificationTTLHour
+	if ttl < 1 {
+		ttl = consts.EmailVerificationTTLHourDefault
+	}
+
+	// the audience keeps the link from being accepted as a bearer token
+	signedToken, err := keys.Sign(entity.EmailVerificationClaims{
+		ID:    user.ID,
+		Email: user.Email,
+		StandardClaims: jwt.StandardClaims{
+			Audience:  jwt.ClaimStrings{consts.EmailVerificationAudience},
+			ExpiresAt: jwt.At(time.Now().Add(time.Duration(ttl) * time.Hour)),
+			IssuedAt:  jwt.At(time.Now()),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return mail.Send(ctx, mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Verify your Sharefood email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below. The link expires in %d hours.\n\n%s?token=%s\n",
+			user.Name, ttl, cfg.Auth.EmailVerificationURL, signedToken,
+		),
+	})
+}
+
+// parseEmailVerification validates the signed token of a verification link
+func parseEmailVerification(keys *jwtx.KeySet, signedToken string) (entity.EmailVerificationClaims, error) {
+	claims := entity.EmailVerificationClaims{}
+	token, err := keys.Parse(signedToken, &claims, jwt.WithAudience(consts.EmailVerificationAudience))
+	if err != nil || !token.Valid {
+		return claims, consts.Error(consts.VerificationTokenNotValid)
+	}
+
+	return claims, nil
+}
+
+// sendPhoneOTP stores a new one-time code and texts it to the user phone number
+func sendPhoneOTP(ctx context.Context, cfg *appctx.Config, repo repositories.PhoneVerification, sender sms.Sender, user entity.User) error {
+	ttl := cfg.Auth.PhoneOTPTTLMinute
+	if ttl < 1 {
+		ttl = consts.PhoneOTPTTLMinuteDefault
+	}
+
+	code, err := util.GenerateSecureNumberString(consts.PhoneOTPLength)
+	if err != nil {
+		return err
+	}
+
+	verification := entity.PhoneVerification{
+		ID:          uuid.New(),
+		IDUser:      user.ID,
+		PhoneNumber: user.PhoneNumber,
+		CodeHash:    phoneOTPHash(user.ID, code),
+		ExpiredAt:   time.Now().Local().Add(time.Duration(ttl) * time.Minute),
+	}
+
+	if err := repo.Create(ctx, &verification); err != nil {
+		return err
+	}
+
+	return sender.Send(ctx, user.PhoneNumber, fmt.Sprintf("Your Sharefood verification code is %s. It expires in %d minutes.", code, ttl))
+}
+
+// phoneOTPHash binds the code to the user so equal codes hash differently
+func phoneOTPHash(idUser uuid.UUID, code string) string {
+	return hash.Hmac256(code, idUser.String())
+}
@@ -0,0 +1,98 @@
+package user
+
+import (
+	"context"
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/hash"
+	"sharefood/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// verificationWindow the daily caps are counted over
+const verificationWindow = 24 * time.Hour
+
+// verificationGuard paces the codes and links sent per user and per destination, so a resend neither
+// floods a phone number nor hands out a fresh guessing budget. Redis errors let the request through
+// as the login guard does
+type verificationGuard struct {
+	verificationThrottleRepository repositories.VerificationThrottle
+	cooldown                       time.Duration
+	maxSendDaily                   int64
+	maxAttemptDaily                int64
+}
+
+func newVerificationGuard(cfg *appctx.Config, verificationThrottleRepository repositories.VerificationThrottle) verificationGuard {
+	guard := verificationGuard{
+		verificationThrottleRepository: verificationThrottleRepository,
+		cooldown:                       time.Duration(cfg.Auth.VerificationResendCooldownSecond) * time.Second,
+		maxSendDaily:                   int64(cfg.Auth.VerificationSendMaxDaily),
+		maxAttemptDaily:                int64(cfg.Auth.PhoneOTPMaxAttemptDaily),
+	}
+
+	if guard.cooldown < time.Second {
+		guard.cooldown = consts.VerificationResendCooldownSecondDefault * time.Second
+	}
+	if guard.maxSendDaily < 1 {
+		guard.maxSendDaily = consts.VerificationSendMaxDailyDefault
+	}
+	if guard.maxAttemptDaily < 1 {
+		guard.maxAttemptDaily = consts.PhoneOTPMaxAttemptDailyDefault
+	}
+
+	return guard
+}
+
+// the destination is hashed so the redis keys do not hold addresses and numbers in the clear
+func verificationKeys(channel string, idUser uuid.UUID, destination string) []string {
+	return []string{
+		channel + ":user:" + idUser.String(),
+		channel + ":to:" + hash.SHA256(destination),
+	}
+}
+
+// send tells whether a code or link may be sent on the channel to the user destination, the error
+// says why not
+func (g verificationGuard) send(ctx context.Context, channel string, idUser uuid.UUID, destination string) error {
+	keys := verificationKeys(channel, idUser, destination)
+	for _, key := range keys {
+		started, err := g.verificationThrottleRepository.Cooldown(ctx, key, g.cooldown)
+		if err != nil {
+			logger.Error(logger.MessageFormat("[verification] cooldown error: %v", err))
+			continue
+		}
+
+		if !started {
+			return consts.Error(consts.VerificationTooSoon)
+		}
+	}
+
+	for _, key := range keys {
+		sent, err := g.verificationThrottleRepository.Count(ctx, key, verificationWindow)
+		if err != nil {
+			logger.Error(logger.MessageFormat("[verification] count error: %v", err))
+			continue
+		}
+
+		if sent > g.maxSendDaily {
+			return consts.Error(consts.VerificationSendExhausted)
+		}
+	}
+
+	return nil
+}
+
+// attempt counts a guess at the phone code of the user, false once the daily budget of the user is
+// spent whatever the codes sent
+func (g verificationGuard) attempt(ctx context.Context, idUser uuid.UUID) bool {
+	tries, err := g.verificationThrottleRepository.Count(ctx, consts.VerificationChannelPhone+":attempt:"+idUser.String(), verificationWindow)
+	if err != nil {
+		logger.Error(logger.MessageFormat("[verification] count attempt error: %v", err))
+		return true
+	}
+
+	return tries <= g.maxAttemptDaily
+}
@@ -0,0 +1,102 @@
+// Package user
+package user
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/internal/repositories"
+	"sharefood/pkg/cache"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newVerificationThrottle(t *testing.T) (*miniredis.Miniredis, repositories.VerificationThrottle) {
+	srv := miniredis.RunT(t)
+	return srv, repositories.NewVerificationThrottleRepository(cache.NewCache(redis.NewClient(&redis.Options{Addr: srv.Addr()})))
+}
+
+func TestVerificationGuard_Send(t *testing.T) {
+	ctx := context.Background()
+	cfg := &appctx.Config{}
+	cfg.Auth.VerificationSendMaxDaily = 2
+
+	t.Run("test cooldown per user", func(t *testing.T) {
+		_, throttle := newVerificationThrottle(t)
+		guard := newVerificationGuard(cfg, throttle)
+		idUser := uuid.New()
+
+		assert.NoError(t, guard.send(ctx, consts.VerificationChannelPhone, idUser, "+6281100000001"))
+		assert.Equal(t, consts.Error(consts.VerificationTooSoon), guard.send(ctx, consts.VerificationChannelPhone, idUser, "+6281100000002"))
+		// the channels cool down on their own
+		assert.NoError(t, guard.send(ctx, consts.VerificationChannelEmail, idUser, "budi@example.com"))
+	})
+
+	t.Run("test cooldown per destination", func(t *testing.T) {
+		_, throttle := newVerificationThrottle(t)
+		guard := newVerificationGuard(cfg, throttle)
+
+		assert.NoError(t, guard.send(ctx, consts.VerificationChannelPhone, uuid.New(), "+6281100000001"))
+		assert.Equal(t, consts.Error(consts.VerificationTooSoon), guard.send(ctx, consts.VerificationChannelPhone, uuid.New(), "+6281100000001"))
+	})
+
+	t.Run("test daily cap", func(t *testing.T) {
+		srv, throttle := newVerificationThrottle(t)
+		guard := newVerificationGuard(cfg, throttle)
+		idUser := uuid.New()
+
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, guard.send(ctx, consts.VerificationChannelPhone, idUser, "+6281100000001"))
+			srv.FastForward(time.Minute)
+		}
+
+		assert.Equal(t, consts.Error(consts.VerificationSendExhausted), guard.send(ctx, consts.VerificationChannelPhone, idUser, "+6281100000001"))
+
+		srv.FastForward(24 * time.Hour)
+		assert.NoError(t, guard.send(ctx, consts.VerificationChannelPhone, idUser, "+6281100000001"))
+	})
+}
+
+func TestVerificationGuard_Attempt(t *testing.T) {
+	_, throttle := newVerificationThrottle(t)
+	cfg := &appctx.Config{}
+	cfg.Auth.PhoneOTPMaxAttemptDaily = 3
+	guard := newVerificationGuard(cfg, throttle)
+	idUser := uuid.New()
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, guard.attempt(context.Background(), idUser))
+	}
+
+	assert.False(t, guard.attempt(context.Background(), idUser))
+	assert.True(t, guard.attempt(context.Background(), uuid.New()))
+}
+
+func TestPhoneOTPSend_Serve(t *testing.T) {
+	account := entity.User{ID: uuid.New(), PhoneNumber: "+6281100000001"}
+	_, throttle := newVerificationThrottle(t)
+	verifications, sender := &fakePhoneVerificationRepository{}, &fakeSender{}
+	svc := NewPhoneOTPSend(&fakeUserRepository{users: map[uuid.UUID]entity.User{account.ID: account}}, verifications, throttle, sender)
+
+	serve := func() appctx.Response {
+		req := httptest.NewRequest("POST", "/user/verify/phone/send", nil)
+		req.Header.Set("idUser", account.ID.String())
+
+		return svc.Serve(&appctx.Data{Request: req, Config: &appctx.Config{}, ServiceType: consts.ServiceTypeHTTP})
+	}
+
+	assert.Equal(t, consts.CodeSuccess, serve().Code)
+
+	// a resend right away neither texts again nor issues a new code with new attempts
+	assert.Equal(t, consts.CodeReachMaxLimit, serve().Code)
+	assert.Len(t, sender.sent, 1)
+	assert.Len(t, verifications.created, 1)
+}
@@ -0,0 +1,66 @@
+// Package user
+package user
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"sharefood/internal/appctx"
+	"sharefood/internal/consts"
+	"sharefood/internal/entity"
+	"sharefood/pkg/jwtx"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailVerification(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	signing, _ := jwtx.NewSigningKey("test", private)
+	keys, _ := jwtx.NewKeySet(signing)
+
+	account := entity.User{ID: uuid.New(), Name: "Budi", Email: "budi@example.com"}
+
+	t.Run("test link signed by the key set", func(t *testing.T) {
+		mail := &fakeMailer{}
+
+		err := sendEmailVerification(context.Background(), &appctx.Config{}, keys, mail, account)
+		assert.NoError(t, err)
+		assert.Len(t, mail.sent, 1)
+
+		body := strings.TrimSpace(mail.sent[0].Body)
+		claims, err := parseEmailVerification(keys, body[strings.LastIndex(body, "token=")+len("token="):])
+
+		assert.NoError(t, err)
+		assert.Equal(t, account.ID, claims.ID)
+		assert.Equal(t, account.Email, claims.Email)
+	})
+
+	t.Run("test token signed with an empty hmac key", func(t *testing.T) {
+		forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, entity.EmailVerificationClaims{
+			ID:    account.ID,
+			Email: account.Email,
+			StandardClaims: jwt.StandardClaims{
+				Audience:  jwt.ClaimStrings{consts.EmailVerificationAudience},
+				ExpiresAt: jwt.At(time.Now().Add(time.Hour)),
+			},
+		}).SignedString([]byte(""))
+
+		_, err := parseEmailVerification(keys, forged)
+
+		assert.Equal(t, consts.Error(consts.VerificationTokenNotValid), err)
+	})
+
+	t.Run("test token of another audience", func(t *testing.T) {
+		challenge, _ := newTwoFactorChallenge(account, keys)
+
+		_, err := parseEmailVerification(keys, challenge.ChallengeToken)
+
+		assert.Equal(t, consts.Error(consts.VerificationTokenNotValid), err)
+	})
+}
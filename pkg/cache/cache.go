@@ -14,11 +14,23 @@ const (
 	cacheNil string = `redis: nil`
 )
 
+// deleteIfEqual deletes the key only while it still holds the value
+var deleteIfEqual = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
 // AgentCache contract
 type Cacher interface {
 	Get(ctx context.Context, key string) ([]byte, error)
 	Set(ctx context.Context, key string, val interface{}, duration time.Duration) error
 	Delete(ctx context.Context, key ...string) error
+	Increment(ctx context.Context, key string, duration time.Duration) (int64, error)
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	SetNX(ctx context.Context, key string, val interface{}, duration time.Duration) (bool, error)
+	DeleteIfEqual(ctx context.Context, key string, val string) (bool, error)
 }
 
 type cache struct {
@@ -55,3 +67,50 @@ func (c *cache) Delete(ctx context.Context, key ...string) error {
 	cmd := c.rds.Del(ctx, key...)
 	return cmd.Err()
 }
+
+// SetNX sets the key only when it does not exist yet, reports whether it was set
+func (c *cache) SetNX(ctx context.Context, key string, val interface{}, exp time.Duration) (bool, error) {
+	return c.rds.SetNX(ctx, key, val, exp).Result()
+}
+
+// DeleteIfEqual deletes the key when it holds val, in one step so a key set again by someone
+// else in between is left alone. Reports whether it was deleted
+func (c *cache) DeleteIfEqual(ctx context.Context, key string, val string) (bool, error) {
+	n, err := deleteIfEqual.Run(ctx, c.rds, []string{key}, val).Int64()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// Increment the counter under key, the expiry starts with the first increment so the counter
+// counts within a fixed window. The counter is created with its expiry and incremented in one
+// transaction, it never lives on without one
+func (c *cache) Increment(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := c.rds.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, key, 0, exp)
+		incr = pipe.Incr(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+// TTL remaining time to live of key, zero when the key does not exist or never expires
+func (c *cache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := c.rds.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncrement(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	c := NewCache(redis.NewClient(&redis.Options{Addr: srv.Addr()}))
+
+	for i := int64(1); i <= 3; i++ {
+		n, err := c.Increment(ctx, "counter", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, i, n)
+	}
+
+	// the window starts with the first increment
+	assert.Equal(t, time.Minute, srv.TTL("counter"))
+
+	srv.FastForward(time.Minute)
+	n, err := c.Increment(ctx, "counter", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.Equal(t, time.Minute, srv.TTL("counter"))
+}
+
+func TestDeleteIfEqual(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	c := NewCache(redis.NewClient(&redis.Options{Addr: srv.Addr()}))
+
+	assert.NoError(t, c.Set(ctx, "lock", "token-2", time.Minute))
+
+	deleted, err := c.DeleteIfEqual(ctx, "lock", "token-1")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.True(t, srv.Exists("lock"))
+
+	deleted, err = c.DeleteIfEqual(ctx, "lock", "token-2")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.False(t, srv.Exists("lock"))
+
+	deleted, err = c.DeleteIfEqual(ctx, "lock", "token-2")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+}
@@ -3,82 +3,85 @@ package cache
 
 import (
 	"context"
-	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/go-redis/redis/v8"
-	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
-	"github.com/opentracing/opentracing-go/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"sharefood/pkg/tracer"
 )
 
 type redisHook struct {
-	addrs []string
-	db    int
+	attrs []attribute.KeyValue
 }
 
 func NewRedisHook(addrs []string, db int) redis.Hook {
 	return &redisHook{
-		addrs: addrs,
-		db:    db,
+		attrs: []attribute.KeyValue{
+			semconv.DBSystemRedis,
+			semconv.DBRedisDBIndexKey.Int(db),
+			semconv.NetPeerNameKey.String(strings.Join(addrs, ", ")),
+		},
 	}
 }
 
 func (h *redisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	ctx, _ = tracer.Tracer().Start(ctx, "redis."+cmd.Name(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(h.attrs...),
+		trace.WithAttributes(semconv.DBStatementKey.String(strings.ToUpper(cmd.Name()))),
+	)
 
-	span, newCtx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("redis.%s", cmd.Name()))
-	ext.DBType.Set(span, "cache")
-	ext.DBInstance.Set(span, strconv.Itoa(h.db))
-	ext.PeerAddress.Set(span, strings.Join(h.addrs, ", "))
-	ext.PeerService.Set(span, "redis")
-	ext.SpanKind.Set(span, ext.SpanKindEnum("client"))
-	ext.DBStatement.Set(span, strings.ToUpper(cmd.Name()))
-	span.SetTag("service.name", "redis")
-	return newCtx, nil
-
+	return ctx, nil
 }
 
-func (h *redisHook) AfterProcess(ctx context.Context, _ redis.Cmder) error {
-	span := opentracing.SpanFromContext(ctx)
-
-	if span != nil {
-		// if context is raised an error.
-		if ctx.Err() != nil {
-			ext.Error.Set(span, true)
-			span.LogFields(log.Error(ctx.Err()))
-		}
-		span.Finish()
-	}
+func (h *redisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	span := trace.SpanFromContext(ctx)
+	finishRedisSpan(span, cmd.Err())
 
 	return nil
 }
 
 func (h *redisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
-	span, newCtx := opentracing.StartSpanFromContext(ctx, "redis:pipeline:cmd")
-	ext.DBType.Set(span, "cache")
-	ext.DBInstance.Set(span, strconv.Itoa(h.db))
-	ext.PeerAddress.Set(span, strings.Join(h.addrs, ", "))
-	ext.PeerService.Set(span, "redis")
-	ext.SpanKind.Set(span, ext.SpanKindEnum("client"))
 	merge := make([]string, len(cmds))
 	for i, cmd := range cmds {
 		merge[i] = strings.ToUpper(cmd.Name())
 	}
-	ext.DBStatement.Set(span, strings.Join(merge, " --> "))
-	span.SetTag("service.name", "redis")
-	return newCtx, nil
+
+	ctx, _ = tracer.Tracer().Start(ctx, "redis.pipeline",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(h.attrs...),
+		trace.WithAttributes(
+			semconv.DBStatementKey.String(strings.Join(merge, " --> ")),
+			attribute.Int("db.redis.num_cmd", len(cmds)),
+		),
+	)
+
+	return ctx, nil
 }
 
-func (h *redisHook) AfterProcessPipeline(ctx context.Context, _ []redis.Cmder) error {
-	span := opentracing.SpanFromContext(ctx)
-	if span != nil {
-		// if context is raised an error.
-		if ctx.Err() != nil {
-			ext.Error.Set(span, true)
-			span.LogFields(log.Error(ctx.Err()))
+func (h *redisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil && cmd.Err() != redis.Nil {
+			err = cmd.Err()
+			break
 		}
-		span.Finish()
 	}
+
+	finishRedisSpan(trace.SpanFromContext(ctx), err)
+
 	return nil
 }
+
+// finishRedisSpan records the error of the command, a missing key is not one
+func finishRedisSpan(span trace.Span, err error) {
+	if err != nil && err != redis.Nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -1,6 +1,7 @@
 package cryptox
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha512"
@@ -48,6 +49,9 @@ func PublicKeyToBytes(pub *rsa.PublicKey) (error, []byte) {
 // BytesToPrivateKey bytes to private key
 func BytesToPrivateKey(priv []byte) (error, *rsa.PrivateKey) {
 	block, _ := pem.Decode(priv)
+	if block == nil {
+		return fmt.Errorf("no pem block found"), nil
+	}
 	enc := x509.IsEncryptedPEMBlock(block)
 	b := block.Bytes
 	var err error
@@ -70,6 +74,9 @@ func BytesToPrivateKey(priv []byte) (error, *rsa.PrivateKey) {
 // BytesToPublicKey bytes to public key
 func BytesToPublicKey(pub []byte) (error, *rsa.PublicKey) {
 	block, _ := pem.Decode(pub)
+	if block == nil {
+		return fmt.Errorf("no pem block found"), nil
+	}
 	enc := x509.IsEncryptedPEMBlock(block)
 	b := block.Bytes
 	var err error
@@ -93,6 +100,57 @@ func BytesToPublicKey(pub []byte) (error, *rsa.PublicKey) {
 	return nil, key
 }
 
+// BytesToSigner bytes to private key, RSA keys in PKCS#1 as read by BytesToPrivateKey,
+// RSA, ECDSA and Ed25519 keys in PKCS#8 and ECDSA keys in SEC 1
+func BytesToSigner(priv []byte) (error, crypto.Signer) {
+	block, _ := pem.Decode(priv)
+	if block == nil {
+		return fmt.Errorf("no pem block found"), nil
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		err, key := BytesToPrivateKey(priv)
+		if err != nil {
+			return err, nil
+		}
+		return nil, key
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return err, nil
+		}
+		return nil, key
+	}
+
+	ifc, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return err, nil
+	}
+
+	key, ok := ifc.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported private key type %T", ifc), nil
+	}
+
+	return nil, key
+}
+
+// BytesToAnyPublicKey bytes to public key of any type x509 supports, BytesToPublicKey only accepts RSA
+func BytesToAnyPublicKey(pub []byte) (error, crypto.PublicKey) {
+	block, _ := pem.Decode(pub)
+	if block == nil {
+		return fmt.Errorf("no pem block found"), nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return err, nil
+	}
+
+	return nil, key
+}
+
 // EncryptWithPublicKey encrypts data with public key
 func EncryptWithPublicKey(msg []byte, pub *rsa.PublicKey) (error, []byte) {
 	hash := sha512.New()
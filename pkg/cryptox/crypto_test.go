@@ -0,0 +1,63 @@
+// Package cryptox
+package cryptox
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytesToSigner(t *testing.T) {
+	err, rsaKey, _ := GenerateKeyPair(2048)
+	assert.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	assert.NoError(t, err)
+
+	testCase := map[string][]byte{
+		"pkcs1 rsa":     PrivateKeyToBytes(rsaKey),
+		"pkcs8 ed25519": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}),
+		"sec1 ecdsa":    pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}),
+	}
+
+	for name, b := range testCase {
+		err, signer := BytesToSigner(b)
+		if assert.NoError(t, err, name) {
+			assert.NotNil(t, signer.Public(), name)
+		}
+	}
+
+	err, _ = BytesToSigner([]byte("not a pem"))
+	assert.Error(t, err)
+}
+
+func TestBytesToAnyPublicKey(t *testing.T) {
+	err, _, rsaPublic := GenerateKeyPair(2048)
+	assert.NoError(t, err)
+
+	err, b := PublicKeyToBytes(rsaPublic)
+	assert.NoError(t, err)
+
+	err, key := BytesToAnyPublicKey(b)
+	assert.NoError(t, err)
+	assert.Equal(t, rsaPublic, key)
+
+	err, _ = BytesToAnyPublicKey([]byte("not a pem"))
+	assert.Error(t, err)
+
+	err, _ = BytesToPublicKey([]byte("not a pem"))
+	assert.Error(t, err)
+}
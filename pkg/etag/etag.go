@@ -0,0 +1,94 @@
+// Package etag builds the entity tags of the responses and matches them against the
+// conditional request headers
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Any matches every current representation in If-Match and If-None-Match
+const Any = "*"
+
+// codings the tags of the compressed representations are suffixed with
+var codings = []string{"gzip", "br"}
+
+// Weak tag of the content b
+func Weak(b []byte) string {
+	sum := sha256.Sum256(b)
+	return `W/"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// FromTime strong tag of the version of a resource last updated at t, the update time changes
+// with every write so two representations of the same tag are the same resource
+func FromTime(t time.Time) string {
+	return `"` + strconv.FormatInt(t.UnixNano(), 36) + `"`
+}
+
+// Encoded tag of the representation compressed with the content coding. A strong tag tells the bytes
+// apart, so the gzip and br representations are tagged apart from the identity one. A weak tag is
+// kept as the representations are equivalent
+func Encoded(tag string, coding string) string {
+	if tag == "" || weak(tag) || !strings.HasSuffix(tag, `"`) {
+		return tag
+	}
+
+	return strings.TrimSuffix(tag, `"`) + "-" + coding + `"`
+}
+
+// MatchStrong reports whether the If-Match header lists tag, as sent or as compressed. The tags are
+// compared strongly, a weak tag on either side never matches as it does not tell the resource is
+// unchanged
+func MatchStrong(header string, tag string) bool {
+	return match(header, tag, func(candidate string) bool {
+		return !weak(tag) && !weak(candidate) && decoded(candidate) == tag
+	})
+}
+
+// MatchWeak reports whether the If-None-Match header lists tag, as sent or as compressed. The tags
+// are compared weakly, without the weak indicator
+func MatchWeak(header string, tag string) bool {
+	return match(header, tag, func(candidate string) bool {
+		return decoded(opaque(candidate)) == opaque(tag)
+	})
+}
+
+// match reports whether the header lists any tag or a candidate equal to tag
+func match(header string, tag string, equal func(candidate string) bool) bool {
+	if tag == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == Any || equal(candidate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// weak reports whether the tag has the weak indicator
+func weak(tag string) bool {
+	return strings.HasPrefix(tag, "W/")
+}
+
+// decoded tag of the representation before it was compressed with one of the codings
+func decoded(tag string) string {
+	for _, coding := range codings {
+		if suffix := "-" + coding + `"`; strings.HasSuffix(tag, suffix) {
+			return strings.TrimSuffix(tag, suffix) + `"`
+		}
+	}
+
+	return tag
+}
+
+// opaque part of the tag, without the weak indicator
+func opaque(tag string) string {
+	return strings.TrimPrefix(tag, "W/")
+}
@@ -0,0 +1,64 @@
+// Package etag
+package etag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeak(t *testing.T) {
+	tag := Weak([]byte(`{"id_food":"1"}`))
+
+	assert.Regexp(t, `^W/"[A-Za-z0-9_-]{22}"$`, tag)
+	assert.Equal(t, tag, Weak([]byte(`{"id_food":"1"}`)))
+	assert.NotEqual(t, tag, Weak([]byte(`{"id_food":"2"}`)))
+}
+
+func TestFromTime(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 19, 10, 0, 0, 123456000, time.UTC)
+
+	assert.Regexp(t, `^"[0-9a-z]+"$`, FromTime(updatedAt))
+	assert.Equal(t, FromTime(updatedAt), FromTime(updatedAt.In(time.FixedZone("WIB", 7*3600))))
+	assert.NotEqual(t, FromTime(updatedAt), FromTime(updatedAt.Add(time.Microsecond)))
+}
+
+func TestMatchWeak(t *testing.T) {
+	tag := `W/"abc"`
+
+	assert.True(t, MatchWeak(`W/"abc"`, tag))
+	assert.True(t, MatchWeak(`"abc"`, tag))
+	assert.True(t, MatchWeak(`"xyz", W/"abc"`, tag))
+	assert.True(t, MatchWeak(`*`, tag))
+	assert.True(t, MatchWeak(`W/"abc"`, `"abc"`))
+	assert.False(t, MatchWeak(`W/"xyz"`, tag))
+	assert.False(t, MatchWeak(``, tag))
+	assert.False(t, MatchWeak(`*`, ""))
+	assert.True(t, MatchWeak(`"abc-gzip"`, `"abc"`))
+	assert.True(t, MatchWeak(`W/"abc-br"`, `"abc"`))
+}
+
+func TestMatchStrong(t *testing.T) {
+	tag := `"abc"`
+
+	assert.True(t, MatchStrong(`"abc"`, tag))
+	assert.True(t, MatchStrong(`"xyz", "abc"`, tag))
+	assert.True(t, MatchStrong(`*`, tag))
+	assert.False(t, MatchStrong(`W/"abc"`, tag))
+	assert.False(t, MatchStrong(`W/"abc"`, `W/"abc"`))
+	assert.False(t, MatchStrong(`"xyz"`, tag))
+	assert.False(t, MatchStrong(``, tag))
+	assert.False(t, MatchStrong(`*`, ""))
+	assert.True(t, MatchStrong(`"abc-gzip"`, tag))
+	assert.True(t, MatchStrong(`"abc-br"`, tag))
+	assert.False(t, MatchStrong(`W/"abc-gzip"`, tag))
+	assert.False(t, MatchStrong(`"abc-deflate"`, tag))
+}
+
+func TestEncoded(t *testing.T) {
+	assert.Equal(t, `"abc-gzip"`, Encoded(`"abc"`, "gzip"))
+	assert.Equal(t, `"abc-br"`, Encoded(`"abc"`, "br"))
+	assert.Equal(t, `W/"abc"`, Encoded(`W/"abc"`, "gzip"))
+	assert.Equal(t, "", Encoded("", "gzip"))
+}
@@ -15,9 +15,10 @@ func Hmac256(src string, secret string) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-// HmacComparator compare signature request body with hmac hash from payload
+// HmacComparator compare signature request body with hmac hash from payload, in constant time
+// so the comparison does not leak how much of the signature matched
 func HmacComparator(message string, messageHmac string, secret string) bool {
-	return messageHmac == Hmac256(message, secret)
+	return hmac.Equal([]byte(messageHmac), []byte(Hmac256(message, secret)))
 }
 
 func Hmac256Raw(src, secret string) []byte {
@@ -28,6 +28,18 @@ func TestHmacComparator(t *testing.T) {
 	}
 }
 
+func TestHmacComparatorMismatch(t *testing.T) {
+	for _, encrypted := range []string{
+		"0329a06b62cd16b33eb6792be8c60b158d89a2ee3a876fce9a881ebb488c0915",
+		"0329a06b62cd16b33eb6792be8c60b158d89a2ee3a876fce9a881ebb488c09",
+		"",
+	} {
+		if HmacComparator("test", encrypted, "secret") {
+			t.Fatalf("expected signature %q to be rejected", encrypted)
+		}
+	}
+}
+
 func TestHmac256Raw(t *testing.T) {
 
 	expected := []byte{
@@ -0,0 +1,98 @@
+// Package health checks the dependencies the service needs to serve
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusUp the dependency answered in time
+	StatusUp = "up"
+	// StatusDown the dependency failed or timed out
+	StatusDown = "down"
+)
+
+// Check of a dependency, a critical one failing makes the service not ready
+type Check struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// Result of the check of a dependency, the error is for the logs and never serialized as it may
+// tell about the addresses and versions of the dependencies
+type Result struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"-"`
+}
+
+// Report of the checks, down when a critical dependency is down
+type Report struct {
+	Status       string   `json:"status"`
+	Dependencies []Result `json:"dependencies"`
+}
+
+// Ready reports whether no critical dependency is down
+func (r Report) Ready() bool {
+	return r.Status == StatusUp
+}
+
+// Run the checks concurrently, each one is given timeout to answer
+func Run(ctx context.Context, timeout time.Duration, checks ...Check) Report {
+	report := Report{Status: StatusUp, Dependencies: make([]Result, len(checks))}
+
+	wg := sync.WaitGroup{}
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c Check) {
+			defer wg.Done()
+			report.Dependencies[i] = run(ctx, timeout, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, res := range report.Dependencies {
+		if res.Critical && res.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func run(ctx context.Context, timeout time.Duration, c Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// the check may not honor the context, it is not waited for
+		err = ctx.Err()
+	}
+
+	res := Result{
+		Name:      c.Name,
+		Status:    StatusUp,
+		Critical:  c.Critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+
+	return res
+}
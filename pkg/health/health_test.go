@@ -0,0 +1,57 @@
+// Package health
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func up(context.Context) error { return nil }
+
+func down(context.Context) error { return errors.New("connection refused") }
+
+func hang(ctx context.Context) error {
+	<-ctx.Done()
+	time.Sleep(time.Second)
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	report := Run(context.Background(), time.Second,
+		Check{Name: "db_write", Critical: true, Check: up},
+		Check{Name: "kafka", Check: down},
+	)
+
+	assert.True(t, report.Ready())
+	assert.Equal(t, StatusUp, report.Dependencies[0].Status)
+	assert.Equal(t, "db_write", report.Dependencies[0].Name)
+	assert.Equal(t, StatusDown, report.Dependencies[1].Status)
+	assert.Equal(t, "connection refused", report.Dependencies[1].Error)
+}
+
+func TestRunCriticalDown(t *testing.T) {
+	report := Run(context.Background(), time.Second,
+		Check{Name: "db_write", Critical: true, Check: down},
+		Check{Name: "redis", Check: up},
+	)
+
+	assert.False(t, report.Ready())
+	assert.Equal(t, StatusDown, report.Status)
+}
+
+func TestRunTimeout(t *testing.T) {
+	start := time.Now()
+	report := Run(context.Background(), 50*time.Millisecond,
+		Check{Name: "db_read", Critical: true, Check: hang},
+		Check{Name: "redis", Critical: true, Check: hang},
+	)
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.False(t, report.Ready())
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies[0].Error)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies[1].Error)
+}
@@ -5,7 +5,7 @@ import (
 	"io"
 	"net/http"
 
-	"github.com/opentracing/opentracing-go"
+	"sharefood/pkg/tracer"
 )
 
 // NewRequest returns a new Request given a context, method, URL, and optional body.
@@ -32,15 +32,7 @@ func NewRequest(ctx context.Context, method, url string, body io.Reader) (*http.
 		return req, err
 	}
 
-	span := opentracing.SpanFromContext(ctx)
+	tracer.Inject(ctx, req.Header)
 
-	if span != nil {
-		err = span.Tracer().Inject(
-			span.Context(),
-			opentracing.HTTPHeaders,
-			opentracing.HTTPHeadersCarrier(req.Header),
-		)
-	}
-	
-	return req.WithContext(ctx), err
+	return req.WithContext(ctx), nil
 }
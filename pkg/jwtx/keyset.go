@@ -0,0 +1,156 @@
+// Package jwtx
+package jwtx
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"sort"
+
+	"github.com/dgrijalva/jwt-go/v4"
+)
+
+// Key signing or verification key, identified in the token by its kid header
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	// Private is nil for a key only kept to verify tokens it signed before being rotated out
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// NewSigningKey key able to sign, the algorithm follows from the key type:
+// RS256 for RSA, ES256/ES384/ES512 for ECDSA by curve and EdDSA for Ed25519
+func NewSigningKey(id string, private crypto.Signer) (Key, error) {
+	key, err := NewVerificationKey(id, private.Public())
+	if err != nil {
+		return Key{}, err
+	}
+
+	key.Private = private
+
+	return key, nil
+}
+
+// NewVerificationKey key only able to verify
+func NewVerificationKey(id string, public crypto.PublicKey) (Key, error) {
+	if id == "" {
+		return Key{}, fmt.Errorf("key id required")
+	}
+
+	key := Key{ID: id, Public: public}
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			key.Method = jwt.SigningMethodES256
+		case 384:
+			key.Method = jwt.SigningMethodES384
+		case 521:
+			key.Method = jwt.SigningMethodES512
+		default:
+			return Key{}, fmt.Errorf("key %q: unsupported curve %s", id, k.Curve.Params().Name)
+		}
+	case ed25519.PublicKey:
+		key.Method = SigningMethodEd25519
+	default:
+		return Key{}, fmt.Errorf("key %q: unsupported key type %T", id, public)
+	}
+
+	return key, nil
+}
+
+// KeySet signs with one key and verifies with all of them, so tokens signed by a rotated out key
+// stay valid until they expire as long as its public key is kept in the set
+type KeySet struct {
+	signing Key
+	keys    map[string]Key
+}
+
+// NewKeySet creates a key set signing with the signing key
+func NewKeySet(signing Key, verification ...Key) (*KeySet, error) {
+	if signing.Private == nil {
+		return nil, fmt.Errorf("key %q: signing key without private key", signing.ID)
+	}
+
+	set := &KeySet{
+		signing: signing,
+		keys:    map[string]Key{signing.ID: signing},
+	}
+
+	for _, key := range verification {
+		if _, ok := set.keys[key.ID]; ok {
+			return nil, fmt.Errorf("key %q: duplicate key id", key.ID)
+		}
+		set.keys[key.ID] = key
+	}
+
+	return set, nil
+}
+
+// Sign signs the claims with the signing key and its kid header
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signing.Method, claims)
+	token.Header["kid"] = s.signing.ID
+
+	return token.SignedString(s.signing.Private)
+}
+
+// Keyfunc picks the verification key by the kid header, the token algorithm must be the one of the key
+// so a public key can never be used as an hmac secret
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if token.Method == nil || token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method for key %q", kid)
+	}
+
+	return key.Public, nil
+}
+
+// Parse parses and validates a token signed by one of the keys of the set
+func (s *KeySet) Parse(raw string, claims jwt.Claims, options ...jwt.ParserOption) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(raw, claims, s.Keyfunc, append([]jwt.ParserOption{jwt.WithValidMethods(s.algorithms())}, options...)...)
+}
+
+// JWKS public keys of the set, as served on the jwks uri
+func (s *KeySet) JWKS() (JSONWebKeySet, error) {
+	ids := make([]string, 0, len(s.keys))
+	for id := range s.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(ids))}
+	for _, id := range ids {
+		key := s.keys[id]
+		jwk, err := NewJSONWebKey(key.ID, key.Method.Alg(), key.Public)
+		if err != nil {
+			return JSONWebKeySet{}, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set, nil
+}
+
+func (s *KeySet) algorithms() []string {
+	seen := map[string]bool{}
+	algs := []string{}
+	for _, key := range s.keys {
+		if alg := key.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+
+	return algs
+}
@@ -0,0 +1,141 @@
+// Package jwtx
+package jwtx
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClaims() jwt.StandardClaims {
+	return jwt.StandardClaims{
+		Subject:   "jhon.doe",
+		ExpiresAt: jwt.At(time.Now().Add(time.Minute)),
+	}
+}
+
+func TestKeySet_SignParse(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	testCase := map[string]crypto.Signer{
+		"RS256": rsaKey,
+		"EdDSA": edKey,
+		"ES256": ecKey,
+	}
+
+	for alg, private := range testCase {
+		key, err := NewSigningKey("key-1", private)
+		assert.NoError(t, err, alg)
+		assert.Equal(t, alg, key.Method.Alg())
+
+		set, err := NewKeySet(key)
+		assert.NoError(t, err, alg)
+
+		raw, err := set.Sign(newClaims())
+		assert.NoError(t, err, alg)
+
+		claims := jwt.StandardClaims{}
+		token, err := set.Parse(raw, &claims)
+		assert.NoError(t, err, alg)
+		assert.True(t, token.Valid, alg)
+		assert.Equal(t, "key-1", token.Header["kid"], alg)
+		assert.Equal(t, "jhon.doe", claims.Subject, alg)
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	_, oldPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	_, newPrivate, _ := ed25519.GenerateKey(rand.Reader)
+
+	oldKey, err := NewSigningKey("old", oldPrivate)
+	assert.NoError(t, err)
+	newKey, err := NewSigningKey("new", newPrivate)
+	assert.NoError(t, err)
+
+	oldSet, err := NewKeySet(oldKey)
+	assert.NoError(t, err)
+	raw, err := oldSet.Sign(newClaims())
+	assert.NoError(t, err)
+
+	// the old key is kept for verification only after the rotation
+	retired, err := NewVerificationKey("old", oldPrivate.Public())
+	assert.NoError(t, err)
+	rotated, err := NewKeySet(newKey, retired)
+	assert.NoError(t, err)
+
+	_, err = rotated.Parse(raw, &jwt.StandardClaims{})
+	assert.NoError(t, err)
+
+	// and once it is dropped its tokens are rejected
+	dropped, err := NewKeySet(newKey)
+	assert.NoError(t, err)
+
+	_, err = dropped.Parse(raw, &jwt.StandardClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_RejectsAlgorithmConfusion(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	key, err := NewSigningKey("key-1", rsaKey)
+	assert.NoError(t, err)
+	set, err := NewKeySet(key)
+	assert.NoError(t, err)
+
+	jwks, err := set.JWKS()
+	assert.NoError(t, err)
+
+	// a token signed with hmac using the published key material as the secret
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims())
+	token.Header["kid"] = "key-1"
+	raw, err := token.SignedString([]byte(jwks.Keys[0].N))
+	assert.NoError(t, err)
+
+	_, err = set.Parse(raw, &jwt.StandardClaims{})
+	assert.Error(t, err)
+
+	// and one without a kid
+	raw, err = jwt.NewWithClaims(jwt.SigningMethodRS256, newClaims()).SignedString(rsaKey)
+	assert.NoError(t, err)
+
+	_, err = set.Parse(raw, &jwt.StandardClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	signing, err := NewSigningKey("rsa", rsaKey)
+	assert.NoError(t, err)
+	verification, err := NewVerificationKey("ed", edPublic)
+	assert.NoError(t, err)
+
+	set, err := NewKeySet(signing, verification)
+	assert.NoError(t, err)
+
+	jwks, err := set.JWKS()
+	assert.NoError(t, err)
+	assert.Len(t, jwks.Keys, 2)
+
+	keys, err := jwks.PublicKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, keys["rsa"])
+	assert.Equal(t, edPrivate.Public(), keys["ed"])
+}
@@ -35,6 +35,7 @@ func (c *consumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, clai
 	// The `ConsumeClaim` itself is called within a goroutine, see:
 	// https://github.com/Shopify/sarama/blob/master/consumer_group.go#L27-L29
 	for msg := range claim.Messages() {
+		consumedMessages.WithLabelValues(msg.Topic).Inc()
 		if c.autoCommit {
 			session.MarkMessage(msg, "")
 		}
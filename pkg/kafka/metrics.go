@@ -0,0 +1,28 @@
+// Package kafka
+package kafka
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricResultSuccess = "success"
+	metricResultError   = "error"
+)
+
+var (
+	producedMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kafka",
+		Subsystem: "producer",
+		Name:      "messages_total",
+		Help:      "Number of messages published, by topic and result.",
+	}, []string{"topic", "result"})
+
+	consumedMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kafka",
+		Subsystem: "consumer",
+		Name:      "messages_total",
+		Help:      "Number of messages consumed, by topic.",
+	}, []string{"topic"})
+)
@@ -0,0 +1,35 @@
+// Package kafka
+package kafka
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// Ping reports whether the cluster can be reached, any broker accepting a connection is enough
+func Ping(ctx context.Context, brokers []string) error {
+	var (
+		dialer net.Dialer
+		errs   []string
+	)
+
+	for _, broker := range brokers {
+		conn, err := dialer.DialContext(ctx, "tcp", strings.TrimSpace(broker))
+		if err == nil {
+			return conn.Close()
+		}
+		errs = append(errs, err.Error())
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if len(errs) == 0 {
+		return errors.New("no kafka broker configured")
+	}
+
+	return errors.New(strings.Join(errs, "; "))
+}
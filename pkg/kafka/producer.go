@@ -51,9 +51,12 @@ func (k *producer) Publish(_ context.Context, msg *MessageContext) error {
 	partition, offset, err := k.producer.SendMessage(param)
 
 	if err != nil {
+		producedMessages.WithLabelValues(msg.Topic, metricResultError).Inc()
 		return fmt.Errorf("[kafka-publisher] topic: %s, partition %d, offset %d, id %v, got:%s ", msg.Topic, partition, offset, msg.LogId, err.Error())
 	}
 
+	producedMessages.WithLabelValues(msg.Topic, metricResultSuccess).Inc()
+
 	if msg.Verbose {
 		logger.Info(fmt.Sprintf("[kafka-publisher] topic: %s,  partition: %d, offset: %d", msg.Topic, partition, offset), logger.Any("msg", msg.Value))
 	}
@@ -20,4 +20,7 @@ const (
 
     // EnvironmentKey holds the environment field
     EnvironmentKey = "env"
+
+    // RequestIDKey holds the request id field
+    RequestIDKey = "request_id"
 )
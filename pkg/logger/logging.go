@@ -7,6 +7,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"sharefood/pkg/requestid"
 	"sharefood/pkg/util"
 )
 
@@ -155,40 +156,41 @@ func AccessLog(arg interface{}, fl ...Field) {
 
 // InfoWithContext log info with context
 func InfoWithContext(ctx context.Context, arg interface{}, fl ...Field) {
-	logrus.WithFields(extractContext(ctx.Value("access"), map[string]interface{}{
+	logrus.WithFields(extractContext(ctx, map[string]interface{}{
 		EventKey: extract(fl...),
 	})).WithContext(ctx).Info(arg)
 }
 
 // WarnWithContext log warn with context
 func WarnWithContext(ctx context.Context, arg interface{}, fl ...Field) {
-	logrus.WithFields(extractContext(ctx.Value("access"), map[string]interface{}{
+	logrus.WithFields(extractContext(ctx, map[string]interface{}{
 		EventKey: extract(fl...),
 	})).WithContext(ctx).Warn(arg)
 }
 
 // ErrorWithContext log error with context
 func ErrorWithContext(ctx context.Context, arg interface{}, fl ...Field) {
-	logrus.WithFields(extractContext(ctx.Value("access"), map[string]interface{}{
+	logrus.WithFields(extractContext(ctx, map[string]interface{}{
 		EventKey: extract(fl...),
 	})).WithContext(ctx).Error(arg)
 }
 
 // DebugWithContext log debug with context
 func DebugWithContext(ctx context.Context, arg interface{}, fl ...Field) {
-	logrus.WithFields(extractContext(ctx.Value("access"), map[string]interface{}{
+	logrus.WithFields(extractContext(ctx, map[string]interface{}{
 		EventKey: extract(fl...),
 	})).WithContext(ctx).Debug(arg)
 }
 
 // TraceWithContext log trace with context
 func TraceWithContext(ctx context.Context, arg interface{}, fl ...Field) {
-	logrus.WithFields(extractContext(ctx.Value("access"), map[string]interface{}{
+	logrus.WithFields(extractContext(ctx, map[string]interface{}{
 		EventKey: extract(fl...),
 	})).WithContext(ctx).Trace(arg)
 }
 
-func extractContext(i interface{}, logField map[string]interface{}) map[string]interface{} {
+// extractContext adds the access fields and the request id carried by ctx
+func extractContext(ctx context.Context, logField map[string]interface{}) map[string]interface{} {
 
 	if Environment(conf.Environment) != "" {
 		logField[EnvironmentKey] = Environment(conf.Environment)
@@ -198,6 +200,11 @@ func extractContext(i interface{}, logField map[string]interface{}) map[string]i
 		logField[ServiceKey] = conf.ServiceName
 	}
 
+	if id, ok := requestid.FromContext(ctx); ok {
+		logField[RequestIDKey] = id.String()
+	}
+
+	i := ctx.Value("access")
 	if util.IsSameType(i, logField) {
 		x := i.(map[string]interface{})
 		for k, v := range x {
@@ -0,0 +1,39 @@
+// Package mailer
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message outgoing email
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer defines the minimum interface for an email delivery system.
+type Mailer interface {
+	// Send delivers the message to every recipient.
+	Send(ctx context.Context, msg Message) error
+}
+
+// Bytes renders message as plain text RFC 5322 mail
+func (m Message) Bytes() []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "From: %s\r\n", m.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", m.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(m.Body)
+
+	return []byte(b.String())
+}
@@ -0,0 +1,85 @@
+// Package mailer
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sharefood/pkg/logger"
+)
+
+// Compile time check to verify implements the Mailer interface.
+var (
+	_ Mailer = (*fileOutbox)(nil)
+	_ Mailer = (*logOutbox)(nil)
+)
+
+// fileOutbox implements Mailer and writes every message as .eml file,
+// intended for local development and testing purpose
+type fileOutbox struct {
+	dir  string
+	from string
+}
+
+// NewFileOutbox creates a Mailer writing messages into dir
+func NewFileOutbox(dir, from string) Mailer {
+	return &fileOutbox{dir: dir, from: from}
+}
+
+// Send writes the message into the outbox directory
+func (s *fileOutbox) Send(_ context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = s.from
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create outbox: %w", err)
+	}
+
+	name := fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), sanitize(strings.Join(msg.To, "_")))
+	if err := ioutil.WriteFile(filepath.Join(s.dir, name), msg.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+
+	return nil
+}
+
+// logOutbox implements Mailer and only prints the message into the log
+type logOutbox struct {
+	from string
+}
+
+// NewLogOutbox creates a Mailer printing messages into the log
+func NewLogOutbox(from string) Mailer {
+	return &logOutbox{from: from}
+}
+
+// Send prints the message
+func (s *logOutbox) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = s.from
+	}
+
+	logger.InfoWithContext(ctx, string(msg.Bytes()),
+		logger.EventName("mailer_outbox"),
+		logger.Any("to", msg.To),
+		logger.Any("subject", msg.Subject),
+	)
+
+	return nil
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
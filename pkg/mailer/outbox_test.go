@@ -0,0 +1,38 @@
+// Package mailer
+package mailer
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileOutbox_Send(t *testing.T) {
+	dir := t.TempDir()
+	m := NewFileOutbox(dir, "no-reply@sharefood.id")
+
+	err := m.Send(context.Background(), Message{
+		To:      []string{"jhon.doe@mail.com"},
+		Subject: "Reset your password",
+		Body:    "token: abc",
+	})
+	assert.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.NoError(t, err)
+	if !assert.Len(t, files, 1) {
+		return
+	}
+	assert.True(t, strings.HasSuffix(files[0], "jhon.doe_mail.com.eml"))
+
+	b, err := ioutil.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "From: no-reply@sharefood.id\r\n")
+	assert.Contains(t, string(b), "To: jhon.doe@mail.com\r\n")
+	assert.Contains(t, string(b), "Subject: Reset your password\r\n")
+	assert.True(t, strings.HasSuffix(string(b), "\r\n\r\ntoken: abc"))
+}
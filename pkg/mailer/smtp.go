@@ -0,0 +1,42 @@
+// Package mailer
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+)
+
+// Compile time check to verify implements the Mailer interface.
+var _ Mailer = (*smtpMailer)(nil)
+
+// smtpMailer implements Mailer and delivers messages through a smtp relay
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTP creates a Mailer for the given smtp relay, auth is skipped when user is blank
+func NewSMTP(host string, port int, user, password, from string) Mailer {
+	var auth smtp.Auth
+	if user != "" {
+		auth = smtp.PlainAuth("", user, password, host)
+	}
+
+	return &smtpMailer{
+		addr: fmt.Sprintf("%s:%d", host, port),
+		from: from,
+		auth: auth,
+	}
+}
+
+// Send delivers the message, the message sender is overwritten with the configured one
+func (s *smtpMailer) Send(_ context.Context, msg Message) error {
+	msg.From = s.from
+	if err := smtp.SendMail(s.addr, s.auth, s.from, msg.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
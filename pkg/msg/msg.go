@@ -4,6 +4,7 @@ package msg
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -41,6 +42,16 @@ func (m *Message) doMap() *Message {
 	return m
 }
 
+// codeMessages messages file listing the texts of each status code
+type codeMessages struct {
+	Messages []struct {
+		// Name key of the message, CodeKey of the code when empty
+		Name     string `yaml:"name"`
+		Code     int    `yaml:"code"`
+		Contents []Lang `yaml:"contents"`
+	} `yaml:"messages"`
+}
+
 type Lang struct {
 	Lang string `yaml:"lang"`
 	Text string `yaml:"text"`
@@ -76,7 +87,9 @@ func Setup(fName string, paths ...string) error {
 			f := fmt.Sprint(p, fName)
 			err := file.ReadFromYAML(f, &langs)
 			if err != nil {
-				continue
+				if !readCodeMessages(f, &langs) {
+					continue
+				}
 			}
 			err = nil
 		}
@@ -96,6 +109,29 @@ func Setup(fName string, paths ...string) error {
 	return err
 }
 
+// readCodeMessages appends the messages of a file keyed by status code, see CodeKey
+func readCodeMessages(f string, langs *[]Message) bool {
+	var codes codeMessages
+	if err := file.ReadFromYAML(f, &codes); err != nil || len(codes.Messages) == 0 {
+		return false
+	}
+
+	for _, c := range codes.Messages {
+		name := c.Name
+		if name == "" {
+			name = CodeKey(c.Code)
+		}
+		*langs = append(*langs, Message{Name: name, Status: c.Code, Langs: c.Contents})
+	}
+
+	return true
+}
+
+// CodeKey key of the message of a status code
+func CodeKey(code int) string {
+	return strconv.Itoa(code)
+}
+
 func cleanLangStr(s string) string {
 	return strings.ToLower(strings.Trim(s, " "))
 }
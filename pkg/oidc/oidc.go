@@ -0,0 +1,307 @@
+// Package oidc
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"golang.org/x/oauth2"
+
+	"sharefood/pkg/jwtx"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+// defaultTimeout bounds the calls to the provider when no client is given
+const defaultTimeout = 10 * time.Second
+
+// Config settings of an openid connect identity provider
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims identity claims of a validated id token
+type Claims struct {
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+	jwt.StandardClaims
+}
+
+// Provider runs the authorization code flow against one identity provider,
+// the endpoints are discovered from the issuer on first use
+type Provider struct {
+	config Config
+	client *http.Client
+
+	// fetch lets one caller at a time go to the provider, mu only guards the fields below
+	// so verifying with a known key never waits on the network
+	fetch    sync.Mutex
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	jwksURI  string
+	keys     map[string]interface{}
+	keysTime time.Time
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewProvider creates a provider, client defaults to a client timing out after 10 seconds
+func NewProvider(config Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{
+		config: config,
+		client: client,
+	}
+}
+
+// AuthCodeURL url of the provider login page, challenge is the S256 challenge of the PKCE verifier
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, challenge string) (string, error) {
+	cfg, err := p.oauth2Config(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("nonce", nonce),
+		oauth2.SetAuthURLParam("code_challenge", challenge),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+// Exchange trades the authorization code for the id token and validates it against the nonce
+func (p *Provider) Exchange(ctx context.Context, code string, verifier string, nonce string) (Claims, error) {
+	cfg, err := p.oauth2Config(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	token, err := cfg.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code,
+		oauth2.SetAuthURLParam("code_verifier", verifier),
+	)
+	if err != nil {
+		return Claims{}, fmt.Errorf("exchange code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return Claims{}, fmt.Errorf("token response has no id_token")
+	}
+
+	return p.Verify(ctx, rawIDToken, nonce)
+}
+
+// Verify validates the signature, issuer, audience, expiry and nonce of an id token
+func (p *Provider) Verify(ctx context.Context, rawIDToken string, nonce string) (Claims, error) {
+	if _, err := p.oauth2Config(ctx); err != nil {
+		return Claims{}, err
+	}
+
+	claims := Claims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithIssuer(p.config.Issuer),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return Claims{}, fmt.Errorf("verify id token: %w", err)
+	}
+
+	// an absent expiry passes the jwt validation, an id token must carry one
+	if claims.ExpiresAt == nil {
+		return Claims{}, fmt.Errorf("verify id token: missing exp")
+	}
+
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("verify id token: missing sub")
+	}
+
+	if nonce == "" || claims.Nonce != nonce {
+		return Claims{}, fmt.Errorf("verify id token: nonce mismatch")
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) oauth2Config(ctx context.Context) (*oauth2.Config, error) {
+	if cfg := p.discovered(); cfg != nil {
+		return cfg, nil
+	}
+
+	p.fetch.Lock()
+	defer p.fetch.Unlock()
+
+	// discovered by whoever held the fetch lock before
+	if cfg := p.discovered(); cfg != nil {
+		return cfg, nil
+	}
+
+	issuer := strings.TrimSuffix(p.config.Issuer, "/")
+	doc := discovery{}
+	if err := p.getJSON(ctx, issuer+discoveryPath, &doc); err != nil {
+		return nil, fmt.Errorf("discover provider: %w", err)
+	}
+
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("discover provider: issuer %q does not match %q", doc.Issuer, p.config.Issuer)
+	}
+
+	cfg := &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       p.config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  doc.AuthorizationEndpoint,
+			TokenURL: doc.TokenEndpoint,
+		},
+	}
+
+	p.mu.Lock()
+	p.jwksURI = doc.JWKSURI
+	p.oauth2 = cfg
+	p.mu.Unlock()
+
+	return cfg, nil
+}
+
+func (p *Provider) discovered() *oauth2.Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.oauth2
+}
+
+// key looks up the signing key by id, the key set is refetched when the id is unknown
+// so rotated keys are picked up, but no more than once a minute
+func (p *Provider) key(ctx context.Context, kid string) (interface{}, error) {
+	if key, ok := p.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	p.fetch.Lock()
+	defer p.fetch.Unlock()
+
+	// fetched by whoever held the fetch lock before
+	if key, ok := p.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	p.mu.Lock()
+	jwksURI, keysTime := p.jwksURI, p.keysTime
+	p.mu.Unlock()
+
+	if time.Since(keysTime) < time.Minute {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	set := jwtx.JSONWebKeySet{}
+	if err := p.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("fetch signing keys: %w", err)
+	}
+
+	keys, err := set.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysTime = time.Now()
+	p.mu.Unlock()
+
+	if key, ok := p.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// cachedKey the fetched key of the id, a set holding a single key may leave the key id out
+func (p *Provider) cachedKey(kid string) (interface{}, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, true
+	}
+
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	return nil, false
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get %s: status %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// GenerateVerifier random PKCE code verifier
+func GenerateVerifier() (string, error) {
+	return RandomString(32)
+}
+
+// S256Challenge PKCE code challenge of the verifier
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RandomString url safe encoding of n random bytes, used for the state and the nonce
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
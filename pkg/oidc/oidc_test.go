@@ -0,0 +1,169 @@
+// Package oidc_test
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"github.com/stretchr/testify/assert"
+
+	"sharefood/pkg/oidc"
+	"sharefood/pkg/oidc/oidctest"
+)
+
+const redirectURL = "http://localhost:8081/auth/oidc/mock/callback"
+
+func newMockProvider(t *testing.T) (*oidctest.MockProvider, *oidc.Provider) {
+	mock, err := oidctest.NewMockProvider("sharefood", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(mock)
+	t.Cleanup(srv.Close)
+	mock.Issuer = srv.URL
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       srv.URL,
+		ClientID:     "sharefood",
+		ClientSecret: "secret",
+		RedirectURL:  redirectURL,
+	}, srv.Client())
+
+	return mock, provider
+}
+
+// authorize follows the login page of the mock provider and returns the code it redirects back with
+func authorize(t *testing.T, provider *oidc.Provider, state, nonce, verifier string) string {
+	authURL, err := provider.AuthCodeURL(context.Background(), state, nonce, oidc.S256Challenge(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if !assert.Equal(t, http.StatusFound, resp.StatusCode) {
+		t.FailNow()
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, state, location.Query().Get("state"))
+
+	return location.Query().Get("code")
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	_, provider := newMockProvider(t)
+
+	verifier, err := oidc.GenerateVerifier()
+	assert.NoError(t, err)
+
+	code := authorize(t, provider, "state-1", "nonce-1", verifier)
+
+	claims, err := provider.Exchange(context.Background(), code, verifier, "nonce-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "mock-subject", claims.Subject)
+	assert.Equal(t, "jhon.doe@mail.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+}
+
+func TestProvider_ExchangeWrongVerifier(t *testing.T) {
+	_, provider := newMockProvider(t)
+
+	verifier, err := oidc.GenerateVerifier()
+	assert.NoError(t, err)
+
+	code := authorize(t, provider, "state-1", "nonce-1", verifier)
+
+	_, err = provider.Exchange(context.Background(), code, verifier+"x", "nonce-1")
+	assert.Error(t, err)
+}
+
+func TestProvider_ExchangeWrongNonce(t *testing.T) {
+	_, provider := newMockProvider(t)
+
+	verifier, err := oidc.GenerateVerifier()
+	assert.NoError(t, err)
+
+	code := authorize(t, provider, "state-1", "nonce-1", verifier)
+
+	_, err = provider.Exchange(context.Background(), code, verifier, "nonce-2")
+	assert.Error(t, err)
+}
+
+func TestProvider_Verify(t *testing.T) {
+	mock, provider := newMockProvider(t)
+	now := time.Now()
+
+	valid := oidc.Claims{
+		Nonce: "nonce-1",
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    mock.Issuer,
+			Subject:   "mock-subject",
+			Audience:  jwt.ClaimStrings{"sharefood"},
+			ExpiresAt: jwt.At(now.Add(time.Minute)),
+		},
+	}
+
+	testCase := map[string]func(c *oidc.Claims){
+		"valid":          func(c *oidc.Claims) {},
+		"other audience": func(c *oidc.Claims) { c.Audience = jwt.ClaimStrings{"someone-else"} },
+		"other issuer":   func(c *oidc.Claims) { c.Issuer = "https://issuer.invalid" },
+		"expired":        func(c *oidc.Claims) { c.ExpiresAt = jwt.At(now.Add(-time.Hour)) },
+		"no expiry":      func(c *oidc.Claims) { c.ExpiresAt = nil },
+		"no subject":     func(c *oidc.Claims) { c.Subject = "" },
+	}
+
+	for name, modify := range testCase {
+		claims := valid
+		modify(&claims)
+
+		idToken, err := mock.SignIDToken(claims)
+		assert.NoError(t, err)
+
+		_, err = provider.Verify(context.Background(), idToken, "nonce-1")
+		if name == "valid" {
+			assert.NoError(t, err, name)
+		} else {
+			assert.Error(t, err, name)
+		}
+	}
+}
+
+func TestProvider_DiscoveryTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	provider := oidc.NewProvider(oidc.Config{Issuer: srv.URL, ClientID: "sharefood"}, &http.Client{Timeout: 50 * time.Millisecond})
+
+	start := time.Now()
+	_, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", "challenge")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestS256Challenge(t *testing.T) {
+	// base64url without padding of the sha256 of the verifier
+	assert.Equal(t, "hJ7Ph0ph_P-Xa9w8bWEOrkbywStjVC8t6-YAPegqGrc", oidc.S256Challenge("dBjftJeZ4CVP-mJ92ZL-qkMz8xGtBEWIr15w4ZG8Dlk"))
+}
@@ -0,0 +1,201 @@
+// Package oidctest
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go/v4"
+
+	"sharefood/pkg/jwtx"
+	"sharefood/pkg/oidc"
+)
+
+const keyID = "mock"
+
+// Identity the user the mock provider signs in, every authorization request is approved for it
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// MockProvider local openid connect identity provider for tests and development,
+// it serves discovery, authorization, token and jwks endpoints and checks the PKCE verifier
+type MockProvider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Identity     Identity
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+type authorization struct {
+	redirectURI string
+	nonce       string
+	challenge   string
+}
+
+// NewMockProvider creates a provider with a fresh signing key, Issuer must be set to the url it is served on
+func NewMockProvider(clientID string, clientSecret string) (*MockProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MockProvider{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Identity: Identity{
+			Subject:       "mock-subject",
+			Email:         "jhon.doe@mail.com",
+			EmailVerified: true,
+			Name:          "jhon doe",
+		},
+		key:   key,
+		codes: map[string]authorization{},
+	}, nil
+}
+
+// ServeHTTP implements http.Handler
+func (m *MockProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		m.discovery(w)
+	case "/authorize":
+		m.authorize(w, r)
+	case "/token":
+		m.token(w, r)
+	case "/jwks":
+		jwk, _ := jwtx.NewJSONWebKey(keyID, jwt.SigningMethodRS256.Alg(), &m.key.PublicKey)
+		writeJSON(w, http.StatusOK, jwtx.JSONWebKeySet{Keys: []jwtx.JSONWebKey{jwk}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// SignIDToken signs claims with the provider key, for tests that need a crafted id token
+func (m *MockProvider) SignIDToken(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+
+	return token.SignedString(m.key)
+}
+
+func (m *MockProvider) discovery(w http.ResponseWriter) {
+	issuer := strings.TrimSuffix(m.Issuer, "/")
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+// authorize approves right away and redirects back with the code
+func (m *MockProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != m.ClientID || q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	code, err := oidc.RandomString(16)
+	if err != nil {
+		http.Error(w, "server_error", http.StatusInternalServerError)
+		return
+	}
+
+	m.mu.Lock()
+	m.codes[code] = authorization{
+		redirectURI: redirectURI.String(),
+		nonce:       q.Get("nonce"),
+		challenge:   q.Get("code_challenge"),
+	}
+	m.mu.Unlock()
+
+	values := redirectURI.Query()
+	values.Set("code", code)
+	values.Set("state", q.Get("state"))
+	redirectURI.RawQuery = values.Encode()
+
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (m *MockProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != m.ClientID || clientSecret != m.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// codes are single use
+	m.mu.Lock()
+	auth, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	if !ok || auth.redirectURI != r.PostForm.Get("redirect_uri") || oidc.S256Challenge(r.PostForm.Get("code_verifier")) != auth.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken, err := m.SignIDToken(oidc.Claims{
+		Nonce:         auth.nonce,
+		Email:         m.Identity.Email,
+		EmailVerified: m.Identity.EmailVerified,
+		Name:          m.Identity.Name,
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    strings.TrimSuffix(m.Issuer, "/"),
+			Subject:   m.Identity.Subject,
+			Audience:  jwt.ClaimStrings{m.ClientID},
+			IssuedAt:  jwt.At(now),
+			ExpiresAt: jwt.At(now.Add(5 * time.Minute)),
+		},
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "mock-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
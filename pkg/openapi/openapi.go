@@ -0,0 +1,304 @@
+// Package openapi builds an OpenAPI 3 document from the routes of the service and the go types
+// they read and answer with
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version of the OpenAPI specification the document follows
+const Version = "3.0.3"
+
+const (
+	// ContentTypeJSON media type of the json bodies
+	ContentTypeJSON = "application/json"
+	// ContentTypeMultipart media type of the file uploads
+	ContentTypeMultipart = "multipart/form-data"
+)
+
+var pathParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Document OpenAPI 3 document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	schemas  *schemas
+	envelope *Schema
+	tags     map[string]bool
+}
+
+// Info about the api
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server the api is served from
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups operations
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem operations of a path keyed by the lower case http method
+type PathItem map[string]*Operation
+
+// Operation of a path
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// Parameter of an operation, in path or query
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody of an operation
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// MediaType content of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Components reusable schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme how an operation authenticates
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Route an operation is built from
+type Route struct {
+	Method  string
+	Path    string
+	Tag     string
+	Summary string
+	// Security names of the security schemes the route requires, all of them
+	Security []string
+	// Payload value of the type the route casts the request into, read from the query string
+	// for the methods without a body, nil when it reads none
+	Payload interface{}
+	// Upload name of the multipart form field the route reads a file from
+	Upload string
+	// Result value of the type of the data of the success response, nil when it has none
+	Result interface{}
+	// Alternatives values of the other types the data of the success response may be of
+	Alternatives []interface{}
+	// Code status of the success response, 200 when zero
+	Code int
+	// Deprecated the route is to be removed
+	Deprecated bool
+}
+
+// New empty document
+func New(info Info) *Document {
+	d := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas:         map[string]*Schema{},
+			SecuritySchemes: map[string]SecurityScheme{},
+		},
+		tags: map[string]bool{},
+	}
+	d.schemas = newSchemas(d.Components.Schemas)
+
+	return d
+}
+
+// Schema of the type of v, the named structs are added to the components and referenced
+func (d *Document) Schema(v interface{}) *Schema {
+	return d.schemas.of(v)
+}
+
+// SecurityScheme adds the scheme routes refer to by name
+func (d *Document) SecurityScheme(name string, scheme SecurityScheme) {
+	d.Components.SecuritySchemes[name] = scheme
+}
+
+// Envelope sets the type every response is wrapped in, the data of the routes goes in its
+// dataField and fields overrides the schema of the fields typed loosely
+func (d *Document) Envelope(name string, v interface{}, dataField string, fields map[string]interface{}) {
+	s := d.schemas.inline(v)
+	for field, fv := range fields {
+		s.Properties[field] = d.schemas.of(fv)
+	}
+	d.Components.Schemas[name] = s
+	d.envelope = &Schema{Ref: ref(name)}
+	d.schemas.dataField = dataField
+}
+
+// Add the operation of r
+func (d *Document) Add(r Route) {
+	path := pathParam.ReplaceAllString(r.Path, "{$1}")
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+
+	op := &Operation{
+		Summary:     r.Summary,
+		OperationID: operationID(r.Method, path),
+		Responses:   map[string]Response{},
+		Deprecated:  r.Deprecated,
+	}
+
+	if r.Tag != "" {
+		op.Tags = []string{r.Tag}
+		if !d.tags[r.Tag] {
+			d.tags[r.Tag] = true
+			d.Tags = append(d.Tags, Tag{Name: r.Tag})
+		}
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(r.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+
+	if r.Payload != nil {
+		if hasBody(r.Method) {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{ContentTypeJSON: {Schema: d.schemas.of(r.Payload)}},
+			}
+		} else {
+			op.Parameters = append(op.Parameters, d.schemas.query(r.Payload)...)
+		}
+	}
+
+	if r.Upload != "" {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{ContentTypeMultipart: {Schema: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{r.Upload: {Type: "string", Format: "binary"}},
+				Required:   []string{r.Upload},
+			}}},
+		}
+	}
+
+	// the schemes of a requirement are all required
+	if len(r.Security) > 0 {
+		requirement := map[string][]string{}
+		for _, name := range r.Security {
+			requirement[name] = []string{}
+		}
+		op.Security = []map[string][]string{requirement}
+	}
+
+	code := r.Code
+	if code == 0 {
+		code = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(code)] = d.response(http.StatusText(code), d.data(r))
+	op.Responses["default"] = d.response("Error", nil)
+
+	(*item)[strings.ToLower(r.Method)] = op
+}
+
+// Sort orders the tags by name
+func (d *Document) Sort() {
+	sort.Slice(d.Tags, func(i, j int) bool { return d.Tags[i].Name < d.Tags[j].Name })
+}
+
+// data schema of the success response of r, one of the result and its alternatives when it has some
+func (d *Document) data(r Route) *Schema {
+	if r.Result == nil {
+		return nil
+	}
+
+	s := d.schemas.of(r.Result)
+	if len(r.Alternatives) == 0 {
+		return s
+	}
+
+	one := &Schema{OneOf: []*Schema{s}}
+	for _, v := range r.Alternatives {
+		one.OneOf = append(one.OneOf, d.schemas.of(v))
+	}
+
+	return one
+}
+
+// response with the data in the envelope
+func (d *Document) response(description string, data *Schema) Response {
+	var s *Schema
+	switch {
+	case d.envelope == nil && data == nil:
+		return Response{Description: description}
+	case d.envelope == nil:
+		s = data
+	case data == nil:
+		s = d.envelope
+	default:
+		s = &Schema{AllOf: []*Schema{d.envelope, {
+			Type:       "object",
+			Properties: map[string]*Schema{d.schemas.dataField: data},
+		}}}
+	}
+
+	return Response{Description: description, Content: map[string]MediaType{ContentTypeJSON: {Schema: s}}}
+}
+
+// hasBody reports whether the request of the method is read from the body
+func hasBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete
+}
+
+// operationID unique id of the operation, as in getUsersIdRoles
+func operationID(method string, path string) string {
+	b := strings.Builder{}
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '_' || r == '.'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,122 @@
+// Package openapi
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type base struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type node struct {
+	base
+	ID       uuid.UUID       `json:"id"`
+	Name     string          `json:"name,omitempty"`
+	Parent   *node           `json:"parent,omitempty"`
+	Children []node          `json:"children"`
+	Count    int64           `json:"count,string"`
+	Extra    json.RawMessage `json:"extra"`
+	Secret   string          `json:"-"`
+	internal string
+}
+
+type filter struct {
+	Search string `url:"search"`
+	Page   uint64 `url:"page"`
+}
+
+type envelope struct {
+	Code    int         `json:"-"`
+	Status  string      `json:"status,omitempty"`
+	Meta    interface{} `json:"meta,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+	private string
+}
+
+type meta struct {
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty"`
+}
+
+func TestSchema(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+
+	s := doc.Schema(&node{})
+	assert.Equal(t, "#/components/schemas/node", s.Ref)
+
+	n := doc.Components.Schemas["node"]
+	assert.Equal(t, "object", n.Type)
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, n.Properties["created_at"])
+	assert.Equal(t, &Schema{Type: "string", Format: "uuid"}, n.Properties["id"])
+	assert.Equal(t, "#/components/schemas/node", n.Properties["parent"].Ref)
+	assert.Equal(t, "#/components/schemas/node", n.Properties["children"].Items.Ref)
+	assert.Equal(t, "string", n.Properties["count"].Type)
+	assert.Equal(t, &Schema{}, n.Properties["extra"])
+	assert.NotContains(t, n.Properties, "Secret")
+	assert.NotContains(t, n.Properties, "internal")
+	assert.Len(t, n.Properties, 7)
+
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string", Nullable: true}}, doc.Schema([]*string{}))
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "boolean"}}, doc.Schema(map[string]bool{}))
+}
+
+func TestAdd(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+	doc.SecurityScheme("bearer", SecurityScheme{Type: "http", Scheme: "bearer"})
+	doc.Envelope("Response", envelope{}, "data", map[string]interface{}{"meta": meta{}})
+
+	doc.Add(Route{Method: http.MethodGet, Path: "/nodes", Tag: "nodes", Payload: filter{}, Result: []node{}})
+	doc.Add(Route{Method: http.MethodPut, Path: "/nodes/{id:[0-9]+}", Tag: "nodes", Security: []string{"bearer"}, Payload: node{}})
+	doc.Add(Route{Method: http.MethodDelete, Path: "/nodes/{id}", Tag: "nodes", Payload: filter{}})
+	doc.Add(Route{Method: http.MethodPost, Path: "/nodes/{id}/image", Tag: "images", Upload: "image", Code: http.StatusCreated, Deprecated: true})
+	doc.Add(Route{Method: http.MethodPost, Path: "/nodes/search", Tag: "nodes", Result: node{}, Alternatives: []interface{}{filter{}}})
+
+	assert.Equal(t, []Tag{{Name: "nodes"}, {Name: "images"}}, doc.Tags)
+	doc.Sort()
+	assert.Equal(t, []Tag{{Name: "images"}, {Name: "nodes"}}, doc.Tags)
+
+	env := doc.Components.Schemas["Response"]
+	assert.Len(t, env.Properties, 3)
+	assert.Equal(t, "#/components/schemas/meta", env.Properties["meta"].Ref)
+
+	list := (*doc.Paths["/nodes"])["get"]
+	assert.Equal(t, "getNodes", list.OperationID)
+	assert.Nil(t, list.RequestBody)
+	assert.Equal(t, []Parameter{
+		{Name: "search", In: "query", Schema: &Schema{Type: "string"}},
+		{Name: "page", In: "query", Schema: &Schema{Type: "integer", Format: "int64"}},
+	}, list.Parameters)
+	data := list.Responses["200"].Content[ContentTypeJSON].Schema.AllOf[1].Properties["data"]
+	assert.Equal(t, "#/components/schemas/node", data.Items.Ref)
+	assert.Equal(t, "#/components/schemas/Response", list.Responses["default"].Content[ContentTypeJSON].Schema.Ref)
+
+	update := (*doc.Paths["/nodes/{id}"])["put"]
+	assert.Equal(t, "putNodesId", update.OperationID)
+	assert.Equal(t, []Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}}, update.Parameters)
+	assert.Equal(t, "#/components/schemas/node", update.RequestBody.Content[ContentTypeJSON].Schema.Ref)
+	assert.Equal(t, []map[string][]string{{"bearer": {}}}, update.Security)
+	assert.Equal(t, "#/components/schemas/Response", update.Responses["200"].Content[ContentTypeJSON].Schema.Ref)
+
+	remove := (*doc.Paths["/nodes/{id}"])["delete"]
+	assert.Len(t, remove.Parameters, 1)
+	assert.Equal(t, "#/components/schemas/filter", remove.RequestBody.Content[ContentTypeJSON].Schema.Ref)
+
+	upload := (*doc.Paths["/nodes/{id}/image"])["post"]
+	assert.Contains(t, upload.Responses, "201")
+	assert.True(t, upload.Deprecated)
+	assert.False(t, update.Deprecated)
+	assert.Equal(t, "binary", upload.RequestBody.Content[ContentTypeMultipart].Schema.Properties["image"].Format)
+
+	search := (*doc.Paths["/nodes/search"])["post"]
+	one := search.Responses["200"].Content[ContentTypeJSON].Schema.AllOf[1].Properties["data"].OneOf
+	assert.Equal(t, []*Schema{{Ref: "#/components/schemas/node"}, {Ref: "#/components/schemas/filter"}}, one)
+
+	_, err := json.Marshal(doc)
+	assert.NoError(t, err)
+}
@@ -11,6 +11,13 @@ import (
 // const connStringTemplate = "postgres://%s:%s@%s:%d/%s?sslmode=disable"
 const connStringTemplate = "host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=%s"
 
+const (
+	// PoolWrite role of the pool the writes go to
+	PoolWrite = "write"
+	// PoolRead role of the pool the reads go to
+	PoolRead = "read"
+)
+
 type Config struct {
 	Host         string
 	Port         int
@@ -35,6 +42,8 @@ type Adapter interface {
 	Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	Ping(ctx context.Context) error
 	HealthCheck() error
+	// Pools connection pools by role, write and read
+	Pools() map[string]*sql.DB
 }
 
 type Transaction interface {
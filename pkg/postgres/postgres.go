@@ -88,3 +88,8 @@ func (d *postgres) Ping(ctx context.Context) error {
 func (d *postgres) HealthCheck() error {
 	return d.Ping(context.Background())
 }
+
+// Pools the single pool serving both the reads and the writes, as the write one
+func (d *postgres) Pools() map[string]*sql.DB {
+	return map[string]*sql.DB{PoolWrite: d.db.DB}
+}
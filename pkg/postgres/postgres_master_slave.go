@@ -153,6 +153,16 @@ func (d *postgreSQLMasterSlave) HealthCheck() error {
 
 }
 
+// Pools the write pool, and the read one when configured
+func (d *postgreSQLMasterSlave) Pools() map[string]*sql.DB {
+	pools := map[string]*sql.DB{PoolWrite: d.db.DB}
+	if d.dbRead != nil {
+		pools[PoolRead] = d.dbRead.DB
+	}
+
+	return pools
+}
+
 func (d *postgreSQLMasterSlave) selector() *sqlx.DB {
 	if d.dbRead != nil {
 		return d.dbRead
@@ -0,0 +1,90 @@
+// Package ratelimit limits the calls of a key with a sliding window
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Result outcome of a call counted by the limiter
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset time until the current fixed window ends
+	Reset time.Duration
+	// RetryAfter time until a call is allowed again, zero when the call was allowed
+	RetryAfter time.Duration
+}
+
+// Limiter allows a number of calls per window for each key. The window slides: the calls of the
+// previous fixed window are weighted by how much of it the sliding window still overlaps
+type Limiter struct {
+	store Store
+	now   func() time.Time
+}
+
+// NewLimiter initialize a limiter counting the calls in store
+func NewLimiter(store Store) *Limiter {
+	return &Limiter{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Allow counts a call of key against limit calls per window, rejected calls are counted as well
+// so a client that keeps calling stays limited
+func (l *Limiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	now := l.now().UnixNano()
+	size := int64(window)
+	current := now / size
+	elapsed := time.Duration(now % size)
+
+	count, err := l.store.Increment(ctx, fmt.Sprintf("%s:%d", key, current), 2*window)
+	if err != nil {
+		return Result{}, err
+	}
+
+	previous, err := l.store.Count(ctx, fmt.Sprintf("%s:%d", key, current-1))
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{
+		Limit: limit,
+		Reset: window - elapsed,
+	}
+
+	overlap := float64(window-elapsed) / float64(window)
+	estimate := float64(previous)*overlap + float64(count)
+	if estimate <= float64(limit) {
+		res.Allowed = true
+		res.Remaining = int(float64(limit) - estimate)
+		return res, nil
+	}
+
+	res.RetryAfter = retryAfter(limit, window, elapsed, previous, count)
+
+	return res, nil
+}
+
+// retryAfter time until the estimate with one more call drops to the limit, assuming no other call
+// is made meanwhile
+func retryAfter(limit int, window, elapsed time.Duration, previous, count int64) time.Duration {
+	// the previous window slides out until the end of the current one
+	if count < int64(limit) && previous > 0 {
+		left := float64(int64(limit)-count-1) / float64(previous)
+		return (window - elapsed) - time.Duration(left*float64(window))
+	}
+
+	// the current window becomes the previous one and has to slide out far enough
+	wait := window - elapsed
+	if count > 0 && limit > 0 {
+		wait += time.Duration((1 - float64(limit-1)/float64(count)) * float64(window))
+	} else {
+		wait += window
+	}
+
+	return wait
+}
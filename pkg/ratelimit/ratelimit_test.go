@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Increment(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	return 0, errors.New("store down")
+}
+
+func (failingStore) Count(ctx context.Context, key string) (int64, error) {
+	return 0, errors.New("store down")
+}
+
+func newTestLimiter(store Store, now *time.Time) *Limiter {
+	l := NewLimiter(store)
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestAllow(t *testing.T) {
+	ctx := context.Background()
+	window := time.Hour
+	now := time.Unix(0, 0).Add(1000 * window)
+	l := newTestLimiter(NewMemoryStore(), &now)
+
+	for i := 0; i < 3; i++ {
+		res, err := l.Allow(ctx, "ip:1", 3, window)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 2-i, res.Remaining)
+		assert.Equal(t, window, res.Reset)
+	}
+
+	res, err := l.Allow(ctx, "ip:1", 3, window)
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.True(t, res.RetryAfter > window)
+
+	// other keys are counted apart
+	res, err = l.Allow(ctx, "ip:2", 3, window)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestAllowSlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	window := time.Hour
+	now := time.Unix(0, 0).Add(1000 * window)
+	l := newTestLimiter(NewMemoryStore(), &now)
+
+	for i := 0; i < 4; i++ {
+		_, err := l.Allow(ctx, "ip:1", 4, window)
+		assert.NoError(t, err)
+	}
+
+	// half of the previous window still overlaps, it weighs 2 calls
+	now = now.Add(window + window/2)
+	for i := 0; i < 2; i++ {
+		res, err := l.Allow(ctx, "ip:1", 4, window)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+	}
+
+	res, err := l.Allow(ctx, "ip:1", 4, window)
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, window/2, res.Reset)
+
+	// a call fits again once the retry delay passed
+	now = now.Add(res.RetryAfter)
+	res, err = l.Allow(ctx, "ip:1", 4, window)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestAllowError(t *testing.T) {
+	now := time.Now()
+	l := newTestLimiter(failingStore{}, &now)
+
+	_, err := l.Allow(context.Background(), "ip:1", 1, time.Minute)
+	assert.Error(t, err)
+}
+
+func TestFallbackStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFallbackStore(failingStore{}, NewMemoryStore())
+
+	n, err := store.Increment(ctx, "key", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = store.Count(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
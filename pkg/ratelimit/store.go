@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"sharefood/pkg/cache"
+	"sharefood/pkg/logger"
+)
+
+// Store keeps the call counters of the limiter
+type Store interface {
+	// Increment adds a call to the counter of key, the counter expires exp after its first call
+	Increment(ctx context.Context, key string, exp time.Duration) (int64, error)
+	// Count calls counted under key, zero when there is none
+	Count(ctx context.Context, key string) (int64, error)
+}
+
+type cacheStore struct {
+	cache  cache.Cacher
+	prefix string
+}
+
+// NewCacheStore keeps the counters in the cache under prefix, they are shared by every instance
+func NewCacheStore(cache cache.Cacher, prefix string) Store {
+	return &cacheStore{cache: cache, prefix: prefix}
+}
+
+func (s *cacheStore) Increment(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	return s.cache.Increment(ctx, s.prefix+key, exp)
+}
+
+func (s *cacheStore) Count(ctx context.Context, key string) (int64, error) {
+	b, err := s.cache.Get(ctx, s.prefix+key)
+	if err != nil || len(b) == 0 {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(b), 10, 64)
+}
+
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+type memoryStore struct {
+	mu       sync.Mutex
+	counters map[string]memoryCounter
+	sweptAt  time.Time
+}
+
+// NewMemoryStore keeps the counters in the process, each instance limits on its own
+func NewMemoryStore() Store {
+	return &memoryStore{
+		counters: map[string]memoryCounter{},
+		sweptAt:  time.Now(),
+	}
+}
+
+func (s *memoryStore) Increment(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.expiresAt) {
+		c = memoryCounter{expiresAt: now.Add(exp)}
+	}
+
+	c.count++
+	s.counters[key] = c
+
+	return c.count, nil
+}
+
+func (s *memoryStore) Count(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[key]
+	if !ok || !time.Now().Before(c.expiresAt) {
+		return 0, nil
+	}
+
+	return c.count, nil
+}
+
+// sweep drops the expired counters, at most once a minute
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.sweptAt) < time.Minute {
+		return
+	}
+
+	for key, c := range s.counters {
+		if !now.Before(c.expiresAt) {
+			delete(s.counters, key)
+		}
+	}
+	s.sweptAt = now
+}
+
+type fallbackStore struct {
+	primary  Store
+	fallback Store
+}
+
+// NewFallbackStore counts in primary and in fallback while primary fails, so calls keep being
+// limited per instance when the shared store is unreachable
+func NewFallbackStore(primary Store, fallback Store) Store {
+	return &fallbackStore{primary: primary, fallback: fallback}
+}
+
+func (s *fallbackStore) Increment(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	n, err := s.primary.Increment(ctx, key, exp)
+	if err != nil {
+		logger.Warn(logger.MessageFormat("[rate-limit] counting in memory, store error %v", err))
+		return s.fallback.Increment(ctx, key, exp)
+	}
+
+	return n, nil
+}
+
+func (s *fallbackStore) Count(ctx context.Context, key string) (int64, error) {
+	n, err := s.primary.Count(ctx, key)
+	if err != nil {
+		return s.fallback.Count(ctx, key)
+	}
+
+	return n, nil
+}
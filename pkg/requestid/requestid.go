@@ -0,0 +1,41 @@
+// Package requestid carries the id correlating the logs, traces and response of a request
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying id
+func NewContext(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext id carried by ctx, false when there is none
+func FromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(ctxKey{}).(uuid.UUID)
+	return id, ok
+}
+
+// Get id carried by ctx, a new id when there is none so work outside of a request still gets one
+func Get(ctx context.Context) uuid.UUID {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+
+	return uuid.New()
+}
+
+// Parse the id sent by a client, a new id when it is missing or not a uuid. The 32 hex digits
+// form proxies generate is accepted as well
+func Parse(raw string) uuid.UUID {
+	id, err := uuid.Parse(raw)
+	if err != nil || id == uuid.Nil {
+		return uuid.New()
+	}
+
+	return id
+}
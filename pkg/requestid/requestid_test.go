@@ -0,0 +1,39 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	id := uuid.New()
+	ctx := NewContext(context.Background(), id)
+
+	got, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, id, got)
+	assert.Equal(t, id, Get(ctx))
+
+	assert.NotEqual(t, uuid.Nil, Get(context.Background()))
+}
+
+func TestParse(t *testing.T) {
+	id := uuid.New()
+	assert.Equal(t, id, Parse(id.String()))
+
+	// the form without dashes
+	assert.Equal(t, id, Parse(strings.ReplaceAll(id.String(), "-", "")))
+
+	for _, raw := range []string{"", "not-an-id", uuid.Nil.String()} {
+		parsed := Parse(raw)
+		assert.NotEqual(t, uuid.Nil, parsed, raw)
+		assert.NotEqual(t, raw, parsed.String(), raw)
+	}
+}
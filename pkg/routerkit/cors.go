@@ -0,0 +1,180 @@
+// Package router
+package routerkit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// CORS cross-origin requests the router allows
+type CORS struct {
+	// AllowedOrigins origins allowed, "*" allows any and "https://*.example.com" any subdomain.
+	// The origins only allowed by "*" are answered with a literal "*" and never with credentials
+	AllowedOrigins []string
+	// AllowedMethods methods allowed, defaults to the common ones
+	AllowedMethods []string
+	// AllowedHeaders request headers allowed, "*" allows any
+	AllowedHeaders []string
+	// ExposedHeaders response headers the browser lets the page read
+	ExposedHeaders []string
+	// AllowCredentials lets the browser send cookies and authorization
+	AllowCredentials bool
+	// MaxAge how long the browser caches a preflight answer
+	MaxAge time.Duration
+}
+
+var defaultCORSMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// WithCORS answers the preflights of the registered routes and sets the cors headers of the
+// requests coming from an allowed origin.
+func WithCORS(cors CORS) RouterOption {
+	return func(cfg *routerConfig) {
+		if len(cors.AllowedMethods) == 0 {
+			cors.AllowedMethods = defaultCORSMethods
+		}
+		cfg.cors = &cors
+	}
+}
+
+// corsHandler applies the cors settings in front of next
+func (r *Router) corsHandler(next http.Handler) http.Handler {
+	cors := r.config.cors
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+		allowed, listed := cors.allowsOrigin(origin)
+		if origin == "" || !allowed {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+
+		method := req.Header.Get("Access-Control-Request-Method")
+		if req.Method != http.MethodOptions || method == "" {
+			cors.setOrigin(h, origin, listed)
+			if len(cors.ExposedHeaders) > 0 {
+				h.Set("Access-Control-Expose-Headers", strings.Join(cors.ExposedHeaders, ", "))
+			}
+
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		// preflight, answered when a route is registered for the requested method
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+
+		headers := req.Header.Get("Access-Control-Request-Headers")
+		if !cors.allowsMethod(method) || !cors.allowsHeaders(headers) || !r.matchesMethod(req, method) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		cors.setOrigin(h, origin, listed)
+		h.Set("Access-Control-Allow-Methods", strings.Join(cors.AllowedMethods, ", "))
+		if headers != "" {
+			h.Set("Access-Control-Allow-Headers", headers)
+		}
+		if cors.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", strconv.Itoa(int(cors.MaxAge.Seconds())))
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// matchesMethod reports whether a route is registered for the path of req and method
+func (r *Router) matchesMethod(req *http.Request, method string) bool {
+	probe := *req
+	probe.Method = method
+
+	var match mux.RouteMatch
+	return r.Router.Match(&probe, &match) && match.MatchErr == nil
+}
+
+// setOrigin echoes the origin when it is listed, any other origin gets "*" and no credentials
+// so allowing any origin never lets every site send requests with the cookies of the user
+func (c *CORS) setOrigin(h http.Header, origin string, listed bool) {
+	if !listed {
+		h.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+
+	h.Set("Access-Control-Allow-Origin", origin)
+	if c.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// allowsOrigin reports whether the origin is allowed, and whether it is listed by itself or by
+// its parent domain rather than only allowed by "*"
+func (c *CORS) allowsOrigin(origin string) (allowed bool, listed bool) {
+	origin = strings.ToLower(origin)
+	for _, o := range c.AllowedOrigins {
+		o = strings.ToLower(o)
+		if o == "*" {
+			allowed = true
+			continue
+		}
+
+		if o == origin {
+			return true, true
+		}
+
+		// wildcard subdomain, the scheme and the parent domain must match
+		if i := strings.Index(o, "*."); i >= 0 {
+			prefix, suffix := o[:i], o[i+1:]
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+				len(origin) > len(prefix)+len(suffix) {
+				return true, true
+			}
+		}
+	}
+
+	return allowed, false
+}
+
+func (c *CORS) allowsMethod(method string) bool {
+	for _, allowed := range c.AllowedMethods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *CORS) allowsHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		allowed := false
+		for _, h := range c.AllowedHeaders {
+			if h == "*" || strings.EqualFold(h, header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	return true
+}
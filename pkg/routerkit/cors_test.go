@@ -0,0 +1,119 @@
+package routerkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCORSRouter() *Router {
+	r := NewRouter(WithCORS(CORS{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.sharefood.id"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}))
+	r.HandleFunc("/foods/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet, http.MethodPut)
+
+	return r
+}
+
+func preflight(r *Router, origin, method, headers string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/foods/1", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSPreflight(t *testing.T) {
+	r := newCORSRouter()
+
+	w := preflight(r, "https://app.example.com", http.MethodPut, "authorization, content-type")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "authorization, content-type", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+
+	w = preflight(r, "https://admin.sharefood.id", http.MethodGet, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// no route for the method, origin or header not allowed
+	testCase := []struct{ origin, method, headers string }{
+		{"https://app.example.com", http.MethodDelete, ""},
+		{"https://sharefood.id", http.MethodGet, ""},
+		{"https://evil.com", http.MethodGet, ""},
+		{"https://app.example.com", http.MethodGet, "X-Custom"},
+	}
+	for _, tc := range testCase {
+		w := preflight(r, tc.origin, tc.method, tc.headers)
+		assert.NotEqual(t, http.StatusNoContent, w.Code, tc)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), tc)
+	}
+}
+
+func TestCORSRequest(t *testing.T) {
+	r := newCORSRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/foods/1", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-ID", w.Header().Get("Access-Control-Expose-Headers"))
+
+	req = httptest.NewRequest(http.MethodGet, "/foods/1", nil)
+	req.Header.Set("Origin", "https://evil.com")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	r := NewRouter(WithCORS(CORS{
+		AllowedOrigins:   []string{"*", "https://app.example.com"},
+		AllowCredentials: true,
+	}))
+	r.HandleFunc("/foods/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet)
+
+	request := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/foods/1", nil)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// only allowed by "*", never with credentials
+	w := request("https://evil.com")
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+
+	w = preflight(r, "https://evil.com", http.MethodGet, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+
+	// listed, echoed with credentials
+	w = request("https://app.example.com")
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -2,16 +2,19 @@
 package routerkit
 
 import (
+	"context"
 	"net/http"
 	
 	"github.com/gorilla/mux"
-	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Router registers routes to be matched and dispatches a handler.
 type Router struct {
 	*mux.Router
 	config *routerConfig
+	// subrouters of the api versions, in the order they were started
+	versions []version
 }
 
 // StrictSlash defines the trailing slash behavior for new routes. The initial
@@ -81,9 +84,9 @@ func NewRouter(opts ...RouterOption) *Router {
 // all the incoming requests to the underlying multiplexer
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	var (
-		match    mux.RouteMatch
-		spanopts []opentracing.StartSpanOption
-		route    = "unknown"
+		match mux.RouteMatch
+		attrs []attribute.KeyValue
+		route = "unknown"
 	)
 	// get the resource associated to this request
 	if r.Match(req, &match) && match.Route != nil {
@@ -91,10 +94,33 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			route = r
 		}
 		if h, err := match.Route.GetHostTemplate(); err == nil {
-			spanopts = append(spanopts, opentracing.Tag{Key: "mux.host", Value: h})
+			attrs = append(attrs, attribute.String("mux.host", h))
 		}
 	}
-	spanopts = append(spanopts, r.config.spanOpts...)
+	attrs = append(attrs, attribute.String("http.route", route))
+	attrs = append(attrs, r.config.spanAttrs...)
 	resource := req.Method + " " + route
-	TraceAndServe(r.Router, w, req, r.config.serviceName, resource, spanopts...)
+
+	var h http.Handler = r.Router
+	if r.config.cors != nil {
+		h = r.corsHandler(h)
+	}
+	for i := len(r.config.middlewares) - 1; i >= 0; i-- {
+		h = r.config.middlewares[i](h)
+	}
+
+	req = req.WithContext(context.WithValue(req.Context(), routeKey{}, route))
+	TraceAndServe(h, w, req, r.config.serviceName, resource, r.config.trustedProxies, attrs...)
+}
+
+type routeKey struct{}
+
+// Route path template of the route matching the request, "unknown" when none matched.
+// It is known to the middlewares of the router before the route is dispatched
+func Route(r *http.Request) string {
+	if route, ok := r.Context().Value(routeKey{}).(string); ok {
+		return route
+	}
+
+	return "unknown"
 }
@@ -2,12 +2,19 @@
 package routerkit
 
 import (
-	"github.com/opentracing/opentracing-go"
+	"net"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type routerConfig struct {
 	serviceName string
-	spanOpts    []opentracing.StartSpanOption // additional span options to be applied
+	spanAttrs   []attribute.KeyValue // additional span attributes to be applied
+	middlewares []func(http.Handler) http.Handler
+	cors        *CORS
+	// proxies the trace context is continued from
+	trustedProxies []*net.IPNet
 }
 
 // RouterOption represents an option that can be passed to NewRouter.
@@ -24,10 +31,26 @@ func WithServiceName(name string) RouterOption {
 	}
 }
 
-// WithSpanOptions applies the given set of options to the spans started
+// WithSpanAttributes applies the given set of attributes to the spans started
 // by the router.
-func WithSpanOptions(opts ...opentracing.StartSpanOption) RouterOption {
+func WithSpanAttributes(attrs ...attribute.KeyValue) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.spanAttrs = attrs
+	}
+}
+
+// WithTrustedProxies continues the trace propagated in the traceparent header only when the request
+// comes from one of the given proxies, the requests of any other peer start a trace of their own
+func WithTrustedProxies(nets ...*net.IPNet) RouterOption {
 	return func(cfg *routerConfig) {
-		cfg.spanOpts = opts
+		cfg.trustedProxies = append(cfg.trustedProxies, nets...)
 	}
-}
\ No newline at end of file
+}
+
+// WithMiddlewares wraps every request, matched or not, with the given middlewares.
+// The first one is the outermost, they run within the request span.
+func WithMiddlewares(mws ...func(http.Handler) http.Handler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.middlewares = append(cfg.middlewares, mws...)
+	}
+}
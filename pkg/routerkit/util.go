@@ -2,32 +2,38 @@
 package routerkit
 
 import (
-	"fmt"
+	"net"
 	"net/http"
-	"strconv"
 
-	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"sharefood/pkg/tracer"
+	"sharefood/pkg/util"
 )
 
-// TraceAndServe will apply tracing to the given http.Handler using the passed tracer under the given service and resource.
-func TraceAndServe(h http.Handler, w http.ResponseWriter, r *http.Request, service, resource string, spanopts ...opentracing.StartSpanOption) {
-	opts := append([]opentracing.StartSpanOption{
-		opentracing.Tag{Key: "span.type", Value: "web"},
-		opentracing.Tag{Key: "service.name", Value: service},
-		opentracing.Tag{Key: "resource.name", Value: resource},
-		opentracing.Tag{Key: "http.method", Value: r.Method},
-		opentracing.Tag{Key: "http.url", Value: r.URL.Path},
-	}, spanopts...)
-
-	spanCtx, err := opentracing.GlobalTracer().Extract(
-		opentracing.HTTPHeaders,
-		opentracing.HTTPHeadersCarrier(r.Header))
-	if err == nil {
-		opts = append(opts, opentracing.ChildOf(spanCtx))
+// TraceAndServe will apply tracing to the given http.Handler under the given service and resource.
+// The trace propagated in the traceparent header is continued only when the request comes from one of
+// the trusted proxies, any client could otherwise pick the trace its requests are recorded in.
+func TraceAndServe(h http.Handler, w http.ResponseWriter, r *http.Request, service, resource string, trusted []*net.IPNet, attrs ...attribute.KeyValue) {
+	attrs = append([]attribute.KeyValue{
+		attribute.String("service.name", service),
+		attribute.String("resource.name", resource),
+		semconv.HTTPMethodKey.String(r.Method),
+		semconv.HTTPTargetKey.String(r.URL.Path),
+	}, attrs...)
+
+	ctx := r.Context()
+	opts := []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...)}
+	if util.TrustedPeer(r, trusted) {
+		ctx = tracer.Extract(ctx, r.Header)
+	} else {
+		opts = append(opts, trace.WithNewRoot())
 	}
 
-	span, ctx := opentracing.StartSpanFromContext(r.Context(), "http.request", opts...)
-	defer span.Finish()
+	ctx, span := tracer.Tracer().Start(ctx, resource, opts...)
+	defer span.End()
 
 	w = wrapResponseWriter(w, span)
 
@@ -43,7 +49,7 @@ func TraceAndServe(h http.Handler, w http.ResponseWriter, r *http.Request, servi
 //
 // This code is generated because we have to account for all the permutations
 // of the interfaces.
-func wrapResponseWriter(w http.ResponseWriter, span opentracing.Span) http.ResponseWriter {
+func wrapResponseWriter(w http.ResponseWriter, span trace.Span) http.ResponseWriter {
 	hFlusher, okFlusher := w.(http.Flusher)
 	hPusher, okPusher := w.(http.Pusher)
 	hCloseNotifier, okCloseNotifier := w.(http.CloseNotifier)
@@ -153,11 +159,11 @@ func wrapResponseWriter(w http.ResponseWriter, span opentracing.Span) http.Respo
 // intercept and store the status of a request.
 type responseWriter struct {
 	http.ResponseWriter
-	span   opentracing.Span
+	span   trace.Span
 	status int
 }
 
-func newResponseWriter(w http.ResponseWriter, span opentracing.Span) *responseWriter {
+func newResponseWriter(w http.ResponseWriter, span trace.Span) *responseWriter {
 	return &responseWriter{w, span, 0}
 }
 
@@ -176,8 +182,6 @@ func (w *responseWriter) Write(b []byte) (int, error) {
 func (w *responseWriter) WriteHeader(status int) {
 	w.ResponseWriter.WriteHeader(status)
 	w.status = status
-	w.span.SetTag("http.status_code", strconv.Itoa(status))
-	if status >= 500 && status < 600 {
-		w.span.SetTag("error", fmt.Errorf("%d: %s", status, http.StatusText(status)))
-	}
+	w.span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+	w.span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer))
 }
\ No newline at end of file
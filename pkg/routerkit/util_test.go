@@ -0,0 +1,57 @@
+package routerkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"sharefood/pkg/util"
+)
+
+func TestTraceAndServeTrustedProxies(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	trusted, err := util.ParseCIDRs([]string{"10.0.0.0/8"})
+	assert.NoError(t, err)
+
+	r := NewRouter(WithTrustedProxies(trusted...))
+	r.HandleFunc("/foods", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testCases := map[string]struct {
+		remoteAddr string
+		continued  bool
+	}{
+		"test trusted proxy": {
+			remoteAddr: "10.1.2.3:4567",
+			continued:  true,
+		},
+		"test untrusted peer": {
+			remoteAddr: "203.0.113.7:4567",
+			continued:  false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/foods", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.Equal(t, tc.continued, span.SpanContext().TraceID().String() == "4bf92f3577b34da6a3ce929d0e0e4736")
+			assert.Equal(t, tc.continued, span.Parent().IsValid())
+		})
+	}
+}
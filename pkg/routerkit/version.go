@@ -0,0 +1,114 @@
+// Package router
+package routerkit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type version struct {
+	name   string
+	router *mux.Router
+}
+
+// RouteInfo method and path template of a registered route
+type RouteInfo struct {
+	Method string
+	Path   string
+}
+
+// Version returns the subrouter of the api version, the routes registered on it are served
+// under /name and listed by Routes(name)
+func (r *Router) Version(name string) *mux.Router {
+	for _, v := range r.versions {
+		if v.name == name {
+			return v.router
+		}
+	}
+
+	sub := r.PathPrefix("/" + name).Subrouter()
+	r.versions = append(r.versions, version{name: name, router: sub})
+
+	return sub
+}
+
+// Versions names of the api versions, in the order they were started
+func (r *Router) Versions() []string {
+	names := make([]string, 0, len(r.versions))
+	for _, v := range r.versions {
+		names = append(names, v.name)
+	}
+
+	return names
+}
+
+// Routes registered under the api version, the unversioned ones when name is empty. Path
+// prefixes without methods, such as the ones of the subrouters, are not listed
+func (r *Router) Routes(name string) []RouteInfo {
+	routes := []RouteInfo{}
+	r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		if r.versionOf(path) != name {
+			return nil
+		}
+
+		for _, method := range methods {
+			routes = append(routes, RouteInfo{Method: method, Path: path})
+		}
+
+		return nil
+	})
+
+	return routes
+}
+
+// versionOf the route of the path template, empty when unversioned
+func (r *Router) versionOf(path string) string {
+	first := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	for _, v := range r.versions {
+		if v.name == first {
+			return v.name
+		}
+	}
+
+	return ""
+}
+
+// Deprecation of a route, advertised on its responses
+type Deprecation struct {
+	// At the route is deprecated from
+	At time.Time
+	// Sunset the route is removed after, none when zero
+	Sunset time.Time
+	// Link to the documentation of the deprecation, none when empty
+	Link string
+}
+
+// Deprecated wraps h advertising the deprecation of its route, in the Deprecation (RFC 9745)
+// and Sunset (RFC 8594) headers
+func Deprecated(h http.Handler, d Deprecation) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "@"+strconv.FormatInt(d.At.Unix(), 10))
+		if !d.Sunset.IsZero() {
+			w.Header().Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.Link != "" {
+			w.Header().Add("Link", "<"+d.Link+`>; rel="deprecation"; type="text/html"`)
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,69 @@
+package routerkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ok(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestVersionRoutes(t *testing.T) {
+	r := NewRouter()
+	root := r.PathPrefix("/").Subrouter()
+	root.HandleFunc("/liveness", ok).Methods(http.MethodGet)
+	root.HandleFunc("/foods", ok).Methods(http.MethodGet)
+
+	v1 := r.Version("v1")
+	v1.HandleFunc("/foods", ok).Methods(http.MethodGet, http.MethodPost)
+	matched := ""
+	v1.HandleFunc("/foods/{id}", func(w http.ResponseWriter, r *http.Request) {
+		matched = Route(r)
+	}).Methods(http.MethodGet)
+	r.Version("v2").HandleFunc("/foods", ok).Methods(http.MethodGet)
+
+	assert.Equal(t, v1, r.Version("v1"))
+	assert.Equal(t, []string{"v1", "v2"}, r.Versions())
+
+	assert.Equal(t, []RouteInfo{
+		{Method: http.MethodGet, Path: "/v1/foods"},
+		{Method: http.MethodPost, Path: "/v1/foods"},
+		{Method: http.MethodGet, Path: "/v1/foods/{id}"},
+	}, r.Routes("v1"))
+	assert.Equal(t, []RouteInfo{{Method: http.MethodGet, Path: "/v2/foods"}}, r.Routes("v2"))
+	assert.Equal(t, []RouteInfo{
+		{Method: http.MethodGet, Path: "/liveness"},
+		{Method: http.MethodGet, Path: "/foods"},
+	}, r.Routes(""))
+
+	// the root subrouter matching first does not shadow the versions
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/foods/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/v1/foods/{id}", matched)
+}
+
+func TestDeprecated(t *testing.T) {
+	at := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 5, 1, 0, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+
+	w := httptest.NewRecorder()
+	Deprecated(http.HandlerFunc(ok), Deprecation{At: at, Sunset: sunset, Link: "https://docs.sharefood.id/v1"}).
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/foods", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@1793491200", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 30 Apr 2027 17:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `<https://docs.sharefood.id/v1>; rel="deprecation"; type="text/html"`, w.Header().Get("Link"))
+
+	w = httptest.NewRecorder()
+	Deprecated(http.HandlerFunc(ok), Deprecation{At: at}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/foods", nil))
+	assert.Equal(t, "@1793491200", w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+	assert.Empty(t, w.Header().Get("Link"))
+}
@@ -0,0 +1,36 @@
+// Package sms
+package sms
+
+import (
+	"context"
+
+	"sharefood/pkg/logger"
+)
+
+// Sender defines the minimum interface for a text message delivery system.
+type Sender interface {
+	// Send delivers text to the phone number.
+	Send(ctx context.Context, phoneNumber, text string) error
+}
+
+// Compile time check to verify implements the Sender interface.
+var _ Sender = (*logSender)(nil)
+
+// logSender is a Sender stub printing every message into the log,
+// intended for local development and testing purpose
+type logSender struct{}
+
+// NewLogSender creates a Sender stub
+func NewLogSender() Sender {
+	return &logSender{}
+}
+
+// Send prints the message
+func (s *logSender) Send(ctx context.Context, phoneNumber, text string) error {
+	logger.InfoWithContext(ctx, text,
+		logger.EventName("sms_outbox"),
+		logger.Any("to", phoneNumber),
+	)
+
+	return nil
+}
@@ -0,0 +1,116 @@
+// Package tracer
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+const (
+	// ExporterOTLP exports the spans to an OTLP collector over http
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes the spans to stdout, for local development
+	ExporterStdout = "stdout"
+)
+
+// Config of the tracer provider
+type Config struct {
+	ServiceName string
+	Env         string
+	// Exporter otlp or stdout
+	Exporter string
+	// Endpoint host:port of the OTLP collector, OTEL_EXPORTER_OTLP_ENDPOINT is used when empty
+	Endpoint string
+	// Insecure sends the spans to the collector without tls
+	Insecure bool
+	// SampleRatio share of the traces started here which are sampled, the decision of the caller is kept
+	SampleRatio float64
+}
+
+var (
+	mu       sync.Mutex
+	provider *sdktrace.TracerProvider
+)
+
+func init() {
+	// the trace context is propagated even without exporting the spans
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// Setup sets the global tracer provider exporting the spans, call Shutdown to flush them on exit
+func Setup(ctx context.Context, cfg Config) error {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch cfg.Exporter {
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP, "":
+		opts := []otlptracehttp.Option{}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		err = fmt.Errorf("unknown trace exporter %s", cfg.Exporter)
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(cfg.ServiceName),
+		semconv.DeploymentEnvironmentKey.String(cfg.Env),
+	))
+	if err != nil {
+		return err
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(tp)
+
+	mu.Lock()
+	provider = tp
+	mu.Unlock()
+
+	return nil
+}
+
+// Shutdown flushes the spans not exported yet and stops the provider set by Setup
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if provider == nil {
+		return nil
+	}
+
+	err := provider.Shutdown(ctx)
+	provider = nil
+
+	return err
+}
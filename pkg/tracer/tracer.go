@@ -5,14 +5,19 @@ package tracer
 import (
 	"context"
 	"fmt"
-
-	"github.com/opentracing/opentracing-go"
-	otext "github.com/opentracing/opentracing-go/ext"
-	otlog "github.com/opentracing/opentracing-go/log"
-
-	"sharefood/pkg/util"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// instrumentationName name of the spans started through this package
+const instrumentationName = "sharefood/pkg/tracer"
+
 type Option struct {
 	TagKey string
 	Value  string
@@ -32,59 +37,87 @@ func WithResourceNameOptions(value string) Option {
 	}
 }
 
+// Tracer the tracer the spans are started with, from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
 // SpanStart starts a new query span from ctx, then returns a new context with the new span.
 func SpanStart(ctx context.Context, eventName string) context.Context {
-	_, ctx = opentracing.StartSpanFromContext(ctx, eventName)
+	ctx, _ = Tracer().Start(ctx, eventName)
 	return ctx
 }
 
 // SpanFinish finishes the span associated with ctx.
 func SpanFinish(ctx context.Context) {
-	if span := opentracing.SpanFromContext(ctx); span != nil {
-		span.Finish()
-	}
+	trace.SpanFromContext(ctx).End()
 }
 
-// SpanError adds an error to the span associated with ctx.
+// SpanError adds an error to the span associated with ctx, a nil error is ignored.
 func SpanError(ctx context.Context, err error) {
-	if span := opentracing.SpanFromContext(ctx); span != nil {
-		otext.Error.Set(span, true)
-		span.LogFields(otlog.String("event", "error"), otlog.Error(err))
+	if err == nil {
+		return
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// SpanTag sets a tag on the span associated with ctx.
+func SpanTag(ctx context.Context, key string, value interface{}) {
+	trace.SpanFromContext(ctx).SetAttributes(Attribute(key, value))
+}
+
+// Attribute span attribute of value, the values of the types the spans do not support are formatted
+func Attribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
 	}
 }
 
 func SpanStartWithOption(ctx context.Context, eventName string, opts ...Option) context.Context {
+	return spanStart(ctx, eventName, trace.SpanKindInternal, opts...)
+}
 
-	var spOptions []opentracing.StartSpanOption
+// DBSpanStartWithOption starts the client span of a database call
+func DBSpanStartWithOption(ctx context.Context, dbName, eventName string, opts ...Option) context.Context {
+	opts = append(opts, WithOptions(string(semconv.DBNameKey), dbName))
 
-	for x := 0; x < len(opts); x++ {
-		if util.InArray(opts[x].TagKey, []string{
-			"span.type",
-			"service.name",
-			"resource.name",
-		}) {
-			spOptions = append(spOptions, opentracing.Tag{Key: opts[x].TagKey, Value: opts[x].Value})
-		}
-	}
+	return spanStart(ctx, eventName, trace.SpanKindClient, opts...)
+}
 
-	sp, ctx := opentracing.StartSpanFromContext(ctx, eventName, spOptions...)
-	for i := 0; i < len(opts); i++ {
-		sp.SetTag(opts[i].TagKey, opts[i].Value)
+func spanStart(ctx context.Context, eventName string, kind trace.SpanKind, opts ...Option) context.Context {
+	attrs := make([]attribute.KeyValue, 0, len(opts))
+	for _, opt := range opts {
+		key := opt.TagKey
+		if key == "sql.query" {
+			key = string(semconv.DBStatementKey)
+		}
+		attrs = append(attrs, attribute.String(key, opt.Value))
 	}
 
+	ctx, _ = Tracer().Start(ctx, eventName, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
 	return ctx
 }
 
-func DBSpanStartWithOption(ctx context.Context, dbName, eventName string, opts ...Option) context.Context {
-
-	svcName := fmt.Sprintf("%s.%s", "mysql", dbName)
-	opts = append(opts,
-		WithOptions("db.type", "sql"),
-		//WithOptions("span.type", "db"),
-		WithOptions("span.kind", "clients"),
-		WithOptions("peer.service", svcName),
-		WithOptions("service.name", svcName),
-	)
+// Extract the trace context the caller propagated in the W3C traceparent header
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
 
-	return SpanStartWithOption(ctx, eventName, opts...)
+// Inject the trace context of ctx in the W3C traceparent header, for the service called
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
 }
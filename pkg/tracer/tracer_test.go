@@ -0,0 +1,75 @@
+// Package tracer
+package tracer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpanPropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	in := http.Header{}
+	in.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx := SpanStart(Extract(context.Background(), in), "get_food")
+	SpanTag(ctx, "request_id", "abc")
+	SpanError(ctx, errors.New("not found"))
+
+	out := http.Header{}
+	Inject(ctx, out)
+	SpanFinish(ctx)
+
+	// the call out continues the trace of the caller, under the span of the service
+	parts := strings.Split(out.Get("traceparent"), "-")
+	assert.Len(t, parts, 4)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", parts[1])
+	assert.NotEqual(t, "00f067aa0ba902b7", parts[2])
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "get_food", spans[0].Name())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	assert.Equal(t, "Error", spans[0].Status().Code.String())
+	assert.Len(t, spans[0].Events(), 1)
+}
+
+func TestDBSpanStartWithOption(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx := DBSpanStartWithOption(context.Background(), "sharefood", "fetch_row", WithOptions("sql.query", "SELECT 1"))
+	SpanFinish(ctx)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	attrs := map[string]string{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, "SELECT 1", attrs["db.statement"])
+	assert.Equal(t, "sharefood", attrs["db.name"])
+}
+
+func TestSpanErrorNil(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx := SpanStart(context.Background(), "get_food")
+	assert.NotPanics(t, func() { SpanError(ctx, nil) })
+	SpanFinish(ctx)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "Unset", spans[0].Status().Code.String())
+	assert.Len(t, spans[0].Events(), 0)
+}
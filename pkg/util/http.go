@@ -0,0 +1,102 @@
+// Package util
+package util
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type clientIPKey struct{}
+
+// WithClientIP returns a copy of ctx carrying the client address resolved for the request
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP address of the client, the one resolved for the request when there is one. Otherwise the
+// peer the request came from, forwarding headers are not trusted since any client can set them
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+
+	return peerIP(r)
+}
+
+// ForwardedClientIP address of the client behind the trusted proxies. X-Forwarded-For is only read when
+// the peer is a trusted proxy, from the right so the addresses the client prepended are skipped
+func ForwardedClientIP(r *http.Request, trusted []*net.IPNet) string {
+	ip := peerIP(r)
+	if !ContainsIP(trusted, ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+
+		ip = hop
+		if !ContainsIP(trusted, ip) {
+			break
+		}
+	}
+
+	return ip
+}
+
+// TrustedPeer whether the peer the request came from is one of the trusted proxies
+func TrustedPeer(r *http.Request, trusted []*net.IPNet) bool {
+	return ContainsIP(trusted, peerIP(r))
+}
+
+// ParseCIDRs parses addresses and cidr ranges, an address is a range of itself
+func ParseCIDRs(values []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(values))
+	for _, v := range values {
+		if !strings.Contains(v, "/") {
+			if ip := net.ParseIP(v); ip != nil && ip.To4() != nil {
+				v += "/32"
+			} else {
+				v += "/128"
+			}
+		}
+
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
+
+func peerIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// ContainsIP reports whether ip is in one of the ranges
+func ContainsIP(nets []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, n := range nets {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
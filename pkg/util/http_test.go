@@ -0,0 +1,76 @@
+// Package util
+package util
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	testCase := map[string]string{
+		"10.0.0.1:52100":   "10.0.0.1",
+		"[::1]:52100":      "::1",
+		"10.0.0.2":         "10.0.0.2",
+		"unix-socket-peer": "unix-socket-peer",
+	}
+
+	for remoteAddr, expected := range testCase {
+		r := &http.Request{RemoteAddr: remoteAddr, Header: http.Header{}}
+		r.Header.Set("X-Forwarded-For", "1.2.3.4")
+		assert.Equal(t, expected, ClientIP(r), remoteAddr)
+	}
+}
+
+func TestClientIPResolved(t *testing.T) {
+	r := &http.Request{RemoteAddr: "10.0.0.1:52100", Header: http.Header{}}
+	r = r.WithContext(WithClientIP(r.Context(), "1.2.3.4"))
+
+	assert.Equal(t, "1.2.3.4", ClientIP(r))
+}
+
+func TestForwardedClientIP(t *testing.T) {
+	trusted, err := ParseCIDRs([]string{"10.0.0.0/8", "192.168.1.1"})
+	assert.NoError(t, err)
+
+	testCase := []struct {
+		remoteAddr string
+		forwarded  []string
+		expected   string
+	}{
+		// untrusted peer, the header is ignored
+		{"8.8.8.8:1000", []string{"1.2.3.4"}, "8.8.8.8"},
+		{"10.0.0.1:1000", nil, "10.0.0.1"},
+		{"10.0.0.1:1000", []string{"1.2.3.4"}, "1.2.3.4"},
+		// the address the client prepended is skipped
+		{"10.0.0.1:1000", []string{"6.6.6.6, 1.2.3.4"}, "1.2.3.4"},
+		{"10.0.0.1:1000", []string{"1.2.3.4, 192.168.1.1"}, "1.2.3.4"},
+		{"10.0.0.1:1000", []string{"1.2.3.4", "10.0.0.2"}, "1.2.3.4"},
+		// chain of trusted proxies only, the farthest one is the client
+		{"10.0.0.1:1000", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{"10.0.0.1:1000", []string{"garbage"}, "10.0.0.1"},
+	}
+
+	for _, tc := range testCase {
+		r := &http.Request{RemoteAddr: tc.remoteAddr, Header: http.Header{}}
+		for _, v := range tc.forwarded {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		assert.Equal(t, tc.expected, ForwardedClientIP(r, trusted), tc)
+	}
+
+	_, err = ParseCIDRs([]string{"not-an-ip"})
+	assert.Error(t, err)
+}
+
+func TestContainsIP(t *testing.T) {
+	nets, err := ParseCIDRs([]string{"10.0.0.0/8", "2001:db8::1"})
+	assert.NoError(t, err)
+
+	assert.True(t, ContainsIP(nets, "10.1.2.3"))
+	assert.True(t, ContainsIP(nets, "2001:db8::1"))
+	assert.False(t, ContainsIP(nets, "11.0.0.1"))
+	assert.False(t, ContainsIP(nets, "not-an-ip"))
+	assert.False(t, ContainsIP(nil, "10.1.2.3"))
+}
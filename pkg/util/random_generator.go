@@ -3,7 +3,10 @@ package util
 
 import (
 	"bytes"
+	crand "crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"time"
 )
@@ -97,3 +100,28 @@ func GenerateRandomBytesMask(letterBytes string, n int) string {
 
 	return string(b)
 }
+
+// GenerateSecureToken generate hex encoded token from n cryptographically secure random bytes
+func GenerateSecureToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// GenerateSecureNumberString generate cryptographically secure random string number
+func GenerateSecureNumberString(n int) (string, error) {
+	b := make([]byte, n)
+	max := big.NewInt(int64(len(letterBytes)))
+	for i := range b {
+		idx, err := crand.Int(crand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = letterBytes[idx.Int64()]
+	}
+
+	return string(b), nil
+}
@@ -1,7 +1,10 @@
 // Package util
 package util
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 const (
 	success = "\u2713"
@@ -104,4 +107,44 @@ func TestGenerateAppID(t *testing.T) {
 	}
 }
 
+func TestGenerateSecureToken(t *testing.T) {
+	t.Parallel()
+	t.Log("Test generation of secure token")
+	{
+		a, err := GenerateSecureToken(32)
+		if err != nil {
+			t.Fatalf("%s expected no error, got %v", failed, err)
+		}
+
+		b, _ := GenerateSecureToken(32)
 
+		if len(a) != 64 {
+			t.Errorf("%s expected token length 64, got %d", failed, len(a))
+		} else {
+			t.Logf("%s expected token length 64", success)
+		}
+
+		if a == b {
+			t.Errorf("%s expected no two tokens have the same value", failed)
+		} else {
+			t.Logf("%s expected no two tokens have the same value", success)
+		}
+	}
+}
+
+func TestGenerateSecureNumberString(t *testing.T) {
+	t.Parallel()
+	t.Log("Test generation of secure number string")
+	{
+		s, err := GenerateSecureNumberString(6)
+		if err != nil {
+			t.Fatalf("%s expected no error, got %v", failed, err)
+		}
+
+		if len(s) != 6 || strings.Trim(s, letterBytes) != "" {
+			t.Errorf("%s expected 6 digits, got %s", failed, s)
+		} else {
+			t.Logf("%s expected 6 digits", success)
+		}
+	}
+}
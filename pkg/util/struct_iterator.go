@@ -528,7 +528,6 @@ func StructToQueryDelete(where interface{}, tableName, tag string, soft bool) (s
 	q := fmt.Sprintf(`DELETE FROM %s `, tableName)
 
 	if soft {
-		fmt.Sprintf(`UPDATE %s SET deleted_at = ?`, tableName)
 		vals = append([]interface{}{time.Now().Format(layoutDateTimeFormat)}, vals...)
 	}
 